@@ -0,0 +1,156 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pborman/getopt"
+)
+
+// These global variables hold build information. The Build variable will be
+// populated by the Makefile and uses the Git Head hash as its identifier.
+// These variables are used in the console output for --version and --help.
+var (
+	Version = "0.1.0"
+	Build   string
+)
+
+// These are the exit codes. A playbook that does not verify, is not formatted
+// or is different is a failure, as is a file that can not be read or decoded.
+// Bad arguments are a usage error. Like diff(1), the diff command also uses
+// the usage error when a playbook can not be read.
+const (
+	exitOK         = 0
+	exitFailure    = 1
+	exitUsageError = 2
+)
+
+// command - This type defines a subcommand. The run function gets the
+// arguments that follow the name of the subcommand and returns the exit code.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+// commands - This is the list of subcommands in the order they are shown in
+// the help output.
+var commands = []command{
+	{"new", "Create a new playbook with a start and an end step", runNew},
+	{"fmt", "Print playbooks in canonical form", runFmt},
+	{"sign", "Sign a playbook with a private key", runSign},
+	{"verify", "Verify the signatures on playbooks", runVerify},
+	{"diff", "Show the differences between two playbooks", runDiff},
+}
+
+func main() {
+	getopt.HelpColumn = 35
+	getopt.DisplayWidth = 120
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// run - This function will find the subcommand named by the first argument
+// and run it with the rest of the arguments.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsageError
+	}
+
+	switch args[0] {
+	case "--version", "version":
+		printOutputHeader(stdout)
+		return exitOK
+	case "--help", "-h", "help":
+		// Help for a subcommand is the same as running it with --help
+		if len(args) > 1 {
+			return run([]string{args[1], "--help"}, stdout, stderr)
+		}
+		printOutputHeader(stdout)
+		printUsage(stdout)
+		return exitOK
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "Error: %q is not a cacao command\n\n", args[0])
+	printUsage(stderr)
+	return exitUsageError
+}
+
+// newOptionSet - This function will create the set of command line options
+// for a subcommand.
+func newOptionSet(name, parameters string) *getopt.Set {
+	set := getopt.New()
+	set.SetProgram("cacao " + name)
+	set.SetParameters(parameters)
+	return set
+}
+
+// parseOptions - This function will add the --help option to the set and
+// parse the arguments. It returns false and the exit code if the subcommand
+// should stop, either because the arguments are not valid or because the help
+// information was printed.
+func parseOptions(set *getopt.Set, args []string, stdout, stderr io.Writer) (int, bool) {
+	help := set.BoolLong("help", 'h', "Help")
+
+	// The first argument is the program name, which the set already has
+	if err := set.Getopt(append([]string{"cacao"}, args...), nil); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		set.PrintUsage(stderr)
+		return exitUsageError, false
+	}
+
+	if *help {
+		set.PrintUsage(stdout)
+		return exitOK, false
+	}
+	return exitOK, true
+}
+
+// usageError - This function will print an error about the arguments of a
+// subcommand along with its usage and return the usage error exit code.
+func usageError(set *getopt.Set, stderr io.Writer, format string, a ...interface{}) int {
+	fmt.Fprintf(stderr, "Error: "+format+"\n", a...)
+	set.PrintUsage(stderr)
+	return exitUsageError
+}
+
+// printUsage - This function will print the list of subcommands.
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: cacao <command> [options] [arguments]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Use \"cacao help <command>\" for the options of a command.")
+}
+
+// printOutputHeader - This function will print a header for the version and
+// help output
+func printOutputHeader(w io.Writer) {
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "CACAO Tool")
+	fmt.Fprintln(w, "Copyright, Bret Jordan")
+	fmt.Fprintln(w, "Version:", Version)
+	if Build != "" {
+		fmt.Fprintln(w, "Build:", Build)
+	}
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,247 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects/playbook"
+)
+
+// runCommand - This will run the cacao command with the arguments and return
+// the exit code and the output
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestNewAndFmt - This will test creating a playbook and formatting it
+func TestNewAndFmt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pb.json")
+
+	if code, _, stderr := runCommand("new", "--type", "investigation,detection", "-o", path); code != exitOK {
+		t.Fatalf("1.1 new returned %d: %s", code, stderr)
+	}
+	data, _ := os.ReadFile(path)
+	p, err := playbook.Decode(data)
+	if err != nil {
+		t.Fatalf("1.2 unable to decode the new playbook: %s", err)
+	}
+	if valid, _, details := p.Valid(false); !valid {
+		t.Errorf("1.3 the new playbook is not valid %v", details)
+	}
+	if p.Name != "New investigation and detection playbook" || len(p.Workflow) != 2 {
+		t.Errorf("1.4 the new playbook is not correct %+v", p)
+	}
+
+	if code, _, _ := runCommand("new", "--type", "investigation", "-o", path); code != exitFailure {
+		t.Errorf("1.5 new overwrote an existing file")
+	}
+	if code, _, stderr := runCommand("new", "--type", "cooking"); code != exitUsageError || !strings.Contains(stderr, "cooking") {
+		t.Errorf("1.6 new with a type that is not valid returned %d: %s", code, stderr)
+	}
+
+	// The new playbook is not in canonical form until it is formatted
+	if code, stdout, _ := runCommand("fmt", "--check", path); code != exitFailure || stdout != path+"\n" {
+		t.Errorf("1.7 fmt --check returned %d: %s", code, stdout)
+	}
+	code, stdout, _ := runCommand("fmt", path)
+	if code != exitOK || !strings.HasPrefix(stdout, "{\n  \"created\": ") {
+		t.Errorf("1.8 fmt did not print the canonical form %d:\n%s", code, stdout)
+	}
+	if code, _, _ := runCommand("fmt", "-w", path); code != exitOK {
+		t.Errorf("1.9 fmt -w returned %d", code)
+	}
+	if data, _ := os.ReadFile(path); string(data) != stdout {
+		t.Errorf("1.10 fmt -w did not write the canonical form")
+	}
+	if code, _, _ := runCommand("fmt", "--check", path); code != exitOK {
+		t.Errorf("1.11 fmt --check failed for a formatted file")
+	}
+
+	// YAML is written for YAML files and formatted as YAML
+	yamlPath := filepath.Join(dir, "pb.yaml")
+	if code, _, _ := runCommand("new", "--type", "mitigation", "-o", yamlPath); code != exitOK {
+		t.Fatalf("1.12 new did not write a YAML file")
+	}
+	if code, _, _ := runCommand("fmt", "-w", yamlPath); code != exitOK {
+		t.Errorf("1.13 fmt -w returned %d for a YAML file", code)
+	}
+	if data, _ := os.ReadFile(yamlPath); !strings.HasPrefix(string(data), "created: ") {
+		t.Errorf("1.14 the YAML file is not in canonical form:\n%s", data)
+	}
+}
+
+// TestSignAndVerify - This will test signing playbooks with a key and a
+// certificate, and verifying them with trusted keys and certificates
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, blocks ...*pem.Block) string {
+		var data []byte
+		for _, b := range blocks {
+			data = append(data, pem.EncodeToMemory(b)...)
+		}
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		os.WriteFile(path, data, 0600)
+		return path
+	}
+	privateKey := func(key *ecdsa.PrivateKey) *pem.Block {
+		der, _ := x509.MarshalPKCS8PrivateKey(key)
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+	publicKey := func(key *ecdsa.PrivateKey) *pem.Block {
+		der, _ := x509.MarshalPKIXPublicKey(key.Public())
+		return &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+
+	authorKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	authorPath := write("author.pem", privateKey(authorKey))
+	write("trusted/author.pub", publicKey(authorKey))
+	otherPub := write("other.pub", publicKey(otherKey))
+
+	// A root certificate and a code signing certificate for the company
+	rootKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rootTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDer, _ := x509.CreateCertificate(rand.Reader, &rootTemplate, &rootTemplate, rootKey.Public(), rootKey)
+	root, _ := x509.ParseCertificate(rootDer)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	leafTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "ACME Cyber Company"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leafDer, _ := x509.CreateCertificate(rand.Reader, &leafTemplate, root, leafKey.Public(), rootKey)
+	companyPath := write("company.pem", privateKey(leafKey), &pem.Block{Type: "CERTIFICATE", Bytes: leafDer})
+	write("trusted/root.crt", &pem.Block{Type: "CERTIFICATE", Bytes: rootDer})
+	trusted := filepath.Join(dir, "trusted")
+
+	path := filepath.Join(dir, "pb.json")
+	if code, _, stderr := runCommand("new", "--type", "investigation", "-o", path); code != exitOK {
+		t.Fatalf("2.1 new returned %d: %s", code, stderr)
+	}
+
+	if code, _, stderr := runCommand("sign", "--key", authorPath, path); code != exitUsageError || !strings.Contains(stderr, "--signee") {
+		t.Errorf("2.2 sign without a signee returned %d: %s", code, stderr)
+	}
+	if code, _, stderr := runCommand("sign", "--key", authorPath, "--alg", "ES256", "--signee", "Author", "-w", path); code != exitOK {
+		t.Fatalf("2.3 sign returned %d: %s", code, stderr)
+	}
+	signed := filepath.Join(dir, "signed.json")
+	if code, _, stderr := runCommand("sign", "--key", companyPath, "--alg", "ES384", "-o", signed, path); code != exitOK {
+		t.Fatalf("2.4 sign with a certificate returned %d: %s", code, stderr)
+	}
+
+	data, _ := os.ReadFile(signed)
+	p, _ := playbook.Decode(data)
+	if len(p.Signatures) != 2 || p.Signatures[1].Signee != "ACME Cyber Company" || len(p.Signatures[1].PublicCertChain) != 1 {
+		t.Fatalf("2.5 the signatures are not correct %+v", p.Signatures)
+	}
+	if p.Signatures[0].RelatedTo != p.ID || p.Signatures[0].RelatedVersion != p.Modified {
+		t.Errorf("2.6 the signature is not bound to the playbook %+v", p.Signatures[0])
+	}
+	if valid, _, details := p.Valid(false); !valid {
+		t.Errorf("2.7 the signed playbook is not valid %v", details)
+	}
+
+	code, stdout, _ := runCommand("verify", "--trust", trusted, signed)
+	if code != exitOK || !strings.HasPrefix(stdout, signed+": verified, 2 of 2") {
+		t.Errorf("2.8 verify returned %d:\n%s", code, stdout)
+	}
+
+	// Only the author key is trusted, so the company signature does not verify
+	if code, stdout, _ := runCommand("verify", "--key", "Author="+filepath.Join(trusted, "author.pub"), signed); code != exitFailure || !strings.Contains(stdout, "1 of 2") {
+		t.Errorf("2.9 verify without the trusted root returned %d:\n%s", code, stdout)
+	}
+	if code, _, _ := runCommand("verify", "--key", "Author="+filepath.Join(trusted, "author.pub"), "--threshold", "1", signed); code != exitOK {
+		t.Errorf("2.10 verify with a threshold of 1 returned %d", code)
+	}
+	if code, stdout, _ := runCommand("verify", "--trust", otherPub, path); code != exitFailure || !strings.Contains(stdout, "is not trusted") {
+		t.Errorf("2.11 verify with an untrusted key returned %d:\n%s", code, stdout)
+	}
+
+	// Tampered content does not verify
+	p.Name = "Something else"
+	data, _ = p.Encode()
+	os.WriteFile(signed, data, 0600)
+	if code, stdout, _ := runCommand("verify", "--trust", trusted, signed); code != exitFailure || !strings.Contains(stdout, "0 of 2") {
+		t.Errorf("2.12 verify of a tampered playbook returned %d:\n%s", code, stdout)
+	}
+
+	if code, _, _ := runCommand("verify", signed); code != exitUsageError {
+		t.Errorf("2.13 verify without trusted keys returned %d", code)
+	}
+
+	// One key signing under two signee names only counts once
+	twice := filepath.Join(dir, "twice.json")
+	if code, _, stderr := runCommand("sign", "--key", authorPath, "--alg", "ES256", "--signee", "Someone Else", "-o", twice, path); code != exitOK {
+		t.Fatalf("2.14 sign returned %d: %s", code, stderr)
+	}
+	if code, stdout, _ := runCommand("verify", "--trust", trusted, "--threshold", "2", twice); code != exitFailure || !strings.Contains(stdout, "2 of 2") {
+		t.Errorf("2.15 verify of one key under two signees with a threshold of 2 returned %d:\n%s", code, stdout)
+	}
+}
+
+// TestDiff - This will test comparing playbooks and the command dispatch
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	runCommand("new", "--type", "investigation", "-o", a)
+	data, _ := os.ReadFile(a)
+	os.WriteFile(b, bytes.Replace(data, []byte("New investigation playbook"), []byte("Renamed"), 1), 0600)
+
+	if code, stdout, _ := runCommand("diff", a, a); code != exitOK || stdout != "" {
+		t.Errorf("3.1 diff of the same playbook returned %d:\n%s", code, stdout)
+	}
+	if code, stdout, _ := runCommand("diff", a, b); code != exitFailure || stdout != "~ /name: \"New investigation playbook\" -> \"Renamed\"\n" {
+		t.Errorf("3.2 diff of different playbooks returned %d:\n%s", code, stdout)
+	}
+	code, stdout, _ := runCommand("diff", "--patch", a, b)
+	var patch []map[string]string
+	if err := json.Unmarshal([]byte(stdout), &patch); code != exitFailure || err != nil || len(patch) != 1 || patch[0]["path"] != "/name" || patch[0]["value"] != "Renamed" {
+		t.Errorf("3.3 diff --patch returned %d:\n%s", code, stdout)
+	}
+	if code, stdout, _ := runCommand("diff", "--patch", a, a); code != exitOK || stdout != "[]\n" {
+		t.Errorf("3.4 diff --patch of the same playbook returned %d:\n%s", code, stdout)
+	}
+	if code, _, _ := runCommand("diff", a); code != exitUsageError {
+		t.Errorf("3.5 diff with one file returned %d", code)
+	}
+
+	if code, _, stderr := runCommand("cook"); code != exitUsageError || !strings.Contains(stderr, "not a cacao command") {
+		t.Errorf("3.6 an unknown command returned %d: %s", code, stderr)
+	}
+	if code, stdout, _ := runCommand("help", "diff"); code != exitOK || !strings.Contains(stdout, "cacao diff") {
+		t.Errorf("3.7 help for a command returned %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// runDiff - This function will compare two playbooks and print a report of
+// the changes that turn the first playbook into the second, or with --patch,
+// the changes as a JSON Patch (RFC 6902). Like diff, it exits with 0 if the
+// playbooks are the same and 1 if they are different.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	set := newOptionSet("diff", "playbook1 playbook2")
+	bOptPatch := set.BoolLong("patch", 'p', "Print the changes as a JSON Patch")
+	if code, ok := parseOptions(set, args, stdout, stderr); !ok {
+		return code
+	}
+
+	if set.NArgs() != 2 {
+		return usageError(set, stderr, "two playbook files are needed")
+	}
+
+	p1, err := readPlaybook(set.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %s\n", set.Arg(0), err)
+		return exitUsageError
+	}
+	p2, err := readPlaybook(set.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %s\n", set.Arg(1), err)
+		return exitUsageError
+	}
+
+	changes, err := p1.Diff(p2)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitUsageError
+	}
+
+	if *bOptPatch {
+		patch, err := objects.JSONPatch(changes)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitUsageError
+		}
+		fmt.Fprintln(stdout, string(patch))
+	} else {
+		for _, line := range objects.RenderChanges(changes) {
+			fmt.Fprintln(stdout, line)
+		}
+	}
+
+	if len(changes) > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects/playbook"
+)
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// readPlaybook - This function will read and decode a playbook in JSON or
+// YAML, depending on the extension of the file. A path of "-" reads JSON from
+// stdin.
+func readPlaybook(path string) (*playbook.Playbook, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isYAMLFile(path) {
+		return playbook.DecodeYAML(data)
+	}
+	return playbook.Decode(data)
+}
+
+// encodePlaybook - This function will encode a playbook in YAML or in
+// indented JSON, followed by a new line.
+func encodePlaybook(p *playbook.Playbook, yaml bool) ([]byte, error) {
+	if yaml {
+		return p.EncodeYAML()
+	}
+
+	data, err := p.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// writeOutput - This function will write the data to the file, or to stdout if
+// the path is empty or "-".
+func writeOutput(path string, data []byte, stdout io.Writer) error {
+	if path == "" || path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// outputIsYAML - This function returns true if the output should be YAML. The
+// extension of the output file is used, or of the input file if the output is
+// stdout.
+func outputIsYAML(input, output string) bool {
+	if output != "" && output != "-" {
+		return isYAMLFile(output)
+	}
+	return isYAMLFile(input)
+}
+
+// readFile - This function will read a file, or stdin if the path is "-".
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// isYAMLFile - This function returns true if the file has a YAML extension.
+func isYAMLFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/playbook"
+)
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// runFmt - This function will print each playbook in canonical form, which is
+// the JCS form of the playbook indented with two spaces, so the same playbook
+// is always printed the same way. YAML files are printed as YAML with the
+// keys in the same order. With --write the files are updated in place and
+// with --check the files that are not in canonical form are listed.
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	set := newOptionSet("fmt", "[playbook ...]")
+	bOptWrite := set.BoolLong("write", 'w', "Write the result back to the files")
+	bOptCheck := set.BoolLong("check", 'c', "List the files that are not formatted and fail if there are any")
+	if code, ok := parseOptions(set, args, stdout, stderr); !ok {
+		return code
+	}
+
+	files := set.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if *bOptWrite && *bOptCheck {
+		return usageError(set, stderr, "--write and --check can not be used together")
+	}
+
+	code := exitOK
+	for _, path := range files {
+		if path == "-" && *bOptWrite {
+			return usageError(set, stderr, "--write can only be used with files")
+		}
+
+		data, err := readFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			code = exitFailure
+			continue
+		}
+		formatted, err := formatPlaybook(data, isYAMLFile(path))
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %s\n", path, err)
+			code = exitFailure
+			continue
+		}
+
+		switch {
+		case *bOptCheck:
+			if !bytes.Equal(data, formatted) {
+				fmt.Fprintln(stdout, path)
+				code = exitFailure
+			}
+		case *bOptWrite:
+			if !bytes.Equal(data, formatted) {
+				err = os.WriteFile(path, formatted, 0644)
+			}
+		default:
+			_, err = stdout.Write(formatted)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			code = exitFailure
+		}
+	}
+	return code
+}
+
+// formatPlaybook - This function will decode a playbook and return it in
+// canonical form, as indented JSON or as YAML.
+func formatPlaybook(data []byte, yaml bool) ([]byte, error) {
+	var p *playbook.Playbook
+	var err error
+	if yaml {
+		p, err = playbook.DecodeYAML(data)
+	} else {
+		p, err = playbook.Decode(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	canonical, err := p.Canonical()
+	if err != nil {
+		return nil, err
+	}
+	if yaml {
+		return objects.JSONToYAML(canonical)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, canonical, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/playbook"
+	"github.com/openplaybooks/libcacao/objects/workflow"
+)
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// runNew - This function will create a new playbook of the types given, with a
+// workflow that has a start step followed by an end step. The playbook is
+// written to stdout, or to a new file. An existing file is not overwritten.
+func runNew(args []string, stdout, stderr io.Writer) int {
+	set := newOptionSet("new", "")
+	sOptType := set.StringLong("type", 't', "", "Playbook types, as a comma separated list")
+	sOptName := set.StringLong("name", 'n', "", "Name of the playbook")
+	sOptDescription := set.StringLong("description", 'd', "", "Description of the playbook")
+	sOptCreatedBy := set.StringLong("created-by", 0, "", "Identity of the creator, a new one is made if it is not given")
+	sOptOutput := set.StringLong("output", 'o', "", "File to write the playbook to, instead of stdout")
+	bOptYAML := set.BoolLong("yaml", 0, "Write YAML instead of JSON, this is the default for .yaml and .yml files")
+	if code, ok := parseOptions(set, args, stdout, stderr); !ok {
+		return code
+	}
+
+	if set.NArgs() != 0 {
+		return usageError(set, stderr, "unexpected arguments: %s", strings.Join(set.Args(), " "))
+	}
+	if *sOptType == "" {
+		return usageError(set, stderr, "a playbook type is needed, use --type with one of: %s", strings.Join(playbook.GetPlaybookTypesVocab(), ", "))
+	}
+
+	p, err := newPlaybook(*sOptType, *sOptName, *sOptDescription, *sOptCreatedBy)
+	if err != nil {
+		return usageError(set, stderr, "%s", err)
+	}
+
+	data, err := encodePlaybook(p, *bOptYAML || outputIsYAML("", *sOptOutput))
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+
+	if *sOptOutput == "" || *sOptOutput == "-" {
+		err = writeOutput("", data, stdout)
+	} else {
+		err = createFile(*sOptOutput, data)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// newPlaybook - This function will create a new playbook with a start step
+// that is followed by an end step. If the name is empty one is made from the
+// playbook types.
+func newPlaybook(types, name, description, createdBy string) (*playbook.Playbook, error) {
+	p := playbook.New()
+
+	if err := p.AddPlaybookTypes(types); err != nil {
+		return nil, err
+	}
+	for _, t := range p.PlaybookTypes {
+		if !objects.IsVocabValueValid(t, playbook.GetPlaybookTypesVocab()) {
+			return nil, fmt.Errorf("the playbook type %q is not valid, use one of: %s", t, strings.Join(playbook.GetPlaybookTypesVocab(), ", "))
+		}
+	}
+
+	if name == "" {
+		name = "New " + strings.Join(p.PlaybookTypes, " and ") + " playbook"
+	}
+	p.Name = name
+	p.Description = description
+
+	if createdBy == "" {
+		createdBy, _ = objects.CreateID("identity")
+	} else if !strings.HasPrefix(createdBy, "identity--") || !objects.IsUUIDValid(strings.TrimPrefix(createdBy, "identity--")) {
+		return nil, fmt.Errorf("the created by value %q is not a valid identity identifier", createdBy)
+	}
+	p.CreatedBy = createdBy
+
+	start, err := workflow.NewStartStep()
+	if err != nil {
+		return nil, err
+	}
+	end, err := workflow.NewEndStep()
+	if err != nil {
+		return nil, err
+	}
+	start.OnCompletion = end.ID
+	p.WorkflowStart = start.ID
+
+	// Adding a step clears its ID, so the IDs are used above first
+	if err := p.AddWorkflowStep(start); err != nil {
+		return nil, err
+	}
+	if err := p.AddWorkflowStep(end); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// createFile - This function will write the data to a new file, and return an
+// error if the file already exists.
+func createFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists", path)
+		}
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// runSign - This function will sign a playbook with the private key in a PEM
+// file. If the PEM file also has certificates, they are added to the
+// signature as the certificate chain and the signee defaults to the common
+// name of the leaf certificate. Otherwise the public key is added to the
+// signature. The signature is bound to this version of the playbook with the
+// related_to and related_version properties.
+func runSign(args []string, stdout, stderr io.Writer) int {
+	set := newOptionSet("sign", "playbook")
+	sOptKey := set.StringLong("key", 'k', "", "PEM file with the private key and optional certificate chain")
+	sOptAlg := set.StringLong("alg", 'a', "ES256", "Signing algorithm, like ES256, RS256 or Ed25519")
+	sOptSignee := set.StringLong("signee", 's', "", "Name of the signee")
+	sOptCreatedBy := set.StringLong("created-by", 0, "", "Identity of the creator of the signature")
+	sOptValidUntil := set.StringLong("valid-until", 0, "", "Timestamp when the signature expires")
+	sOptOutput := set.StringLong("output", 'o', "", "File to write the signed playbook to, instead of stdout")
+	bOptWrite := set.BoolLong("write", 'w', "Write the signed playbook back to the file")
+	if code, ok := parseOptions(set, args, stdout, stderr); !ok {
+		return code
+	}
+
+	if *sOptKey == "" {
+		return usageError(set, stderr, "a private key is needed, use --key")
+	}
+	if set.NArgs() != 1 {
+		return usageError(set, stderr, "one playbook file is needed")
+	}
+	path := set.Arg(0)
+	output := *sOptOutput
+	if *bOptWrite {
+		if path == "-" || output != "" {
+			return usageError(set, stderr, "--write can only be used with a file and without --output")
+		}
+		output = path
+	}
+
+	p, err := readPlaybook(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %s\n", path, err)
+		return exitFailure
+	}
+
+	signer, err := signature.LoadPEMSigner(*sOptKey, *sOptAlg)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+
+	sig := signature.New()
+	sig.Signee = *sOptSignee
+	sig.CreatedBy = *sOptCreatedBy
+	if chain := signer.CertificateChain(); len(chain) > 0 {
+		if sig.Signee == "" {
+			sig.Signee = chain[0].Subject.CommonName
+		}
+	} else if err := sig.SetPublicKey(signer.Public()); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+	if sig.Signee == "" {
+		return usageError(set, stderr, "a signee is needed, use --signee")
+	}
+	if *sOptValidUntil != "" {
+		if err := sig.SetValidUntil(*sOptValidUntil); err != nil {
+			return usageError(set, stderr, "%s", err)
+		}
+	}
+
+	// The values are copied as they are, so they match the playbook exactly
+	sig.RelatedTo = p.ID
+	sig.RelatedVersion = p.Modified
+
+	if err := p.SignWith(signer, sig); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+
+	data, err := encodePlaybook(p, outputIsYAML(path, output))
+	if err == nil {
+		err = writeOutput(output, data, stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitFailure
+	}
+	return exitOK
+}
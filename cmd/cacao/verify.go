@@ -0,0 +1,254 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// trustStore - This type is a signature.KeyResolver for the keys and
+// certificates that were given on the command line. A signature with a
+// certificate chain must chain to one of the trusted certificates. Otherwise
+// the key of its signee is used, if one was given, or the public key in the
+// signature if it is one of the trusted keys.
+type trustStore struct {
+	certs *signature.CertResolver
+	ring  *signature.KeyRing
+	keys  []crypto.PublicKey
+}
+
+// --------------------------------------------------
+// Public trustStore methods
+// --------------------------------------------------
+
+// ResolveKey - This method implements signature.KeyResolver.
+func (t *trustStore) ResolveKey(s *signature.Signature) (crypto.PublicKey, error) {
+	if len(s.PublicCertChain) > 0 {
+		if t.certs == nil {
+			return nil, fmt.Errorf("the signature has a certificate chain, but no trusted certificates were given")
+		}
+		return t.certs.ResolveKey(s)
+	}
+
+	if key, err := t.ring.ResolveKey(s); err == nil {
+		return key, nil
+	}
+
+	if s.PublicKey != "" {
+		key, err := s.ParsePublicKey()
+		if err != nil {
+			return nil, err
+		}
+		for _, trusted := range t.keys {
+			if k, ok := trusted.(interface{ Equal(crypto.PublicKey) bool }); ok && k.Equal(key) {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("the public key of the signee %q is not trusted", s.Signee)
+	}
+	return nil, fmt.Errorf("no trusted key was found for the signee %q", s.Signee)
+}
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// runVerify - This function will verify the signatures on each playbook,
+// including the countersignatures nested in them. By default every signature
+// must verify. With --threshold, the playbook only needs valid signatures
+// from that many different keys.
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	set := newOptionSet("verify", "playbook ...")
+	lOptTrust := set.ListLong("trust", 't', "PEM files, or directories of them, with trusted certificates and public keys")
+	lOptKey := set.ListLong("key", 'k', "Public key for a signee, as signee=file.pem")
+	iOptThreshold := set.IntLong("threshold", 0, 0, "Number of different keys that must have a valid signature, instead of all")
+	if code, ok := parseOptions(set, args, stdout, stderr); !ok {
+		return code
+	}
+
+	if set.NArgs() == 0 {
+		return usageError(set, stderr, "one or more playbook files are needed")
+	}
+	if len(*lOptTrust) == 0 && len(*lOptKey) == 0 {
+		return usageError(set, stderr, "trusted keys are needed, use --trust or --key")
+	}
+
+	trust, err := loadTrustStore(*lOptTrust, *lOptKey)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return exitUsageError
+	}
+
+	code := exitOK
+	for _, path := range set.Args() {
+		if !verifyFile(stdout, path, trust, *iOptThreshold) {
+			code = exitFailure
+		}
+	}
+	return code
+}
+
+// verifyFile - This function will verify the signatures on one playbook and
+// print the result for each of them. It returns true if the playbook passed.
+func verifyFile(w io.Writer, path string, trust *trustStore, threshold int) bool {
+	p, err := readPlaybook(path)
+	if err != nil {
+		fmt.Fprintf(w, "%s: error: %s\n", path, err)
+		return false
+	}
+	if len(p.Signatures) == 0 {
+		fmt.Fprintf(w, "%s: not verified, the playbook is not signed\n", path)
+		return false
+	}
+
+	var lines []string
+	valid := 0
+	var keys []crypto.PublicKey
+	for i := range p.Signatures {
+		sig := &p.Signatures[i]
+		links, err := p.VerifyChain(sig.ID, trust)
+		if err == nil {
+			valid++
+			// The signee is just a name, so the threshold counts the
+			// different keys that made valid signatures instead.
+			if key, err := trust.ResolveKey(sig); err == nil {
+				keys = addKey(keys, key)
+			}
+		}
+		for i, link := range links {
+			status := "valid"
+			if link.Err != nil {
+				status = link.Err.Error()
+			}
+			if i == 0 {
+				lines = append(lines, fmt.Sprintf("  %s by %s (%s): %s", link.SignatureID, link.Signee, link.Algorithm, status))
+			} else {
+				lines = append(lines, fmt.Sprintf("    countersigned by %s (%s): %s", link.Signee, link.Algorithm, status))
+			}
+		}
+	}
+
+	passed := valid == len(p.Signatures)
+	if threshold > 0 {
+		passed = len(keys) >= threshold
+	}
+	if passed {
+		fmt.Fprintf(w, "%s: verified, %d of %d signatures are valid\n", path, valid, len(p.Signatures))
+	} else {
+		fmt.Fprintf(w, "%s: not verified, %d of %d signatures are valid\n", path, valid, len(p.Signatures))
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+	return passed
+}
+
+// addKey - This function will add the key to the list if it is not already
+// in it.
+func addKey(keys []crypto.PublicKey, key crypto.PublicKey) []crypto.PublicKey {
+	for _, k := range keys {
+		if e, ok := k.(interface{ Equal(crypto.PublicKey) bool }); ok && e.Equal(key) {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
+
+// loadTrustStore - This function will load the trusted certificates and
+// public keys from the PEM files and directories, and the keys for the
+// signees given as signee=file.pem.
+func loadTrustStore(trusted, signeeKeys []string) (*trustStore, error) {
+	t := &trustStore{ring: signature.NewKeyRing()}
+	roots := x509.NewCertPool()
+	certs := 0
+
+	for _, arg := range trusted {
+		files, err := pemFiles(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			for {
+				var block *pem.Block
+				block, data = pem.Decode(data)
+				if block == nil {
+					break
+				}
+				switch block.Type {
+				case "CERTIFICATE":
+					cert, err := x509.ParseCertificate(block.Bytes)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", path, err)
+					}
+					roots.AddCert(cert)
+					certs++
+				case "PUBLIC KEY":
+					key, err := signature.ParsePEMPublicKey(pem.EncodeToMemory(block))
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", path, err)
+					}
+					t.keys = append(t.keys, key)
+				}
+			}
+		}
+	}
+	if certs > 0 {
+		t.certs = signature.NewCertResolver(roots)
+	}
+
+	for _, arg := range signeeKeys {
+		i := strings.Index(arg, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("the key %q is not in the form signee=file.pem", arg)
+		}
+		if err := t.ring.AddPEMFile(arg[:i], arg[i+1:]); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// pemFiles - This function will return the file, or the files in the
+// directory and its subdirectories that have a .pem, .crt, .cer or .pub
+// extension.
+func pemFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".pem", ".crt", ".cer", ".pub":
+			if !d.IsDir() {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// These are the file extensions that are validated when a directory is given
+var playbookExtensions = []string{".json", ".yaml", ".yml"}
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// expandArgs - This function will turn the command line arguments into a list
+// of files. Directories are searched recursively for playbook files, and glob
+// patterns are expanded, where ** matches any number of directories. A "-"
+// means stdin. Files that do not exist are kept so they are reported as
+// errors, but a pattern that does not match anything is an error.
+func expandArgs(args []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	for _, arg := range args {
+		if arg == "-" {
+			add(arg)
+			continue
+		}
+
+		if hasMeta(arg) {
+			matches, err := glob(arg)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %s", arg)
+			}
+			for _, m := range matches {
+				add(m)
+			}
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			add(arg)
+			continue
+		}
+		found, err := walkDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range found {
+			add(f)
+		}
+	}
+	return files, nil
+}
+
+// walkDir - This function will return the playbook files in the directory and
+// its sub directories.
+func walkDir(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isPlaybookFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// glob - This function will return the files that match the pattern. It works
+// like filepath.Glob, except that a ** path element matches zero or more
+// directories.
+func glob(pattern string) ([]string, error) {
+	// Make sure the pattern is valid before walking the file system
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("the pattern %s is not valid: %s", pattern, err)
+	}
+
+	parts := strings.Split(filepath.Clean(pattern), string(filepath.Separator))
+
+	// The directory to start from is made of the parts before the first one
+	// with a wildcard
+	i := 0
+	for i < len(parts) && !hasMeta(parts[i]) {
+		i++
+	}
+	root := filepath.Join(parts[:i]...)
+	if strings.HasPrefix(pattern, string(filepath.Separator)) {
+		root = string(filepath.Separator) + root
+	}
+	if root == "" {
+		root = "."
+	}
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if matchParts(parts[i:], strings.Split(rel, string(filepath.Separator))) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// matchParts - This function will return true if the path elements match the
+// pattern elements, where ** matches zero or more path elements.
+func matchParts(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchParts(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	matched, _ := filepath.Match(pattern[0], name[0])
+	return matched && matchParts(pattern[1:], name[1:])
+}
+
+// hasMeta - This function will return true if the path has glob wildcards
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[`)
+}
+
+// isPlaybookFile - This function will return true if the file extension is
+// one that playbooks are written in
+func isPlaybookFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range playbookExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// isYAMLFile - This function will return true if the file is YAML
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,311 @@
+// Copyright 2021 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// These are the output formats for the --format option
+var outputFormats = map[string]func(io.Writer, []fileResult) (bool, error){
+	"text":  func(w io.Writer, results []fileResult) (bool, error) { return printText(w, results), nil },
+	"json":  printJSON,
+	"junit": printJUnit,
+	"sarif": printSARIF,
+}
+
+// decodeRule is the rule ID used for files that can not be read or decoded
+const decodeRule = "playbook.decode"
+
+// outputResult - This type is a single result in the machine readable output
+// formats. The line and column are zero when they are not known.
+type outputResult struct {
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// printJSON - This function will print the results as a JSON document with a
+// summary for each file and a flat list of results.
+func printJSON(w io.Writer, results []fileResult) (bool, error) {
+	type file struct {
+		Path     string `json:"path"`
+		Valid    bool   `json:"valid"`
+		Problems int    `json:"problems"`
+		Error    string `json:"error,omitempty"`
+	}
+	doc := struct {
+		Valid   bool           `json:"valid"`
+		Files   []file         `json:"files"`
+		Results []outputResult `json:"results"`
+	}{Valid: allValid(results), Files: []file{}, Results: collectResults(results)}
+
+	for _, r := range results {
+		f := file{Path: r.Path, Valid: r.Valid, Problems: r.Problems}
+		if r.Err != nil {
+			f.Error = r.Err.Error()
+		}
+		doc.Files = append(doc.Files, f)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return doc.Valid, enc.Encode(doc)
+}
+
+// printJUnit - This function will print the results as JUnit XML with a test
+// case for each file. Files with problems fail, and files that can not be
+// read or decoded are errors.
+func printJUnit(w io.Writer, results []fileResult) (bool, error) {
+	type message struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		Name      string   `xml:"name,attr"`
+		ClassName string   `xml:"classname,attr"`
+		Failure   *message `xml:"failure,omitempty"`
+		Error     *message `xml:"error,omitempty"`
+		SystemOut string   `xml:"system-out,omitempty"`
+	}
+	type testSuite struct {
+		Name      string     `xml:"name,attr"`
+		Tests     int        `xml:"tests,attr"`
+		Failures  int        `xml:"failures,attr"`
+		Errors    int        `xml:"errors,attr"`
+		TestCases []testCase `xml:"testcase"`
+	}
+	type testSuites struct {
+		XMLName  xml.Name    `xml:"testsuites"`
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Errors   int         `xml:"errors,attr"`
+		Suites   []testSuite `xml:"testsuite"`
+	}
+
+	suite := testSuite{Name: "CACAO Validator", Tests: len(results)}
+	for _, r := range results {
+		tc := testCase{Name: r.Path, ClassName: "cacao.playbook"}
+		var problems, info []string
+		for _, o := range collectResults([]fileResult{r}) {
+			line := location(o) + " " + o.Rule
+			if o.Pointer != "" {
+				line += " " + o.Pointer
+			}
+			line += ": " + o.Message
+			if o.Severity == objects.SeverityInfo {
+				info = append(info, line)
+			} else {
+				problems = append(problems, line)
+			}
+		}
+
+		switch {
+		case r.Err != nil:
+			suite.Errors++
+			tc.Error = &message{Message: r.Err.Error(), Type: decodeRule, Text: strings.Join(problems, "\n")}
+		case !r.Valid:
+			suite.Failures++
+			tc.Failure = &message{Message: plural(r.Problems, "error"), Type: "invalid", Text: strings.Join(problems, "\n")}
+		case len(problems) > 0:
+			// Warnings do not fail the test case
+			info = append(problems, info...)
+		}
+		tc.SystemOut = strings.Join(info, "\n")
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	doc := testSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []testSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return false, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return false, err
+	}
+	_, err := io.WriteString(w, "\n")
+	return allValid(results), err
+}
+
+// printSARIF - This function will print the results as a SARIF 2.1.0 log, so
+// they can be shown by code scanning tools. Successful checks, which are only
+// found with --debug, are reported with a kind of pass.
+func printSARIF(w io.Writer, results []fileResult) (bool, error) {
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+	type artifactLocation struct {
+		URI string `json:"uri"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           *region          `json:"region,omitempty"`
+	}
+	type logicalLocation struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation  `json:"physicalLocation"`
+		LogicalLocations []logicalLocation `json:"logicalLocations,omitempty"`
+	}
+	type text struct {
+		Text string `json:"text"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		RuleIndex int        `json:"ruleIndex"`
+		Kind      string     `json:"kind"`
+		Level     string     `json:"level"`
+		Message   text       `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	type rule struct {
+		ID string `json:"id"`
+	}
+
+	var rules []rule
+	ruleIndex := make(map[string]int)
+	sarifResults := []result{}
+
+	for _, o := range collectResults(results) {
+		if _, found := ruleIndex[o.Rule]; !found {
+			ruleIndex[o.Rule] = len(rules)
+			rules = append(rules, rule{ID: o.Rule})
+		}
+
+		r := result{RuleID: o.Rule, RuleIndex: ruleIndex[o.Rule], Kind: "fail", Message: text{o.Message}}
+		switch o.Severity {
+		case objects.SeverityError:
+			r.Level = "error"
+		case objects.SeverityWarning:
+			r.Level = "warning"
+		default:
+			r.Kind, r.Level = "pass", "none"
+		}
+
+		loc := location{PhysicalLocation: physicalLocation{ArtifactLocation: artifactLocation{URI: artifactURI(o.Path)}}}
+		if o.Line > 0 {
+			loc.PhysicalLocation.Region = &region{StartLine: o.Line, StartColumn: o.Column}
+		}
+		if o.Pointer != "" {
+			loc.LogicalLocations = []logicalLocation{{FullyQualifiedName: o.Pointer}}
+		}
+		r.Locations = []location{loc}
+		sarifResults = append(sarifResults, r)
+	}
+
+	driver := map[string]interface{}{
+		"name":           "cacao-validator",
+		"version":        Version,
+		"informationUri": "https://github.com/openplaybooks/libcacao",
+		"rules":          rules,
+	}
+	if rules == nil {
+		driver["rules"] = []rule{}
+	}
+	doc := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{
+			map[string]interface{}{
+				"tool":    map[string]interface{}{"driver": driver},
+				"results": sarifResults,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return allValid(results), enc.Encode(doc)
+}
+
+// collectResults - This function will turn the file results into a flat list
+// of results. A file that can not be read or decoded has a single result.
+func collectResults(results []fileResult) []outputResult {
+	out := []outputResult{}
+	for _, r := range results {
+		if r.Err != nil {
+			out = append(out, outputResult{
+				Path:     r.Path,
+				Severity: objects.SeverityError,
+				Rule:     decodeRule,
+				Message:  r.Err.Error(),
+				Line:     r.Line,
+				Column:   r.Column,
+			})
+			continue
+		}
+		for _, f := range r.Findings {
+			out = append(out, outputResult{
+				Path:     r.Path,
+				Severity: f.Severity,
+				Rule:     f.Rule,
+				Pointer:  f.Pointer,
+				Message:  f.Message,
+				Line:     f.Line,
+				Column:   f.Column,
+			})
+		}
+	}
+	return out
+}
+
+// allValid - This function will return true if every file is valid
+func allValid(results []fileResult) bool {
+	for _, r := range results {
+		if !r.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// location - This function will return the path, line and column of a result
+// in the form that compilers use
+func location(o outputResult) string {
+	if o.Line == 0 {
+		return o.Path + ":"
+	}
+	return fmt.Sprintf("%s:%d:%d:", o.Path, o.Line, o.Column)
+}
+
+// artifactURI - This function will return the URI of a file for SARIF.
+// Relative paths are kept relative so they resolve against the repository.
+func artifactURI(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	if filepath.IsAbs(path) {
+		return "file://" + filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(path)
+}
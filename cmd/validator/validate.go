@@ -0,0 +1,168 @@
+// Copyright 2021 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/playbook"
+)
+
+// fileResult - This type holds the result of validating one file. If the file
+// could not be read or decoded, Err says why and there are no findings. The
+// line and column of a decode error are set when they are known.
+type fileResult struct {
+	Path     string
+	Valid    bool
+	Problems int
+	Findings []finding
+	Err      error
+	Line     int
+	Column   int
+}
+
+// finding - This type is a finding along with the line and column in the file
+// of the property that its JSON Pointer refers to, or zero if it is not known.
+type finding struct {
+	objects.Finding
+	Line   int
+	Column int
+}
+
+// --------------------------------------------------
+// Private functions
+// --------------------------------------------------
+
+// validateFiles - This function will validate the files with a pool of
+// workers and return the results in the same order as the files.
+func validateFiles(files []string, workers int, debug bool) []fileResult {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	results := make([]fileResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = validateFile(files[i], debug)
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// validateFile - This function will read, decode and validate one file. A
+// path of "-" reads from stdin.
+func validateFile(path string, debug bool) fileResult {
+	result := fileResult{Path: path}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var p *playbook.Playbook
+	if isYAMLFile(path) {
+		p, err = playbook.DecodeYAML(data)
+	} else {
+		p, err = playbook.Decode(data)
+	}
+	if err != nil {
+		result.Err = err
+		var de *objects.DecodeError
+		if errors.As(err, &de) {
+			result.Line, result.Column = de.Line, de.Column
+		}
+		return result
+	}
+
+	// If the debug flag is set then the findings will include detail about what
+	// is good and bad otherwise just what is wrong.
+	// The positions are indexed once so the file is not read again for each
+	// finding.
+	var index *objects.PositionIndex
+	if isYAMLFile(path) {
+		index = objects.NewYAMLPositionIndex(data)
+	} else {
+		index = objects.NewJSONPositionIndex(data)
+	}
+	for _, f := range p.Check(debug) {
+		if f.IsProblem() {
+			result.Problems++
+		}
+		// A property that is missing is reported where its parent is
+		line, column, ok := index.Position(f.Pointer)
+		for pointer := f.Pointer; !ok && pointer != ""; {
+			pointer = pointer[:strings.LastIndex(pointer, "/")]
+			line, column, ok = index.Position(pointer)
+		}
+		result.Findings = append(result.Findings, finding{Finding: f, Line: line, Column: column})
+	}
+	result.Valid = result.Problems == 0
+	return result
+}
+
+// printText - This function will print a summary line and the details for
+// each file, followed by the totals. It returns true if every file is valid.
+func printText(w io.Writer, results []fileResult) bool {
+	valid, invalid, failed := 0, 0, 0
+
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(w, "%s: error: %s\n", r.Path, r.Err)
+		case r.Valid:
+			valid++
+			fmt.Fprintf(w, "%s: valid\n", r.Path)
+		default:
+			invalid++
+			fmt.Fprintf(w, "%s: invalid, %s\n", r.Path, plural(r.Problems, "error"))
+		}
+		for _, f := range r.Findings {
+			fmt.Fprintf(w, "    %s\n", f.String())
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s, %d valid, %d invalid, %d could not be read\n", plural(len(results), "file"), valid, invalid, failed)
+	return invalid == 0 && failed == 0
+}
+
+// plural - This function will return the count and the word, adding an s when
+// the count is not one
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
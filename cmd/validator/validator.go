@@ -6,12 +6,10 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"io"
 	"os"
+	"runtime"
 
-	"github.com/openplaybooks/libcacao/objects/playbook"
 	"github.com/pborman/getopt"
 )
 
@@ -19,61 +17,66 @@ import (
 // populated by the Makefile and uses the Git Head hash as its identifier.
 // These variables are used in the console output for --version and --help.
 var (
-	Version = "0.1.1"
+	Version = "0.2.0"
 	Build   string
 )
 
+// These are the exit codes. A file that is not valid, or that can not be read
+// or decoded, is a failure. Bad arguments, like a pattern that does not match
+// any files, are a usage error.
+const (
+	exitValid      = 0
+	exitInvalid    = 1
+	exitUsageError = 2
+)
+
 // These global variables are for dealing with command line options
 var (
-	bOptDebug = getopt.BoolLong("debug", 0, "Debug")
-	bOptHelp  = getopt.BoolLong("help", 0, "Help")
-	bOptVer   = getopt.BoolLong("version", 0, "Version")
+	bOptDebug   = getopt.BoolLong("debug", 0, "Debug")
+	iOptWorkers = getopt.IntLong("workers", 'w', runtime.NumCPU(), "Number of files to validate at the same time")
+	sOptFormat  = getopt.StringLong("format", 'f', "text", "Output format: text, json, junit or sarif")
+	bOptHelp    = getopt.BoolLong("help", 0, "Help")
+	bOptVer     = getopt.BoolLong("version", 0, "Version")
 )
 
 func main() {
 	processCommandLineFlags()
 
-	info, err := os.Stdin.Stat()
-	if err != nil {
-		panic(err)
+	output, found := outputFormats[*sOptFormat]
+	if !found {
+		fmt.Fprintln(os.Stderr, "Error: the output format must be text, json, junit or sarif")
+		os.Exit(exitUsageError)
 	}
 
-	if info.Mode()&os.ModeCharDevice != 0 || info.Size() <= 0 {
-		fmt.Println("The validator needs JSON data to be passed to it via a pipe.")
-		return
-	}
-
-	reader := bufio.NewReader(os.Stdin)
-	var output []byte
-
-	for {
-		input, err := reader.ReadByte()
-		if err != nil && err == io.EOF {
-			break
+	args := getopt.Args()
+	if len(args) == 0 {
+		info, err := os.Stdin.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintln(os.Stderr, "The validator needs files, directories or glob patterns, or JSON data passed to it via a pipe.")
+			os.Exit(exitUsageError)
 		}
-		output = append(output, input)
+		args = []string{"-"}
 	}
 
-	p, _ := playbook.Decode(output)
-
-	valid, count, details := p.Valid(*bOptDebug)
-	fmt.Println("Object valid: ", valid)
-	fmt.Println("Error Count: ", count)
+	files, err := expandArgs(args)
+	if err == nil && len(files) == 0 {
+		err = fmt.Errorf("no playbook files were found")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitUsageError)
+	}
 
-	// If the debug flag is set then print out detail about what is good and bad
-	// otherwise just print out what is wrong.
-	fmt.Println("\nDetails:")
-	if *bOptDebug {
-		for _, result := range details {
-			fmt.Println(result)
-		}
-	} else {
-		for _, result := range details {
-			if result[0:2] != "++" {
-				fmt.Println(result)
-			}
-		}
+	results := validateFiles(files, *iOptWorkers, *bOptDebug)
+	valid, err := output(os.Stdout, results)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitInvalid)
+	}
+	if !valid {
+		os.Exit(exitInvalid)
 	}
+	os.Exit(exitValid)
 }
 
 // --------------------------------------------------
@@ -85,7 +88,7 @@ func main() {
 func processCommandLineFlags() {
 	getopt.HelpColumn = 35
 	getopt.DisplayWidth = 120
-	getopt.SetParameters("")
+	getopt.SetParameters("[file | directory | pattern ...]")
 	getopt.Parse()
 
 	// Lets check to see if the version command line flag was given. If it is
@@ -0,0 +1,187 @@
+// Copyright 2021 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMatchParts - This will test matching paths against glob patterns
+func TestMatchParts(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.json", "a.json", true},
+		{"*.json", "a/a.json", false},
+		{"**/*.json", "a.json", true},
+		{"**/*.json", "a/b/c.json", true},
+		{"a/**/c.json", "a/c.json", true},
+		{"a/**/c.json", "a/b/d/c.json", true},
+		{"a/**/c.json", "b/c.json", false},
+		{"a/**", "a/b/c.json", true},
+		{"a/*/c.json", "a/b/d/c.json", false},
+	}
+
+	for i, test := range tests {
+		if got := matchParts(strings.Split(test.pattern, "/"), strings.Split(test.name, "/")); got != test.match {
+			t.Errorf("1.%d matchParts(%s, %s) returned %t", i, test.pattern, test.name, got)
+		}
+	}
+}
+
+// TestExpandArgs - This will test expanding files, directories and patterns
+// and validating the files that are found
+func TestExpandArgs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatalf("unable to write %s: %s", name, err)
+		}
+	}
+	write("a.json", "{\n  \"type\": \"playbook\",\n  \"name\": 5\n}")
+	write("sub/b.yaml", "type: playbook\n")
+	write("sub/deep/c.json", `{"type": "playbook"}`)
+	write("sub/notes.txt", "not a playbook")
+
+	files, err := expandArgs([]string{
+		filepath.Join(dir, "sub"),
+		filepath.Join(dir, "**", "*.json"),
+		filepath.Join(dir, "missing.json"),
+	})
+	if err != nil {
+		t.Fatalf("2.1 expandArgs returned an error: %s", err)
+	}
+	want := []string{
+		filepath.Join(dir, "sub", "b.yaml"),
+		filepath.Join(dir, "sub", "deep", "c.json"),
+		filepath.Join(dir, "a.json"),
+		filepath.Join(dir, "missing.json"),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("2.2 expandArgs returned %v instead of %v", files, want)
+	}
+
+	if _, err := expandArgs([]string{filepath.Join(dir, "**", "*.xml")}); err == nil {
+		t.Errorf("2.3 expandArgs did not return an error for a pattern that does not match")
+	}
+
+	results := validateFiles(files, 2, false)
+	for i, r := range results {
+		if r.Path != files[i] || r.Valid {
+			t.Errorf("2.4.%d the result is not correct %+v", i, r)
+		}
+	}
+	if results[0].Err != nil || results[0].Problems == 0 {
+		t.Errorf("2.5 the YAML file was not validated %+v", results[0])
+	}
+	if results[2].Err == nil || !strings.HasPrefix(results[2].Err.Error(), "line 3 column 11") {
+		t.Errorf("2.6 the decode error does not have the line and column: %v", results[2].Err)
+	}
+	if results[3].Err == nil {
+		t.Errorf("2.7 the missing file did not return an error")
+	}
+
+	var buf bytes.Buffer
+	if printText(&buf, results) {
+		t.Errorf("2.8 printText returned true for invalid files")
+	}
+	if !strings.HasSuffix(buf.String(), "4 files, 0 valid, 2 invalid, 2 could not be read\n") {
+		t.Errorf("2.9 printText did not print the totals\n%s", buf.String())
+	}
+}
+
+// TestOutputFormats - This will test the machine readable output formats
+func TestOutputFormats(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(bad, []byte("{\n  \"type\": \"playbook\",,\n}"), 0600)
+	os.WriteFile(invalid, []byte("type: playbook\nname: 5x\nworkflow_start: start--1\n"), 0600)
+	results := validateFiles([]string{bad, invalid}, 2, false)
+
+	// Every result has the path, pointer, rule and severity, and the position
+	// of its property, or of the parent of a missing property
+	var doc struct {
+		Valid   bool
+		Results []outputResult
+	}
+	var buf bytes.Buffer
+	if valid, err := printJSON(&buf, results); valid || err != nil {
+		t.Fatalf("3.1 printJSON returned %t %v", valid, err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil || doc.Valid {
+		t.Fatalf("3.2 printJSON did not print a JSON document: %v", err)
+	}
+	for i, r := range doc.Results {
+		if r.Path == "" || r.Rule == "" || r.Severity == "" || r.Line == 0 || r.Column == 0 {
+			t.Errorf("3.3.%d the result is missing a property %+v", i, r)
+		}
+	}
+	if r := doc.Results[0]; r.Path != bad || r.Rule != decodeRule || r.Line != 2 || r.Column != 22 {
+		t.Errorf("3.4 the decode error is not correct %+v", r)
+	}
+	positions := map[string][2]int{}
+	for _, r := range doc.Results[1:] {
+		positions[r.Pointer] = [2]int{r.Line, r.Column}
+	}
+	if positions["/workflow_start"] != [2]int{3, 1} || positions["/workflow"] != [2]int{1, 1} {
+		t.Errorf("3.5 the positions of the findings are not correct %v", positions)
+	}
+
+	buf.Reset()
+	if _, err := printJUnit(&buf, results); err != nil {
+		t.Fatalf("3.6 printJUnit returned an error: %s", err)
+	}
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil || suites.Tests != 2 || suites.Failures != 1 || suites.Errors != 1 {
+		t.Errorf("3.7 printJUnit returned %+v %v\n%s", suites, err, buf.String())
+	}
+
+	buf.Reset()
+	if _, err := printSARIF(&buf, results); err != nil {
+		t.Fatalf("3.8 printSARIF returned an error: %s", err)
+	}
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil || log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("3.9 printSARIF did not print a SARIF log: %v", err)
+	}
+	sarif := log.Runs[0].Results
+	if len(sarif) != len(doc.Results) || sarif[0].Level != "error" || sarif[0].RuleID != decodeRule {
+		t.Errorf("3.10 the SARIF results are not correct %+v", sarif)
+	}
+	if loc := sarif[0].Locations[0].PhysicalLocation; loc.ArtifactLocation.URI != "file://"+filepath.ToSlash(bad) || loc.Region.StartLine != 2 {
+		t.Errorf("3.11 the SARIF location is not correct %+v", loc)
+	}
+}
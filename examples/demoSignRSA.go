@@ -3,6 +3,8 @@
 // Use of this source code is governed by an Apache 2.0 license that can be
 // found in the LICENSE file in the root of the source tree.
 
+//go:build ignore
+
 package main
 
 import (
@@ -1,10 +1,17 @@
 module github.com/openplaybooks/libcacao
 
-go 1.17
+go 1.24.0
 
 require (
 	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/google/uuid v1.3.0
 	github.com/gowebpki/jcs v1.0.0
 	github.com/pborman/getopt v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/cloudflare/circl v1.6.4
+	golang.org/x/crypto v0.45.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
 )
@@ -0,0 +1,106 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+import (
+	"fmt"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Define Functions and Methods
+// ----------------------------------------------------------------------
+
+// SetNewID - This method takes in a string value representing an object type
+// and creates a new ID based on the specification format and updates the id
+// property for the object.
+func (a *CommonProperties) SetNewID(objType string) error {
+
+	if !objects.IsVocabValueValid(objType, GetAgentTargetTypesVocab()) {
+		return fmt.Errorf("the object type %s is not a valid CACAO agent or target type", objType)
+	}
+
+	a.ID, _ = objects.CreateID(objType)
+	return nil
+}
+
+// GetID - This method returns the ID of the agent or target object
+func (a *CommonProperties) GetID() string {
+	return a.ID
+}
+
+// SetID - This method will set the ID of the object to the value passed in.
+// This is used when an agent or target is decoded from the playbook
+// dictionaries and the key needs to be restored.
+func (a *CommonProperties) SetID(id string) {
+	a.ID = id
+}
+
+// ClearID - This method will clear the ID from the object
+func (a *CommonProperties) ClearID() {
+	a.ID = ""
+}
+
+// NewLocation - This method creates a new empty civic location and returns a
+// reference to it so it can be populated. If a location already exists, a
+// reference to the existing location is returned.
+func (a *CommonProperties) NewLocation() *CivicLocation {
+	if a.Location == nil {
+		var l CivicLocation
+		a.Location = &l
+	}
+	return a.Location
+}
+
+// ----------------------------------------------------------------------
+// Define Functions and Methods - Contact
+// ----------------------------------------------------------------------
+
+// AddEmail - This method takes in a label, like "work", and an email address
+// and adds it to the email property.
+func (c *Contact) AddEmail(label, value string) error {
+	if c.Email == nil {
+		m := make(map[string]string, 0)
+		c.Email = m
+	}
+	c.Email[label] = value
+	return nil
+}
+
+// AddPhone - This method takes in a label, like "mobile", and a phone number
+// and adds it to the phone property.
+func (c *Contact) AddPhone(label, value string) error {
+	if c.Phone == nil {
+		m := make(map[string]string, 0)
+		c.Phone = m
+	}
+	c.Phone[label] = value
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// addAddress - This function takes in an address dictionary, a network address
+// type, and a string value, a comma separated list of string values, or a
+// slice of string values and adds them to the dictionary under that type.
+func addAddress(m *map[string][]string, addrType string, values interface{}) error {
+	if !objects.IsVocabValueValid(addrType, GetNetworkAddressTypesVocab()) {
+		return fmt.Errorf("the network address type %s is not valid", addrType)
+	}
+
+	if *m == nil {
+		*m = make(map[string][]string, 0)
+	}
+	temp := (*m)[addrType]
+	if err := objects.AddValuesToList(&temp, values); err != nil {
+		return err
+	}
+	(*m)[addrType] = temp
+	return nil
+}
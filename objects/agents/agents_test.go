@@ -0,0 +1,125 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestNewAgents - This will test the constructors for each agent and target
+// type
+func TestNewAgents(t *testing.T) {
+	constructors := map[string]func() (AgentObject, error){
+		"individual":        func() (AgentObject, error) { return NewIndividual() },
+		"group":             func() (AgentObject, error) { return NewGroup() },
+		"organization":      func() (AgentObject, error) { return NewOrganization() },
+		"sector":            func() (AgentObject, error) { return NewSector() },
+		"location":          func() (AgentObject, error) { return NewLocation() },
+		"security-category": func() (AgentObject, error) { return NewSecurityCategory() },
+		"net-address":       func() (AgentObject, error) { return NewNetAddress() },
+		"ssh-cli":           func() (AgentObject, error) { return NewSSHCLI() },
+		"http-api":          func() (AgentObject, error) { return NewHTTPAPI() },
+		"linux":             func() (AgentObject, error) { return NewLinux() },
+		"linux-cli":         func() (AgentObject, error) { return NewLinuxCLI() },
+	}
+
+	if len(constructors) != len(GetAgentTargetTypesVocab()) {
+		t.Errorf("1.1 there is not a constructor for each type in the vocabulary")
+	}
+
+	for objType, create := range constructors {
+		a, err := create()
+		if err != nil {
+			t.Errorf("1.2 unable to create %s: %s", objType, err)
+			continue
+		}
+		common := a.GetCommon()
+		if common.ObjectType != objType || !strings.HasPrefix(common.ID, objType+"--") {
+			t.Errorf("1.3 the %s object has a type of %s and an id of %s", objType, common.ObjectType, common.ID)
+		}
+
+		// The ID is only used as the dictionary key and is never serialized
+		data, _ := json.Marshal(a)
+		if strings.Contains(string(data), common.ID) {
+			t.Errorf("1.4 the id of the %s object was serialized %s", objType, data)
+		}
+
+		a.ClearID()
+		if a.GetCommon().ID != "" {
+			t.Errorf("1.5 ClearID did not clear the id of the %s object", objType)
+		}
+		a.SetID("individual--1")
+		if a.GetCommon().ID != "individual--1" {
+			t.Errorf("1.6 SetID did not set the id of the %s object", objType)
+		}
+	}
+}
+
+// TestSetNewID - This will test creating an ID for an agent or target
+func TestSetNewID(t *testing.T) {
+	var a CommonProperties
+
+	if err := a.SetNewID("cooking"); err == nil || a.ID != "" {
+		t.Errorf("2.1 SetNewID did not return an error for a type that is not valid")
+	}
+	if err := a.SetNewID("linux"); err != nil || !strings.HasPrefix(a.GetID(), "linux--") {
+		t.Errorf("2.2 SetNewID returned %v and an id of %s", err, a.GetID())
+	}
+}
+
+// TestContactAndLocation - This will test the contact and civic location
+// setters
+func TestContactAndLocation(t *testing.T) {
+	a, _ := NewIndividual()
+
+	c := a.NewContact()
+	c.AddEmail("work", "jdoe@example.com")
+	c.AddPhone("mobile", "+1-555-0100")
+	if a.NewContact() != c {
+		t.Errorf("3.1 NewContact did not return the existing contact")
+	}
+	if a.Contact.Email["work"] != "jdoe@example.com" || a.Contact.Phone["mobile"] != "+1-555-0100" {
+		t.Errorf("3.2 the contact is not correct %+v", a.Contact)
+	}
+
+	l := a.NewLocation()
+	l.Country = "US"
+	if a.NewLocation() != l || a.Location.Country != "US" {
+		t.Errorf("3.3 NewLocation did not return the existing location")
+	}
+}
+
+// TestAddAddress - This will test adding network addresses and categories
+func TestAddAddress(t *testing.T) {
+	a, _ := NewNetAddress()
+
+	if err := a.AddAddress("ipv4", "10.0.0.1,10.0.0.2"); err != nil {
+		t.Errorf("4.1 AddAddress returned an error: %s", err)
+	}
+	if err := a.AddAddress("ipv4", []string{"10.0.0.3"}); err != nil {
+		t.Errorf("4.2 AddAddress returned an error: %s", err)
+	}
+	if got := a.Address["ipv4"]; len(got) != 3 || got[2] != "10.0.0.3" {
+		t.Errorf("4.3 the ipv4 addresses are not correct %v", got)
+	}
+	if err := a.AddAddress("ipv5", "10.0.0.4"); err == nil {
+		t.Errorf("4.4 AddAddress did not return an error for an address type that is not valid")
+	}
+	if _, found := a.Address["ipv5"]; found {
+		t.Errorf("4.5 the address type that is not valid was added")
+	}
+
+	if err := a.AddCategory("server, firewall"); err != nil || len(a.Category) != 2 {
+		t.Errorf("4.6 AddCategory returned %v and %v", err, a.Category)
+	}
+
+	loc, _ := NewLocation()
+	if err := loc.AddLogical([]string{"dmz", "internal"}); err != nil || len(loc.Logical) != 2 {
+		t.Errorf("4.7 AddLogical returned %v and %v", err, loc.Logical)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package agents implements the CACAO 2.0 agent and target objects.
+//
+// CACAO agent and target objects contain detailed information about the
+// entities or systems that are either executing the commands in a playbook
+// (agents) or the entities or systems that the commands are being executed
+// against (targets). Agents and targets are stored in a dictionary on the
+// playbook (see the agent_definitions and target_definitions properties in
+// section 3.1), where the key is the agent or target ID and the value is an
+// agent or target object.
+//
+// Both agents and targets use the same set of object types, so this package
+// defines a single AgentObject interface that is used for both dictionaries.
+// Agent and target types MAY be extended with agent-target extensions. The
+// agent and target types are an open vocabulary, so an object with a type that
+// is not defined by the specification is decoded into a Generic object.
+package agents
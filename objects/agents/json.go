@@ -0,0 +1,109 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode - This function will decode a single agent or target into the
+// concrete type identified by its type property and return it as an
+// AgentObject. The agent and target types are an open vocabulary, so an object
+// with a type that is not defined by the specification is decoded into a
+// Generic object. The id value is the dictionary key that the object was
+// stored under in the playbook and it is restored into the ID property of the
+// object.
+func Decode(id string, data []byte) (AgentObject, error) {
+	var common CommonProperties
+	if err := json.Unmarshal(data, &common); err != nil {
+		return nil, err
+	}
+
+	var a AgentObject
+	switch common.ObjectType {
+	case "individual":
+		a = new(Individual)
+	case "group":
+		a = new(Group)
+	case "organization":
+		a = new(Organization)
+	case "sector":
+		a = new(Sector)
+	case "location":
+		a = new(Location)
+	case "security-category":
+		a = new(SecurityCategory)
+	case "net-address":
+		a = new(NetAddress)
+	case "ssh-cli":
+		a = new(SSHCLI)
+	case "http-api":
+		a = new(HTTPAPI)
+	case "linux":
+		a = new(Linux)
+	case "linux-cli":
+		a = new(LinuxCLI)
+	default:
+		if common.ObjectType == "" {
+			return nil, fmt.Errorf("the agent or target %s does not have a type", id)
+		}
+		a = new(Generic)
+	}
+
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	a.SetID(id)
+
+	return a, nil
+}
+
+// ----------------------------------------------------------------------
+// Generic Type Methods
+// ----------------------------------------------------------------------
+
+// MarshalJSON - This method will over write the default MarshalJSON method so
+// that the properties that are not common are encoded next to the common
+// properties.
+func (a *Generic) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(a.CommonProperties)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range a.Properties {
+		if _, found := m[k]; !found {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON - This method will over write the default UnmarshalJSON method
+// so that the common properties are decoded and every other property is kept
+// as raw JSON.
+func (a *Generic) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &a.CommonProperties); err != nil {
+		return err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "name", "description", "location"} {
+		delete(m, k)
+	}
+	if len(m) > 0 {
+		a.Properties = m
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+import "encoding/json"
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// AgentObject - This interface defines an agent or target object. I needed to
+// add the SetID() and ClearID() functions to the interface to make sure I could
+// call them on an object that is defined as fullfilling this interface.
+type AgentObject interface {
+	GetCommon() CommonProperties
+	SetID(id string)
+	ClearID()
+}
+
+// CommonProperties - Each agent and target object contains some base
+// properties that are common across all agent and target types. These common
+// properties are defined in the following type. The ID property here is just to
+// help make processing easier, it is never serialized since the specification
+// only has the ID at the dictionary level.
+type CommonProperties struct {
+	ObjectType  string         `json:"type,omitempty"`
+	ID          string         `json:"-"`
+	Name        string         `json:"name,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Location    *CivicLocation `json:"location,omitempty"`
+}
+
+// Contact - This type implements the CACAO 2.0 contact data type. The contact
+// information data type captures general contact information and uses the JSON
+// object type for serialization. The email and phone properties are
+// dictionaries where the key is a label for the value, for example "work" or
+// "mobile".
+type Contact struct {
+	Email          map[string]string `json:"email,omitempty"`
+	Phone          map[string]string `json:"phone,omitempty"`
+	ContactDetails string            `json:"contact_details,omitempty"`
+}
+
+// CivicLocation - This type implements the CACAO 2.0 civic location data type.
+// The civic location data type captures a physical location and uses the JSON
+// object type for serialization.
+type CivicLocation struct {
+	Name               string `json:"name,omitempty"`
+	Description        string `json:"description,omitempty"`
+	BuildingDetails    string `json:"building_details,omitempty"`
+	NetworkDetails     string `json:"network_details,omitempty"`
+	Region             string `json:"region,omitempty"`
+	Country            string `json:"country,omitempty"`
+	AdministrativeArea string `json:"administrative_area,omitempty"`
+	City               string `json:"city,omitempty"`
+	StreetAddress      string `json:"street_address,omitempty"`
+	PostalCode         string `json:"postal_code,omitempty"`
+	Latitude           string `json:"latitude,omitempty"`
+	Longitude          string `json:"longitude,omitempty"`
+	Precision          string `json:"precision,omitempty"`
+}
+
+// Individual - This type implements the CACAO 2.0 individual agent / target
+// and defines all of the properties associated with the individual type.
+//
+// The individual agent / target object represents a single person that is
+// either processing the commands in a playbook or is the target of the
+// commands, for example a manual step that is sent to an analyst.
+type Individual struct {
+	CommonProperties
+	Contact *Contact `json:"contact,omitempty"`
+}
+
+// Group - This type implements the CACAO 2.0 group agent / target and defines
+// all of the properties associated with the group type.
+//
+// The group agent / target object represents a group of people, for example
+// the SOC tier 1 analysts or the network operations team.
+type Group struct {
+	CommonProperties
+	Contact *Contact `json:"contact,omitempty"`
+}
+
+// Organization - This type implements the CACAO 2.0 organization agent /
+// target and defines all of the properties associated with the organization
+// type.
+//
+// The organization agent / target object represents a company or legal
+// entity. The sector property SHOULD come from the industry sectors
+// vocabulary.
+type Organization struct {
+	CommonProperties
+	Sector  string   `json:"sector,omitempty"`
+	Contact *Contact `json:"contact,omitempty"`
+}
+
+// Sector - This type implements the CACAO 2.0 sector agent / target and
+// defines all of the properties associated with the sector type.
+//
+// The sector agent / target object represents an entire industry sector, for
+// example all financial institutions. The sector property SHOULD come from the
+// industry sectors vocabulary.
+type Sector struct {
+	CommonProperties
+	Sector string `json:"sector,omitempty"`
+}
+
+// Location - This type implements the CACAO 2.0 location agent / target and
+// defines all of the properties associated with the location type.
+//
+// The location agent / target object represents a physical or logical
+// location. The physical location is captured in the common location property
+// and the logical property captures one or more logical locations, for example
+// a specific data center zone or cloud region.
+type Location struct {
+	CommonProperties
+	Logical []string `json:"logical,omitempty"`
+}
+
+// SecurityCategory - This type implements the CACAO 2.0 security category
+// agent / target and defines all of the properties associated with the
+// security category type.
+//
+// The security category agent / target object represents a category of
+// security infrastructure, for example all firewalls or all SIEMs, without
+// identifying a specific device. The category values SHOULD come from the
+// security category vocabulary.
+type SecurityCategory struct {
+	CommonProperties
+	Category []string `json:"category,omitempty"`
+}
+
+// NetAddress - This type implements the CACAO 2.0 network address agent /
+// target and defines all of the properties associated with the network
+// address type.
+//
+// The network address agent / target object represents one or more systems
+// that are reachable on the network. The address property is a dictionary
+// where the key comes from the network address type vocabulary and the value
+// is a list of addresses of that type.
+type NetAddress struct {
+	CommonProperties
+	Address  map[string][]string `json:"address,omitempty"`
+	Port     string              `json:"port,omitempty"`
+	Category []string            `json:"category,omitempty"`
+}
+
+// SSHCLI - This type implements the CACAO 2.0 SSH CLI agent / target and
+// defines all of the properties associated with the SSH CLI type.
+//
+// The SSH CLI agent / target object represents a system that commands are sent
+// to over SSH. The authentication_info property is the ID of an
+// authentication information object defined in the playbook.
+type SSHCLI struct {
+	CommonProperties
+	Address            map[string][]string `json:"address,omitempty"`
+	Port               string              `json:"port,omitempty"`
+	AuthenticationInfo string              `json:"authentication_info,omitempty"`
+	Category           []string            `json:"category,omitempty"`
+}
+
+// HTTPAPI - This type implements the CACAO 2.0 HTTP API agent / target and
+// defines all of the properties associated with the HTTP API type.
+//
+// The HTTP API agent / target object represents a system that commands are
+// sent to over an HTTP based API. The authentication_info property is the ID
+// of an authentication information object defined in the playbook.
+type HTTPAPI struct {
+	CommonProperties
+	Address            map[string][]string `json:"address,omitempty"`
+	Port               string              `json:"port,omitempty"`
+	AuthenticationInfo string              `json:"authentication_info,omitempty"`
+	Category           []string            `json:"category,omitempty"`
+}
+
+// Linux - This type implements the CACAO 2.0 Linux agent / target and defines
+// all of the properties associated with the Linux type.
+//
+// The Linux agent / target object represents a Linux based system that
+// commands are executed on.
+type Linux struct {
+	CommonProperties
+	Address            map[string][]string `json:"address,omitempty"`
+	Port               string              `json:"port,omitempty"`
+	AuthenticationInfo string              `json:"authentication_info,omitempty"`
+	Category           []string            `json:"category,omitempty"`
+}
+
+// LinuxCLI - This type implements the CACAO 2.0 Linux CLI agent / target and
+// defines all of the properties associated with the Linux CLI type.
+//
+// The Linux CLI agent / target object represents a command line interface on
+// a Linux based system that commands are executed in.
+type LinuxCLI struct {
+	CommonProperties
+	Address            map[string][]string `json:"address,omitempty"`
+	Port               string              `json:"port,omitempty"`
+	AuthenticationInfo string              `json:"authentication_info,omitempty"`
+	Category           []string            `json:"category,omitempty"`
+}
+
+// Generic - This type holds an agent or target with a type that is not
+// defined by the specification. The agent and target types are an open
+// vocabulary, so these objects are kept. The common properties are decoded and
+// every other property is kept as raw JSON, so the object can be encoded again
+// without loss.
+type Generic struct {
+	CommonProperties
+	Properties map[string]json.RawMessage `json:"-"`
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewIndividual - This will create and initialize a new individual agent /
+// target object and return it as a pointer.
+func NewIndividual() (*Individual, error) {
+	var a Individual
+	a.ObjectType = "individual"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewGroup - This will create and initialize a new group agent / target object
+// and return it as a pointer.
+func NewGroup() (*Group, error) {
+	var a Group
+	a.ObjectType = "group"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewOrganization - This will create and initialize a new organization agent /
+// target object and return it as a pointer.
+func NewOrganization() (*Organization, error) {
+	var a Organization
+	a.ObjectType = "organization"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewSector - This will create and initialize a new sector agent / target
+// object and return it as a pointer.
+func NewSector() (*Sector, error) {
+	var a Sector
+	a.ObjectType = "sector"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewLocation - This will create and initialize a new location agent / target
+// object and return it as a pointer.
+func NewLocation() (*Location, error) {
+	var a Location
+	a.ObjectType = "location"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewSecurityCategory - This will create and initialize a new security
+// category agent / target object and return it as a pointer.
+func NewSecurityCategory() (*SecurityCategory, error) {
+	var a SecurityCategory
+	a.ObjectType = "security-category"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewNetAddress - This will create and initialize a new network address agent
+// / target object and return it as a pointer.
+func NewNetAddress() (*NetAddress, error) {
+	var a NetAddress
+	a.ObjectType = "net-address"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewSSHCLI - This will create and initialize a new SSH CLI agent / target
+// object and return it as a pointer.
+func NewSSHCLI() (*SSHCLI, error) {
+	var a SSHCLI
+	a.ObjectType = "ssh-cli"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewHTTPAPI - This will create and initialize a new HTTP API agent / target
+// object and return it as a pointer.
+func NewHTTPAPI() (*HTTPAPI, error) {
+	var a HTTPAPI
+	a.ObjectType = "http-api"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewLinux - This will create and initialize a new Linux agent / target object
+// and return it as a pointer.
+func NewLinux() (*Linux, error) {
+	var a Linux
+	a.ObjectType = "linux"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewLinuxCLI - This will create and initialize a new Linux CLI agent / target
+// object and return it as a pointer.
+func NewLinuxCLI() (*LinuxCLI, error) {
+	var a LinuxCLI
+	a.ObjectType = "linux-cli"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
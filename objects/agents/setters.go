@@ -0,0 +1,232 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+import (
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Define Individual Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Individual) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// NewContact - This method creates a new empty contact and returns a reference
+// to it so it can be populated.
+func (a *Individual) NewContact() *Contact {
+	if a.Contact == nil {
+		var c Contact
+		a.Contact = &c
+	}
+	return a.Contact
+}
+
+// ----------------------------------------------------------------------
+// Define Group Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Group) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// NewContact - This method creates a new empty contact and returns a reference
+// to it so it can be populated.
+func (a *Group) NewContact() *Contact {
+	if a.Contact == nil {
+		var c Contact
+		a.Contact = &c
+	}
+	return a.Contact
+}
+
+// ----------------------------------------------------------------------
+// Define Organization Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Organization) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// NewContact - This method creates a new empty contact and returns a reference
+// to it so it can be populated.
+func (a *Organization) NewContact() *Contact {
+	if a.Contact == nil {
+		var c Contact
+		a.Contact = &c
+	}
+	return a.Contact
+}
+
+// ----------------------------------------------------------------------
+// Define Sector Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Sector) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define Location Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Location) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddLogical - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a logical
+// location and adds them to the logical property.
+func (a *Location) AddLogical(values interface{}) error {
+	return objects.AddValuesToList(&a.Logical, values)
+}
+
+// ----------------------------------------------------------------------
+// Define Security Category Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *SecurityCategory) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *SecurityCategory) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define Net Address Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *NetAddress) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddAddress - This method takes in a network address type, like "ipv4", and a
+// string value, a comma separated list of string values, or a slice of string
+// values and adds them to the address property.
+func (a *NetAddress) AddAddress(addrType string, values interface{}) error {
+	return addAddress(&a.Address, addrType, values)
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *NetAddress) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define SSH CLI Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *SSHCLI) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddAddress - This method takes in a network address type, like "ipv4", and a
+// string value, a comma separated list of string values, or a slice of string
+// values and adds them to the address property.
+func (a *SSHCLI) AddAddress(addrType string, values interface{}) error {
+	return addAddress(&a.Address, addrType, values)
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *SSHCLI) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define HTTP API Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *HTTPAPI) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddAddress - This method takes in a network address type, like "url", and a
+// string value, a comma separated list of string values, or a slice of string
+// values and adds them to the address property.
+func (a *HTTPAPI) AddAddress(addrType string, values interface{}) error {
+	return addAddress(&a.Address, addrType, values)
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *HTTPAPI) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define Linux Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Linux) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddAddress - This method takes in a network address type, like "ipv4", and a
+// string value, a comma separated list of string values, or a slice of string
+// values and adds them to the address property.
+func (a *Linux) AddAddress(addrType string, values interface{}) error {
+	return addAddress(&a.Address, addrType, values)
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *Linux) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define Linux CLI Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *LinuxCLI) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// AddAddress - This method takes in a network address type, like "ipv4", and a
+// string value, a comma separated list of string values, or a slice of string
+// values and adds them to the address property.
+func (a *LinuxCLI) AddAddress(addrType string, values interface{}) error {
+	return addAddress(&a.Address, addrType, values)
+}
+
+// AddCategory - This method takes in a string value, a comma separated list of
+// string values, or a slice of string values that all represent a security
+// category and adds them to the category property.
+func (a *LinuxCLI) AddCategory(values interface{}) error {
+	return objects.AddValuesToList(&a.Category, values)
+}
+
+// ----------------------------------------------------------------------
+// Define Generic Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common agent properties
+func (a *Generic) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package agents
+
+// GetAgentTargetTypesVocab - This will return a slice of officially supported
+// agent and target types
+func GetAgentTargetTypesVocab() []string {
+	return []string{
+		"individual",
+		"group",
+		"organization",
+		"sector",
+		"location",
+		"security-category",
+		"net-address",
+		"ssh-cli",
+		"http-api",
+		"linux",
+		"linux-cli",
+	}
+}
+
+// GetSecurityCategoryTypesVocab - This will return a slice of officially
+// supported security category types
+func GetSecurityCategoryTypesVocab() []string {
+	return []string{
+		"aaa",
+		"acl",
+		"analytics",
+		"av",
+		"deception",
+		"dlp",
+		"dns",
+		"edr",
+		"email",
+		"firewall",
+		"ids",
+		"ips",
+		"proxy",
+		"router",
+		"sandbox",
+		"siem",
+		"sip",
+		"switch",
+		"vpn",
+		"waf",
+	}
+}
+
+// GetNetworkAddressTypesVocab - This will return a slice of officially
+// supported network address types that can be used as keys in the address
+// property
+func GetNetworkAddressTypesVocab() []string {
+	return []string{
+		"dname",
+		"ipv4",
+		"ipv6",
+		"l2mac",
+		"vlan",
+		"url",
+	}
+}
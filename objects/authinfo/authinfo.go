@@ -0,0 +1,106 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package authinfo
+
+import (
+	"fmt"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Define Functions and Methods
+// ----------------------------------------------------------------------
+
+// SetNewID - This method takes in a string value representing an object type
+// and creates a new ID based on the specification format and updates the id
+// property for the object.
+func (a *CommonProperties) SetNewID(objType string) error {
+
+	if !objects.IsVocabValueValid(objType, GetAuthInfoTypesVocab()) {
+		return fmt.Errorf("the object type %s is not a valid CACAO authentication information type", objType)
+	}
+
+	a.ID, _ = objects.CreateID(objType)
+	return nil
+}
+
+// SetID - This method will set the ID of the object to the value passed in.
+// This is used when an object is decoded from the playbook dictionary and the
+// key needs to be restored.
+func (a *CommonProperties) SetID(id string) {
+	a.ID = id
+}
+
+// ClearID - This method will clear the ID from the object
+func (a *CommonProperties) ClearID() {
+	a.ID = ""
+}
+
+// GetID - This method returns the ID of the authentication information object
+func (a *CommonProperties) GetID() string {
+	return a.ID
+}
+
+// ----------------------------------------------------------------------
+// Define HTTP Basic Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *HTTPBasic) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define User Auth Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *UserAuth) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define Token Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *Token) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define OAuth2 Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *OAuth2) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define Kerberos Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *Kerberos) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
+
+// ----------------------------------------------------------------------
+// Define Private Key Functions and Methods
+// ----------------------------------------------------------------------
+
+// GetCommon - This method returns the common authentication information
+// properties
+func (a *PrivateKey) GetCommon() CommonProperties {
+	return a.CommonProperties
+}
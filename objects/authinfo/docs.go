@@ -0,0 +1,20 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package authinfo implements the CACAO 2.0 authentication information objects.
+//
+// CACAO authentication information objects contain detailed information that
+// is used to authenticate to an agent or target, for example the username and
+// password for an http-api target or the private key for an ssh-cli target.
+// Authentication information objects are stored in a dictionary on the
+// playbook (see the authentication_info_definitions property in section 3.1),
+// where the key is the authentication information ID and the value is an
+// authentication information object. Agents and targets refer to these objects
+// by their ID in the authentication_info property.
+//
+// Secrets SHOULD NOT be stored directly in a playbook that is shared. When the
+// kms property is set to true, the secret is stored in a key management system
+// and the kms_key_identifier property identifies where it can be found.
+package authinfo
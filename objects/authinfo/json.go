@@ -0,0 +1,48 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package authinfo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode - This function will decode a single authentication information
+// object into the concrete type identified by its type property and return it
+// as an AuthInfoObject. The id value is the dictionary key that the object was
+// stored under in the playbook and it is restored into the ID property of the
+// object.
+func Decode(id string, data []byte) (AuthInfoObject, error) {
+	var common CommonProperties
+	if err := json.Unmarshal(data, &common); err != nil {
+		return nil, err
+	}
+
+	var a AuthInfoObject
+	switch common.ObjectType {
+	case "http-basic":
+		a = new(HTTPBasic)
+	case "user-auth":
+		a = new(UserAuth)
+	case "token":
+		a = new(Token)
+	case "oauth2":
+		a = new(OAuth2)
+	case "kerberos":
+		a = new(Kerberos)
+	case "private-key":
+		a = new(PrivateKey)
+	default:
+		return nil, fmt.Errorf("the authentication information %s has a type of \"%s\" that is not supported", id, common.ObjectType)
+	}
+
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	a.SetID(id)
+
+	return a, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package authinfo
+
+import "github.com/openplaybooks/libcacao/objects"
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// AuthInfoObject - This interface defines an authentication information
+// object. The ClearID() function is part of the interface so that it can be
+// called when the object is added to a playbook.
+type AuthInfoObject interface {
+	GetCommon() CommonProperties
+	SetID(id string)
+	ClearID()
+	Valid(debug bool) (bool, int, []string)
+	Check(debug bool) []objects.Finding
+}
+
+// CommonProperties - Each authentication information object contains some base
+// properties that are common across all authentication information types.
+// These common properties are defined in the following type. The ID property
+// here is just to help make processing easier, it is never serialized since the
+// specification only has the ID at the dictionary level.
+type CommonProperties struct {
+	ObjectType  string `json:"type,omitempty"`
+	ID          string `json:"-"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// HTTPBasic - This type implements the CACAO 2.0 HTTP basic authentication
+// information object and defines all of the properties associated with the
+// http-basic type.
+//
+// The user_id property is required. The password property is required unless
+// the kms property is true, in which case the kms_key_identifier property MUST
+// be populated.
+type HTTPBasic struct {
+	CommonProperties
+	UserID           string `json:"user_id,omitempty"`
+	Password         string `json:"password,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// UserAuth - This type implements the CACAO 2.0 user authentication
+// information object and defines all of the properties associated with the
+// user-auth type.
+//
+// The username property is required. The password property is required unless
+// the kms property is true, in which case the kms_key_identifier property MUST
+// be populated.
+type UserAuth struct {
+	CommonProperties
+	Username         string `json:"username,omitempty"`
+	Password         string `json:"password,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// Token - This type implements the CACAO 2.0 token authentication information
+// object and defines all of the properties associated with the token type.
+//
+// The token property is required unless the kms property is true, in which
+// case the kms_key_identifier property MUST be populated.
+type Token struct {
+	CommonProperties
+	Token            string `json:"token,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// OAuth2 - This type implements the CACAO 2.0 OAuth2 authentication
+// information object and defines all of the properties associated with the
+// oauth2 type.
+//
+// The oauth_header property contains the full value of the HTTP Authorization
+// header, for example "Bearer <token>". It is required unless the kms property
+// is true, in which case the kms_key_identifier property MUST be populated.
+type OAuth2 struct {
+	CommonProperties
+	OAuthHeader      string `json:"oauth_header,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// Kerberos - This type implements the CACAO 2.0 Kerberos authentication
+// information object and defines all of the properties associated with the
+// kerberos type.
+//
+// The principal property is required. The keytab property contains a base64
+// encoded keytab file and is required unless the kms property is true, in
+// which case the kms_key_identifier property MUST be populated.
+type Kerberos struct {
+	CommonProperties
+	Principal        string `json:"principal,omitempty"`
+	Keytab           string `json:"keytab,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// PrivateKey - This type implements the CACAO 2.0 private key authentication
+// information object and defines all of the properties associated with the
+// private-key type.
+//
+// The private_key property contains a PEM encoded private key and is required
+// unless the kms property is true, in which case the kms_key_identifier
+// property MUST be populated.
+type PrivateKey struct {
+	CommonProperties
+	PrivateKey       string `json:"private_key,omitempty"`
+	KMS              bool   `json:"kms,omitempty"`
+	KMSKeyIdentifier string `json:"kms_key_identifier,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewHTTPBasic - This will create and initialize a new http basic
+// authentication information object and return it as a pointer.
+func NewHTTPBasic() (*HTTPBasic, error) {
+	var a HTTPBasic
+	a.ObjectType = "http-basic"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewUserAuth - This will create and initialize a new user authentication
+// information object and return it as a pointer.
+func NewUserAuth() (*UserAuth, error) {
+	var a UserAuth
+	a.ObjectType = "user-auth"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewToken - This will create and initialize a new token authentication
+// information object and return it as a pointer.
+func NewToken() (*Token, error) {
+	var a Token
+	a.ObjectType = "token"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewOAuth2 - This will create and initialize a new oauth2 authentication
+// information object and return it as a pointer.
+func NewOAuth2() (*OAuth2, error) {
+	var a OAuth2
+	a.ObjectType = "oauth2"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewKerberos - This will create and initialize a new kerberos authentication
+// information object and return it as a pointer.
+func NewKerberos() (*Kerberos, error) {
+	var a Kerberos
+	a.ObjectType = "kerberos"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
+
+// NewPrivateKey - This will create and initialize a new private key
+// authentication information object and return it as a pointer.
+func NewPrivateKey() (*PrivateKey, error) {
+	var a PrivateKey
+	a.ObjectType = "private-key"
+	err := a.SetNewID(a.ObjectType)
+	return &a, err
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package authinfo
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Valid - This method checks an http-basic object, user_id and password.
+func (a *HTTPBasic) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the http-basic checks as findings.
+func (a *HTTPBasic) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// Valid - This method checks a user-auth object, username and password.
+func (a *UserAuth) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the user-auth checks as findings.
+func (a *UserAuth) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// Valid - This method checks a token object and its token.
+func (a *Token) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the token checks as findings.
+func (a *Token) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// Valid - This method checks an oauth2 object and its oauth_header.
+func (a *OAuth2) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the oauth2 checks as findings.
+func (a *OAuth2) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// Valid - This method checks a kerberos object, principal and keytab.
+func (a *Kerberos) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the kerberos checks as findings.
+func (a *Kerberos) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// Valid - This method checks a private-key object and its private_key.
+func (a *PrivateKey) Valid(debug bool) (bool, int, []string) {
+	return a.validate(debug).Valid()
+}
+
+// Check - This method returns the private-key checks as findings.
+func (a *PrivateKey) Check(debug bool) []objects.Finding {
+	return a.validate(debug).Findings
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// checkRequired - This function will check that a required string property is
+// populated.
+func checkRequired(r *objects.Results, propertyName, value string) {
+	r.At("authentication_info."+propertyName, propertyName)
+	if value == "" {
+		r.RequiredButMissing(propertyName)
+		return
+	}
+	r.RequiredAndFound(propertyName)
+}
+
+// checkSecret - This function will check a secret bearing property. If the
+// secret is stored in a key management system, then the kms_key_identifier
+// property is required and the secret itself is optional. Otherwise the
+// secret is required. If a shape function is given, then a populated secret
+// must also satisfy it.
+func checkSecret(r *objects.Results, propertyName, value string, kms bool, kmsKeyIdentifier string, shape func(string) bool) {
+	if kms {
+		checkRequired(r, "kms_key_identifier", kmsKeyIdentifier)
+	} else {
+		if kmsKeyIdentifier != "" {
+			r.At("authentication_info.kms", "kms")
+			r.LogProblem("-- the kms_key_identifier property is populated but the kms property is not true")
+		}
+		checkRequired(r, propertyName, value)
+	}
+
+	if value != "" && shape != nil {
+		r.At("authentication_info."+propertyName, propertyName)
+		if shape(value) {
+			str := fmt.Sprintf("++ the %s property is correctly formatted", propertyName)
+			r.LogValid(str)
+		} else {
+			str := fmt.Sprintf("-- the %s property is not correctly formatted", propertyName)
+			r.LogProblem(str)
+		}
+	}
+}
+
+// isTokenValid - This function will check that a token does not contain any
+// whitespace.
+func isTokenValid(s string) bool {
+	return !strings.ContainsAny(s, " \t\r\n")
+}
+
+// isOAuthHeaderValid - This function will check that an OAuth header value is
+// made up of an authentication scheme, like Bearer, followed by the
+// credentials.
+func isOAuthHeaderValid(s string) bool {
+	re := regexp.MustCompile(`^[A-Za-z][A-Za-z0-9!#$%&'*+.^_|~-]* +[^\s]+$`)
+	return re.MatchString(s)
+}
+
+// isPrincipalValid - This function will check that a kerberos principal is in
+// the form of primary[/instance][@REALM].
+func isPrincipalValid(s string) bool {
+	re := regexp.MustCompile(`^[^\s/@]+(/[^\s/@]+)?(@[^\s@]+)?$`)
+	return re.MatchString(s)
+}
+
+// isKeytabValid - This function will check that a keytab is base64 encoded.
+func isKeytabValid(s string) bool {
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
+
+// isPrivateKeyValid - This function will check that a private key is PEM
+// encoded and that the PEM block contains a private key.
+func isPrivateKeyValid(s string) bool {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return false
+	}
+	return strings.HasSuffix(block.Type, "PRIVATE KEY")
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// These methods will run the checks for each type of authentication
+// information and return the results.
+
+func (a *HTTPBasic) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "http-basic")
+	checkRequired(r, "user_id", a.UserID)
+	checkSecret(r, "password", a.Password, a.KMS, a.KMSKeyIdentifier, nil)
+	return r
+}
+
+func (a *UserAuth) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "user-auth")
+	checkRequired(r, "username", a.Username)
+	checkSecret(r, "password", a.Password, a.KMS, a.KMSKeyIdentifier, nil)
+	return r
+}
+
+func (a *Token) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "token")
+	checkSecret(r, "token", a.Token, a.KMS, a.KMSKeyIdentifier, isTokenValid)
+	return r
+}
+
+func (a *OAuth2) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "oauth2")
+	checkSecret(r, "oauth_header", a.OAuthHeader, a.KMS, a.KMSKeyIdentifier, isOAuthHeaderValid)
+	return r
+}
+
+func (a *Kerberos) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "kerberos")
+	checkRequired(r, "principal", a.Principal)
+	if a.Principal != "" {
+		if isPrincipalValid(a.Principal) {
+			r.LogValid("++ the principal property contains a valid kerberos principal")
+		} else {
+			r.LogProblem("-- the principal property does not contain a valid kerberos principal")
+		}
+	}
+	checkSecret(r, "keytab", a.Keytab, a.KMS, a.KMSKeyIdentifier, isKeytabValid)
+	return r
+}
+
+func (a *PrivateKey) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	a.checkObjectType(r, "private-key")
+	checkSecret(r, "private_key", a.PrivateKey, a.KMS, a.KMSKeyIdentifier, isPrivateKeyValid)
+	return r
+}
+
+func (a *CommonProperties) checkObjectType(r *objects.Results, objType string) {
+	r.At("authentication_info.type", "type")
+
+	if a.ObjectType == "" {
+		r.RequiredButMissing("type")
+		return
+	}
+
+	r.RequiredAndFound("type")
+	if a.ObjectType != objType {
+		str := fmt.Sprintf("-- the type property does not contain a value of %s", objType)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the type property contains a valid type value of \"%s\"", a.ObjectType)
+		r.LogValid(str)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package authinfo
+
+// GetAuthInfoTypesVocab - This will return a slice of officially supported
+// authentication information types
+func GetAuthInfoTypesVocab() []string {
+	return []string{
+		"http-basic",
+		"user-auth",
+		"token",
+		"oauth2",
+		"kerberos",
+		"private-key",
+	}
+}
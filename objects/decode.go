@@ -0,0 +1,146 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// DecodeError - This type wraps an error from decoding JSON with the line and
+// column in the JSON where it was found. Both start at 1.
+type DecodeError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+// NestedError - This type wraps a syntax or type error from decoding a nested
+// object, like a workflow step, that was read from the document as raw JSON.
+// The offset in the error is into the object, so the JSON Pointer of the
+// object is kept to find where it starts in the document. Object says which
+// object it is, like "workflow step action--1".
+type NestedError struct {
+	Pointer string
+	Object  string
+	Err     error
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewDecodeError - This function will add the line and column to a syntax or
+// type error from decoding the JSON data, including one wrapped in a
+// *NestedError. For a syntax error this is the byte that could not be read,
+// and for a type error where the value starts. Other errors are returned as
+// they are.
+func NewDecodeError(data []byte, err error) error {
+	base := 0
+	inner := err
+	if e, ok := err.(*NestedError); ok {
+		_, value, found := jsonPointerOffsets(data, e.Pointer)
+		if !found {
+			return err
+		}
+		base, inner = value, e.Err
+	}
+
+	var offset int64
+	typeError := false
+	switch e := inner.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset, typeError = e.Offset, true
+	default:
+		return err
+	}
+	offset += int64(base)
+	if offset < 0 || offset > int64(len(data)) || len(data) == 0 {
+		return err
+	}
+
+	// The offset is just after the last byte that was read, which for a type
+	// error is the end of a scalar or the start of an object or array
+	at := int(offset)
+	if typeError {
+		at = valueStart(data, at)
+	} else if at > 0 {
+		at--
+	}
+	line, column := position(data, at)
+	return &DecodeError{Line: line, Column: column, Err: err}
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Error - This method will return the error with the line and column
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("line %d column %d: %s", e.Line, e.Column, e.Err)
+}
+
+// Unwrap - This method will return the error from the JSON decoder
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Error - This method will return the error with the object it was found in
+func (e *NestedError) Error() string {
+	return fmt.Sprintf("%s in %s", e.Err, e.Object)
+}
+
+// Unwrap - This method will return the error from the JSON decoder
+func (e *NestedError) Unwrap() error {
+	return e.Err
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// valueStart - This function will return the offset where the JSON value that
+// was read up to the end offset starts.
+func valueStart(data []byte, end int) int {
+	if end <= 0 {
+		return 0
+	}
+
+	switch data[end-1] {
+	case '{', '[':
+		return end - 1
+	case '"':
+		for i := end - 2; i >= 0; i-- {
+			if data[i] == '"' && !isEscaped(data, i) {
+				return i
+			}
+		}
+		return end - 1
+	}
+
+	i := end
+	for i > 0 && strings.IndexByte(" \t\r\n,:[{", data[i-1]) < 0 {
+		i--
+	}
+	return i
+}
+
+// isEscaped - This function will return true if the byte at the offset is
+// escaped by an odd number of backslashes.
+func isEscaped(data []byte, offset int) bool {
+	n := 0
+	for i := offset - 1; i >= 0 && data[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
@@ -0,0 +1,417 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// These are the operations that a Change can have. They are the operations of
+// the same name in a JSON Patch (RFC 6902).
+const (
+	ChangeAdd     = "add"
+	ChangeRemove  = "remove"
+	ChangeReplace = "replace"
+)
+
+// maxSummaryLength limits how much of a value is shown in a rendered change.
+const maxSummaryLength = 80
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// Change - This type represents a single difference between two JSON
+// documents. The path is a JSON Pointer (RFC 6901) in the form used by a JSON
+// Patch, so a value added to a list that is compared as a set has a path that
+// ends in "-". Old is the value in the first document, for remove and replace,
+// and Value is the value in the second document, for add and replace.
+type Change struct {
+	Op    string
+	Path  string
+	Old   interface{}
+	Value interface{}
+}
+
+// DiffOptions - This type says how the lists in the documents are compared.
+// The lists of the properties in SetLists are compared as sets, so the order
+// of their values does not matter. The lists of objects of the properties in
+// KeyedLists are paired by the value of a key property of the objects, like
+// "id", instead of by their index. Every other list is compared in order.
+type DiffOptions struct {
+	SetLists   []string
+	KeyedLists map[string]string
+}
+
+// patchOperation - This type is an operation in a JSON Patch.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// differ - This type holds the state of a comparison.
+type differ struct {
+	sets    map[string]bool
+	keys    map[string]string
+	changes []Change
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// DiffJSON - This function will compare two JSON documents and return the
+// changes that turn the first document into the second. Objects are paired
+// by their property names and lists are compared as the options say. The
+// changes are in the order of the properties, and applying them in order as a
+// JSON Patch gives the second document, except for the order of the values in
+// lists that are compared as sets or paired by a key.
+func DiffJSON(a, b []byte, opts *DiffOptions) ([]Change, error) {
+	va, err := decodeJSONValue(a)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := decodeJSONValue(b)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &differ{sets: make(map[string]bool), keys: make(map[string]string)}
+	if opts != nil {
+		for _, name := range opts.SetLists {
+			d.sets[name] = true
+		}
+		for name, key := range opts.KeyedLists {
+			d.keys[name] = key
+		}
+	}
+	d.diff(nil, "", va, vb)
+	return d.changes, nil
+}
+
+// JSONPatch - This function will encode the changes as a JSON Patch (RFC
+// 6902), indented with two spaces.
+func JSONPatch(changes []Change) ([]byte, error) {
+	ops := make([]patchOperation, 0, len(changes))
+	for _, c := range changes {
+		op := patchOperation{Op: c.Op, Path: c.Path}
+		if c.Op != ChangeRemove {
+			value, err := json.Marshal(c.Value)
+			if err != nil {
+				return nil, err
+			}
+			op.Value = value
+		}
+		ops = append(ops, op)
+	}
+	return json.MarshalIndent(ops, "", "  ")
+}
+
+// RenderChanges - This function will render a list of changes as a human
+// readable report with one line for each change.
+func RenderChanges(changes []Change) []string {
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	return lines
+}
+
+// SummarizeValue - This function returns a short form of a decoded JSON value
+// for a report. An object with a type and a name is shown by its type and name,
+// everything else as compact JSON that is cut off if it is too long.
+func SummarizeValue(v interface{}) string {
+	if obj, ok := v.(map[string]interface{}); ok {
+		objType, _ := obj["type"].(string)
+		name, _ := obj["name"].(string)
+		if objType != "" && name != "" {
+			return fmt.Sprintf("%s %q", objType, name)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) <= maxSummaryLength {
+		return string(data)
+	}
+
+	// Cut at a rune boundary so the summary stays valid UTF-8
+	cut := maxSummaryLength
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return string(data[:cut]) + "..."
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// String - This method will render the change as a line of a report. Added
+// values are prefixed with "+", removed values with "-" and replaced values
+// with "~". An object with a type and a name is shown by its type and name.
+func (c Change) String() string {
+	switch c.Op {
+	case ChangeAdd:
+		return fmt.Sprintf("+ %s: %s", c.Path, SummarizeValue(c.Value))
+	case ChangeRemove:
+		return fmt.Sprintf("- %s: %s", c.Path, SummarizeValue(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, SummarizeValue(c.Old), SummarizeValue(c.Value))
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// diff - This method will compare two values at the path given by the
+// tokens. The name is the property that holds the values, or empty for the
+// values in a list.
+func (d *differ) diff(tokens []string, name string, a, b interface{}) {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		if vb, ok := b.(map[string]interface{}); ok {
+			d.diffObject(tokens, va, vb)
+			return
+		}
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok {
+			switch {
+			case d.keys[name] != "" && d.diffKeyedList(tokens, d.keys[name], va, vb):
+			case d.sets[name]:
+				d.diffSet(tokens, va, vb)
+			default:
+				d.diffList(tokens, va, vb)
+			}
+			return
+		}
+	default:
+		if equalJSON(a, b) {
+			return
+		}
+	}
+	d.record(ChangeReplace, tokens, a, b)
+}
+
+// diffObject - This method will pair the properties of two objects by name.
+func (d *differ) diffObject(tokens []string, a, b map[string]interface{}) {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, found := a[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		va, inA := a[name]
+		vb, inB := b[name]
+		child := appendToken(tokens, name)
+		switch {
+		case !inB:
+			d.record(ChangeRemove, child, va, nil)
+		case !inA:
+			d.record(ChangeAdd, child, nil, vb)
+		default:
+			d.diff(child, name, va, vb)
+		}
+	}
+}
+
+// diffList - This method will compare two lists in order. Values that are
+// added are added at their index and values that are removed are removed
+// from the end, so the indexes stay correct as the changes are applied.
+func (d *differ) diffList(tokens []string, a, b []interface{}) {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		d.diff(appendToken(tokens, strconv.Itoa(i)), "", a[i], b[i])
+	}
+	for i := n; i < len(b); i++ {
+		d.record(ChangeAdd, appendToken(tokens, strconv.Itoa(i)), nil, b[i])
+	}
+	for i := len(a) - 1; i >= n; i-- {
+		d.record(ChangeRemove, appendToken(tokens, strconv.Itoa(i)), a[i], nil)
+	}
+}
+
+// diffSet - This method will compare two lists as sets. Each value is paired
+// with an equal value in the other list, if there is one. Values that are
+// removed are removed from the end first and values that are added are
+// appended.
+func (d *differ) diffSet(tokens []string, a, b []interface{}) {
+	used := make([]bool, len(b))
+	var removed []int
+	for i, va := range a {
+		found := false
+		for j, vb := range b {
+			if !used[j] && equalJSON(va, vb) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, i)
+		}
+	}
+
+	for i := len(removed) - 1; i >= 0; i-- {
+		d.record(ChangeRemove, appendToken(tokens, strconv.Itoa(removed[i])), a[removed[i]], nil)
+	}
+	for j, vb := range b {
+		if !used[j] {
+			d.record(ChangeAdd, appendToken(tokens, "-"), nil, vb)
+		}
+	}
+}
+
+// diffKeyedList - This method will pair the objects in two lists by the value
+// of their key property. It returns false, without recording any changes, if
+// a value is not an object with a unique string key, so the lists can be
+// compared another way.
+func (d *differ) diffKeyedList(tokens []string, key string, a, b []interface{}) bool {
+	keysA, okA := listKeys(a, key)
+	keysB, okB := listKeys(b, key)
+	if !okA || !okB {
+		return false
+	}
+	indexB := make(map[string]int, len(keysB))
+	for j, k := range keysB {
+		indexB[k] = j
+	}
+	found := make(map[string]bool, len(keysA))
+	for _, k := range keysA {
+		found[k] = true
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if _, ok := indexB[keysA[i]]; !ok {
+			d.record(ChangeRemove, appendToken(tokens, strconv.Itoa(i)), a[i], nil)
+		}
+	}
+
+	// The objects that are kept move up to fill the place of those removed
+	kept := 0
+	for i, k := range keysA {
+		if j, ok := indexB[k]; ok {
+			d.diff(appendToken(tokens, strconv.Itoa(kept)), "", a[i], b[j])
+			kept++
+		}
+	}
+
+	for j, k := range keysB {
+		if !found[k] {
+			d.record(ChangeAdd, appendToken(tokens, "-"), nil, b[j])
+		}
+	}
+	return true
+}
+
+// record - This method will add a change to the list.
+func (d *differ) record(op string, tokens []string, old, value interface{}) {
+	d.changes = append(d.changes, Change{Op: op, Path: JSONPointer(tokens...), Old: old, Value: value})
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// decodeJSONValue - This function will decode a JSON document, keeping the
+// numbers as they are written.
+func decodeJSONValue(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// appendToken - This function will return a new list of tokens with the token
+// added, without changing the list it was given.
+func appendToken(tokens []string, token string) []string {
+	child := make([]string, len(tokens)+1)
+	copy(child, tokens)
+	child[len(tokens)] = token
+	return child
+}
+
+// listKeys - This function returns the value of the key property of each
+// object in the list. It returns false if a value is not an object, does not
+// have a string key or has the same key as another object.
+func listKeys(list []interface{}, key string) ([]string, bool) {
+	keys := make([]string, len(list))
+	seen := make(map[string]bool, len(list))
+	for i, v := range list {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		k, ok := obj[key].(string)
+		if !ok || seen[k] {
+			return nil, false
+		}
+		keys[i], seen[k] = k, true
+	}
+	return keys, true
+}
+
+// equalJSON - This function returns true if two decoded JSON values are the
+// same. Numbers are equal if they have the same value, even if they are
+// written differently.
+func equalJSON(a, b interface{}) bool {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for k, v := range va {
+			if w, found := vb[k]; !found || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if !equalJSON(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		vb, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if va == vb {
+			return true
+		}
+		fa, errA := va.Float64()
+		fb, errB := vb.Float64()
+		return errA == nil && errB == nil && fa == fb
+	}
+	return a == b
+}
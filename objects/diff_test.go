@@ -0,0 +1,179 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// applyPatch - This will apply a JSON Patch with add, remove and replace
+// operations to a decoded JSON document
+func applyPatch(doc interface{}, patch []byte) (interface{}, error) {
+	var ops []struct {
+		Op    string
+		Path  string
+		Value interface{}
+	}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		tokens, ok := splitPointer(op.Path)
+		if !ok || len(tokens) == 0 {
+			return nil, fmt.Errorf("the path %q is not valid", op.Path)
+		}
+
+		// Find the parent of the target, remembering how to store a new list
+		var parent interface{} = doc
+		var setParent func(interface{}) = func(v interface{}) { doc = v }
+		for _, token := range tokens[:len(tokens)-1] {
+			switch p := parent.(type) {
+			case map[string]interface{}:
+				key := token
+				parent, setParent = p[key], func(v interface{}) { p[key] = v }
+			case []interface{}:
+				i, _ := strconv.Atoi(token)
+				parent, setParent = p[i], func(v interface{}) { p[i] = v }
+			}
+		}
+
+		last := tokens[len(tokens)-1]
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if op.Op == ChangeRemove {
+				delete(p, last)
+			} else {
+				p[last] = op.Value
+			}
+		case []interface{}:
+			i := len(p)
+			if last != "-" {
+				i, _ = strconv.Atoi(last)
+			}
+			switch op.Op {
+			case ChangeAdd:
+				p = append(p[:i], append([]interface{}{op.Value}, p[i:]...)...)
+			case ChangeRemove:
+				p = append(p[:i], p[i+1:]...)
+			default:
+				p[i] = op.Value
+			}
+			setParent(p)
+		default:
+			return nil, fmt.Errorf("the parent of %q is not a container", op.Path)
+		}
+	}
+	return doc, nil
+}
+
+// TestDiffJSON - This will test comparing objects, ordered lists, sets and
+// keyed lists and applying the changes as a JSON Patch
+func TestDiffJSON(t *testing.T) {
+	a := `{
+  "name": "Playbook 1",
+  "priority": 1.0,
+  "labels": ["a", "b", "c", "b"],
+  "commands": ["one", "two", "three"],
+  "steps": {"s1": {"type": "start"}, "s2": {"type": "end", "name": "End"}},
+  "signatures": [{"id": "jss--1", "value": "x"}, {"id": "jss--2", "value": "y"}],
+  "old": true
+}`
+	b := `{
+  "name": "Playbook/2",
+  "priority": 1,
+  "labels": ["c", "b", "d"],
+  "commands": ["one", "2"],
+  "steps": {"s1": {"type": "start", "on_completion": "s3"}, "s3": {"type": "action", "name": "Lookup"}},
+  "signatures": [{"id": "jss--2", "value": "z"}, {"id": "jss--3", "value": null}],
+  "new": null
+}`
+	opts := &DiffOptions{SetLists: []string{"labels"}, KeyedLists: map[string]string{"signatures": "id"}}
+
+	changes, err := DiffJSON([]byte(a), []byte(b), opts)
+	if err != nil {
+		t.Fatalf("1.1 DiffJSON returned an error: %s", err)
+	}
+	want := []string{
+		`~ /commands/1: "two" -> "2"`,
+		`- /commands/2: "three"`,
+		`- /labels/3: "b"`,
+		`- /labels/0: "a"`,
+		`+ /labels/-: "d"`,
+		`~ /name: "Playbook 1" -> "Playbook/2"`,
+		`+ /new: null`,
+		`- /old: true`,
+		`- /signatures/0: {"id":"jss--1","value":"x"}`,
+		`~ /signatures/0/value: "y" -> "z"`,
+		`+ /signatures/-: {"id":"jss--3","value":null}`,
+		`+ /steps/s1/on_completion: "s3"`,
+		`- /steps/s2: end "End"`,
+		`+ /steps/s3: action "Lookup"`,
+	}
+	if got := RenderChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("1.2 the changes are not correct\n%s", strings.Join(got, "\n"))
+	}
+
+	// Applying the patch to the first document gives the second one
+	patch, err := JSONPatch(changes)
+	if err != nil {
+		t.Fatalf("1.3 JSONPatch returned an error: %s", err)
+	}
+	var docA, docB interface{}
+	json.Unmarshal([]byte(a), &docA)
+	json.Unmarshal([]byte(b), &docB)
+	patched, err := applyPatch(docA, patch)
+	if err != nil {
+		t.Fatalf("1.4 unable to apply the patch: %s\n%s", err, patch)
+	}
+	// The values of a set can be in another order
+	for _, doc := range []interface{}{patched, docB} {
+		labels := doc.(map[string]interface{})["labels"].([]interface{})
+		sort.Slice(labels, func(i, j int) bool { return labels[i].(string) < labels[j].(string) })
+	}
+	if !reflect.DeepEqual(patched, docB) {
+		t.Errorf("1.5 the patched document is not the same as the second one\n%v\n%v", patched, docB)
+	}
+	if !strings.Contains(string(patch), `"value": null`) {
+		t.Errorf("1.6 a null value was left out of the patch\n%s", patch)
+	}
+
+	// Without options the lists are compared in order
+	changes, _ = DiffJSON([]byte(`{"labels": ["a", "b"]}`), []byte(`{"labels": ["b", "a"]}`), nil)
+	if len(changes) != 2 || changes[0].Path != "/labels/0" {
+		t.Errorf("1.7 the ordered list was not compared in order %v", changes)
+	}
+	changes, _ = DiffJSON([]byte(`{"labels": ["a", "b"]}`), []byte(`{"labels": ["b", "a"]}`), opts)
+	if len(changes) != 0 {
+		t.Errorf("1.8 the set was compared in order %v", changes)
+	}
+
+	// A keyed list with an object without a key is compared in order
+	changes, _ = DiffJSON([]byte(`{"signatures": [{"id": "1"}, {}]}`), []byte(`{"signatures": [{}, {"id": "1"}]}`), opts)
+	if len(changes) != 2 || changes[0].Path != "/signatures/0/id" {
+		t.Errorf("1.9 the keyed list was not compared in order %v", changes)
+	}
+
+	if _, err := DiffJSON([]byte(`{`), []byte(`{}`), nil); err == nil {
+		t.Errorf("1.10 DiffJSON did not return an error for JSON that is not valid")
+	}
+}
+
+// TestSummarizeValue - This will test the short form of values in a report
+func TestSummarizeValue(t *testing.T) {
+	if s := SummarizeValue(strings.Repeat("é", 60)); !strings.HasSuffix(s, "é...") || len(s) > maxSummaryLength+3 {
+		t.Errorf("2.1 the long value was not cut off at a rune boundary: %s", s)
+	}
+	if s := SummarizeValue(map[string]interface{}{"type": "start"}); s != `{"type":"start"}` {
+		t.Errorf("2.2 the object without a name is not correct: %s", s)
+	}
+}
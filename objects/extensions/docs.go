@@ -0,0 +1,25 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package extensions implements the CACAO 2.0 extension definition object and
+// the extension containers used by playbooks, workflow steps, and data
+// markings.
+//
+// An extension definition describes a vendor or community extension and
+// points to the JSON schema that defines it. The extension definitions are
+// stored in a dictionary on the playbook (see the extension_definitions
+// property in section 3.1), where the key is the extension definition ID.
+// Extensions are then used in the playbook_extensions, step_extensions, and
+// marking_extensions properties, which are dictionaries where the key is the
+// ID of the extension definition and the value is the extension content.
+//
+// Extension content is kept as raw JSON so that unknown extensions are never
+// lost when a playbook is decoded and encoded again. Go code that knows the
+// structure of an extension can register a struct for the extension
+// definition ID with Register. When a playbook is decoded, each extension with
+// a registered struct is checked by decoding it into that struct, so malformed
+// extensions are reported as errors, but the raw JSON is what is kept. Get
+// returns the extension decoded into a new instance of the registered struct.
+package extensions
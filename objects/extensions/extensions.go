@@ -0,0 +1,134 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package extensions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Extension Definition Type Methods
+// ----------------------------------------------------------------------
+
+// SetNewID - This method takes in a string value representing an object type
+// and creates a new ID based on the specification format and updates the id
+// property for the object.
+func (e *ExtensionDefinition) SetNewID(objType string) error {
+
+	if objType != "extension-definition" {
+		return errors.New("the object type is not valid for a CACAO extension definition id")
+	}
+
+	e.ID, _ = objects.CreateID(objType)
+	return nil
+}
+
+// SetID - This method will set the ID of the object to the value passed in.
+// This is used when a definition is decoded from the playbook dictionary and
+// the key needs to be restored.
+func (e *ExtensionDefinition) SetID(id string) {
+	e.ID = id
+}
+
+// GetID - This method returns the ID of the extension definition
+func (e *ExtensionDefinition) GetID() string {
+	return e.ID
+}
+
+// NewExternalReference - This method creates a new empty external reference and
+// returns a reference to it so it can be populated. However, if one or more
+// external references are passed in they are all added and the reference that
+// is returned is for the last entry added.
+func (e *ExtensionDefinition) NewExternalReference(r ...objects.ExternalReference) (*objects.ExternalReference, error) {
+	positionThatAppendWillUse := len(e.ExternalReferences)
+
+	if len(r) > 0 {
+		for i := range r {
+			// Update the value so we grab the last one entered
+			positionThatAppendWillUse = len(e.ExternalReferences)
+			e.ExternalReferences = append(e.ExternalReferences, r[i])
+		}
+		return &e.ExternalReferences[positionThatAppendWillUse], nil
+	}
+
+	// If one was not passed in, lets create one
+	var er objects.ExternalReference
+	e.ExternalReferences = append(e.ExternalReferences, er)
+	return &e.ExternalReferences[positionThatAppendWillUse], nil
+}
+
+// ----------------------------------------------------------------------
+// Extensions Type Methods
+// ----------------------------------------------------------------------
+
+// Add - This method takes in the ID of an extension definition and a value
+// that represents the extension, encodes the value to JSON, and adds it to the
+// dictionary. The value can be a registered struct, a map, or a
+// json.RawMessage.
+func (e *Extensions) Add(id string, v interface{}) error {
+	if id == "" {
+		return errors.New("an extension must be keyed by an extension definition id")
+	}
+
+	var data []byte
+	switch raw := v.(type) {
+	case json.RawMessage:
+		data = raw
+	default:
+		var err error
+		data, err = json.Marshal(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *e == nil {
+		m := make(Extensions, 0)
+		*e = m
+	}
+	(*e)[id] = data
+	return nil
+}
+
+// Get - This method takes in the ID of an extension definition and returns
+// the extension. If a struct has been registered for the ID, a pointer to a
+// new instance of that struct populated with the extension is returned,
+// otherwise the extension is returned as a map[string]interface{}.
+func (e Extensions) Get(id string) (interface{}, error) {
+	data, found := e[id]
+	if !found {
+		return nil, fmt.Errorf("the extension %s was not found", id)
+	}
+	return decode(id, data)
+}
+
+// UnmarshalJSON - This method will over write the default UnmarshalJSON method
+// so that every extension with a registered struct is decoded into that struct
+// when the dictionary is decoded. This makes sure that malformed extensions
+// are reported as errors. The raw JSON is always kept so that the extension
+// can be encoded again without loss.
+func (e *Extensions) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	for id, data := range m {
+		if _, found := lookup(id); !found {
+			continue
+		}
+		if _, err := decode(id, data); err != nil {
+			return err
+		}
+	}
+
+	*e = m
+	return nil
+}
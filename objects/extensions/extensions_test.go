@@ -0,0 +1,67 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package extensions
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testExtension struct {
+	Vendor string `json:"vendor,omitempty"`
+	Score  int    `json:"score,omitempty"`
+}
+
+type testContainer struct {
+	Extensions Extensions `json:"extensions,omitempty"`
+}
+
+// TestRegistry - This will test that registered extensions are decoded into
+// their struct and that unregistered extensions are kept as maps
+func TestRegistry(t *testing.T) {
+	id := "extension-definition--3c5a2f6e-5c3d-4f4e-9d0a-6f4f1c1d2e3f"
+	if err := Register(id, &testExtension{}); err != nil {
+		t.Fatalf("1.1 unable to register extension: %s", err)
+	}
+	defer Unregister(id)
+
+	if err := Register(id, "foo"); err == nil {
+		t.Errorf("1.2 registering a non struct type did not return an error")
+	}
+
+	var c testContainer
+	c.Extensions.Add(id, testExtension{Vendor: "ACME", Score: 7})
+	c.Extensions.Add("extension-definition--unknown", map[string]string{"foo": "bar"})
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("1.3 unable to encode extensions: %s", err)
+	}
+
+	var c2 testContainer
+	if err := json.Unmarshal(data, &c2); err != nil {
+		t.Fatalf("1.4 unable to decode extensions: %s", err)
+	}
+
+	v, err := c2.Extensions.Get(id)
+	if err != nil {
+		t.Fatalf("1.5 unable to get extension: %s", err)
+	}
+	if e, ok := v.(*testExtension); !ok || e.Vendor != "ACME" || e.Score != 7 {
+		t.Errorf("1.6 registered extension was not decoded into its struct, have %#v", v)
+	}
+
+	v, _ = c2.Extensions.Get("extension-definition--unknown")
+	if m, ok := v.(map[string]interface{}); !ok || m["foo"] != "bar" {
+		t.Errorf("1.7 unregistered extension was not decoded into a map, have %#v", v)
+	}
+
+	// A registered extension that does not match its struct is an error
+	bad := []byte(`{"extensions":{"` + id + `":{"score":"high"}}}`)
+	if err := json.Unmarshal(bad, &c2); err == nil {
+		t.Errorf("1.8 decoding a malformed registered extension did not return an error")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package extensions
+
+import (
+	"encoding/json"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// ExtensionDefinition - This type implements the CACAO 2.0 extension
+// definition object and defines all of the properties associated with it.
+// The ID property here is just to help make processing easier, it is never
+// serialized since the specification only has the ID at the dictionary level.
+//
+// The name, created_by, schema, and version properties are required. The
+// schema property contains the URL of the JSON schema for the extension.
+type ExtensionDefinition struct {
+	ObjectType         string                      `json:"type,omitempty"`
+	ID                 string                      `json:"-"`
+	Name               string                      `json:"name,omitempty"`
+	Description        string                      `json:"description,omitempty"`
+	CreatedBy          string                      `json:"created_by,omitempty"`
+	Schema             string                      `json:"schema,omitempty"`
+	Version            string                      `json:"version,omitempty"`
+	ExternalReferences []objects.ExternalReference `json:"external_references,omitempty"`
+}
+
+// Extensions - This type defines a dictionary of extensions where the key is
+// the ID of an extension definition and the value is the raw JSON of the
+// extension.
+type Extensions map[string]json.RawMessage
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// New - This function will create a new extension definition object and
+// return it as a pointer. It will also initialize the object by setting all of
+// the basic properties.
+func New() *ExtensionDefinition {
+	e := new(ExtensionDefinition)
+	e.ObjectType = "extension-definition"
+	e.SetNewID(e.ObjectType)
+	return e
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package extensions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// The registry maps an extension definition ID to the Go struct type that
+// the extension should be decoded into.
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]reflect.Type)
+)
+
+// Register - This function takes in the ID of an extension definition and a
+// struct, or a pointer to a struct, that represents that extension. Once
+// registered, Get returns the extension as a new instance of that struct, and
+// decoding a playbook, workflow step, or data marking returns an error if the
+// extension does not decode into it.
+func Register(id string, v interface{}) error {
+	if id == "" {
+		return errors.New("an extension must be registered with an extension definition id")
+	}
+
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return fmt.Errorf("the type registered for extension %s is nil", id)
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("the type registered for extension %s is not a struct", id)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[id] = t
+	return nil
+}
+
+// Unregister - This function will remove the struct registered for an
+// extension definition ID.
+func Unregister(id string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, id)
+}
+
+// IsRegistered - This function will return true if a struct has been
+// registered for the extension definition ID.
+func IsRegistered(id string) bool {
+	_, found := lookup(id)
+	return found
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// lookup - This function returns the struct type registered for an extension
+// definition ID.
+func lookup(id string) (reflect.Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, found := registry[id]
+	return t, found
+}
+
+// decode - This function will decode the raw JSON of an extension into the
+// struct registered for the ID, or into a map if nothing is registered.
+func decode(id string, data []byte) (interface{}, error) {
+	t, found := lookup(id)
+	if !found {
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	v := reflect.New(t).Interface()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("the extension %s could not be decoded: %s", id, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"fmt"
+	"strings"
+)
+
+// These are the severities that a Finding can have
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// Finding - This type represents a single result from a validation check. The
+// rule is a stable code that identifies the check that produced the finding,
+// and the pointer is a JSON Pointer (RFC 6901) to the property in the playbook
+// that the finding is about. Successful checks are reported with a severity of
+// info.
+type Finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+}
+
+// Results - This type is used to capture the results from the Valid(),
+// Check() and Compare() methods of the objects. The rule and pointer are set
+// by each check with At() before it logs anything so that every finding
+// records which check produced it and where in the object.
+type Results struct {
+	Debug         bool
+	ProblemsFound int
+	ResultDetails []string
+	Findings      []Finding
+	Rule          string
+	Pointer       string
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// NewFinding - This function will create a finding from a result detail
+// string. The "++" prefix is used for successful checks and the "--" prefix
+// for problems, the prefix is removed from the message.
+func NewFinding(detail, rule, pointer string) Finding {
+	f := Finding{Severity: SeverityError, Rule: rule, Pointer: pointer, Message: detail}
+
+	switch {
+	case strings.HasPrefix(detail, "++"):
+		f.Severity = SeverityInfo
+	case strings.HasPrefix(detail, "!!"):
+		f.Severity = SeverityWarning
+	case !strings.HasPrefix(detail, "--"):
+		return f
+	}
+	f.Message = strings.TrimSpace(detail[2:])
+	return f
+}
+
+// JSONPointer - This function will build a JSON Pointer from a list of
+// reference tokens, escaping each token as required by RFC 6901.
+func JSONPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		t = strings.ReplaceAll(t, "~", "~0")
+		t = strings.ReplaceAll(t, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// RenderFindings - This function will render a list of findings as the "++"
+// and "--" prefixed strings that are returned by the Valid() methods.
+func RenderFindings(findings []Finding) []string {
+	var details []string
+	for _, f := range findings {
+		details = append(details, f.String())
+	}
+	return details
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// String - This method will render the finding as a result detail string.
+// Problems are prefixed with "--", warnings with "!!", and successful checks
+// with "++".
+func (f Finding) String() string {
+	switch f.Severity {
+	case SeverityInfo:
+		return "++ " + f.Message
+	case SeverityWarning:
+		return "!! " + f.Message
+	}
+	return "-- " + f.Message
+}
+
+// IsProblem - This method returns true if the finding is an error.
+func (f Finding) IsProblem() bool {
+	return f.Severity == SeverityError
+}
+
+// ----------------------------------------------------------------------
+// Public Results Methods
+// ----------------------------------------------------------------------
+
+// At - This method will set the rule code and the JSON Pointer that are
+// recorded with the findings that are logged after it is called.
+func (r *Results) At(rule string, tokens ...string) {
+	r.Rule = rule
+	r.Pointer = JSONPointer(tokens...)
+}
+
+// LogProblem - This method will log a problem. Problems are always recorded.
+func (r *Results) LogProblem(msg string) {
+	r.ProblemsFound++
+	r.ResultDetails = append(r.ResultDetails, msg)
+	r.Findings = append(r.Findings, NewFinding(msg, r.Rule, r.Pointer))
+}
+
+// LogValid - This method will log a successful check, if debug is enabled.
+func (r *Results) LogValid(msg string) {
+	if r.Debug {
+		r.ResultDetails = append(r.ResultDetails, msg)
+		r.Findings = append(r.Findings, NewFinding(msg, r.Rule, r.Pointer))
+	}
+}
+
+// RequiredButMissing - This method will log a problem for a required property
+// that is missing.
+func (r *Results) RequiredButMissing(propertyName string) {
+	str := fmt.Sprintf("-- the %s property is required but missing", propertyName)
+	r.LogProblem(str)
+}
+
+// RequiredAndFound - This method will log that a required property is found.
+func (r *Results) RequiredAndFound(propertyName string) {
+	str := fmt.Sprintf("++ the %s property is required and is found", propertyName)
+	r.LogValid(str)
+}
+
+// LogNested - This method will log the findings of a nested object, like a
+// workflow step or a signature. Each finding keeps the rule code of the check
+// in the nested object, its JSON Pointer is added to the pointer of the nested
+// object given in tokens, and the location is added to its message. It
+// returns true if none of the findings are problems.
+func (r *Results) LogNested(findings []Finding, location string, tokens ...string) bool {
+	valid := true
+	pointer := JSONPointer(tokens...)
+	for _, f := range findings {
+		r.Rule = f.Rule
+		r.Pointer = pointer + f.Pointer
+		if f.IsProblem() {
+			valid = false
+			r.LogProblem(f.String() + " in " + location)
+		} else {
+			r.LogValid(f.String() + " in " + location)
+		}
+	}
+	return valid
+}
+
+// Valid - This method will return the results in the form that the Valid()
+// methods of the objects return them.
+func (r *Results) Valid() (bool, int, []string) {
+	if r.ProblemsFound > 0 {
+		return false, r.ProblemsFound, r.ResultDetails
+	}
+	return true, r.ProblemsFound, r.ResultDetails
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"testing"
+)
+
+// TestNewFinding - This will test that result detail strings are converted to
+// findings and rendered back to the same string
+func TestNewFinding(t *testing.T) {
+	tests := []struct {
+		detail   string
+		severity string
+		message  string
+	}{
+		{"-- the name property is required but missing", SeverityError, "the name property is required but missing"},
+		{"++ the name property is required and is found", SeverityInfo, "the name property is required and is found"},
+		{"!! the features property is deprecated", SeverityWarning, "the features property is deprecated"},
+	}
+
+	for i, test := range tests {
+		f := NewFinding(test.detail, "playbook.name", "/name")
+		if f.Severity != test.severity || f.Message != test.message {
+			t.Errorf("1.%d NewFinding returned %+v which is invalid", i, f)
+		}
+		if f.String() != test.detail {
+			t.Errorf("1.%d String returned %s instead of %s", i, f.String(), test.detail)
+		}
+	}
+}
+
+// TestJSONPointer - This will test that reference tokens are escaped
+func TestJSONPointer(t *testing.T) {
+	if p := JSONPointer("workflow", "action--1", "on_completion"); p != "/workflow/action--1/on_completion" {
+		t.Errorf("2.1 JSONPointer returned %s which is invalid", p)
+	}
+	if p := JSONPointer("cases", "a/b~c"); p != "/cases/a~1b~0c" {
+		t.Errorf("2.2 JSONPointer returned %s which is invalid", p)
+	}
+	if p := JSONPointer(); p != "" {
+		t.Errorf("2.3 JSONPointer returned %s which is invalid", p)
+	}
+}
+
+// TestResults - This will test that problems are always logged, successful
+// checks only with debug, and that nested findings are moved under a pointer
+func TestResults(t *testing.T) {
+	r := new(Results)
+	r.At("playbook.name", "name")
+	r.RequiredAndFound("name")
+	r.RequiredButMissing("name")
+	if valid, count, details := r.Valid(); valid || count != 1 || len(details) != 1 {
+		t.Errorf("3.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	if len(r.Findings) != 1 || r.Findings[0].Rule != "playbook.name" || r.Findings[0].Pointer != "/name" {
+		t.Errorf("3.2 the findings are not correct %+v", r.Findings)
+	}
+
+	r = &Results{Debug: true}
+	nested := []Finding{
+		NewFinding("++ the type property is required and is found", "workflow.step.type", "/type"),
+		NewFinding("-- the commands property is required but missing", "workflow.step.commands", "/commands"),
+	}
+	if r.LogNested(nested, "workflow step action--1", "workflow", "action--1") {
+		t.Errorf("3.3 LogNested returned true for findings with a problem")
+	}
+	if len(r.Findings) != 2 || r.Findings[1].Rule != "workflow.step.commands" || r.Findings[1].Pointer != "/workflow/action--1/commands" {
+		t.Errorf("3.4 the nested findings are not correct %+v", r.Findings)
+	}
+	if r.ResultDetails[1] != "-- the commands property is required but missing in workflow step action--1" {
+		t.Errorf("3.5 the nested result is not correct %s", r.ResultDetails[1])
+	}
+	if valid, count, _ := r.Valid(); valid || count != 1 {
+		t.Errorf("3.6 Valid returned %t with errors %d which is invalid", valid, count)
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package jwe implements JSON Web Encryption (RFC 7516) envelopes, so that
+// playbooks that are marked TLP:RED can be shared with specific recipients
+// without exposing them to anyone that carries them. The content is encrypted
+// once with AES-GCM and the content encryption key is wrapped for each
+// recipient with RSA-OAEP, RSA-OAEP-256 or ECDH-ES+A256KW (RFC 7518). Since
+// there can be more than one recipient, envelopes are written in the general
+// JSON serialization. The flattened JSON and compact serializations can be
+// decrypted as well.
+package jwe
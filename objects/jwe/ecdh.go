@@ -0,0 +1,198 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package jwe
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// JWK - This type defines the JSON Web Key (RFC 7517) form of the ephemeral
+// public key in the epk header. Only the members for EC (P-256, P-384, P-521)
+// and OKP (X25519) public keys are used.
+type JWK struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// ecdhPublicKey - This function will return the ECDH form of a public key
+func ecdhPublicKey(key interface{}) (*ecdh.PublicKey, error) {
+	switch k := key.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		return k.ECDH()
+	}
+	return nil, fmt.Errorf("%w: %T can not be used with ECDH-ES", ErrUnsupportedKey, key)
+}
+
+// ecdhPrivateKey - This function will return the ECDH form of a private key
+func ecdhPrivateKey(key interface{}) (*ecdh.PrivateKey, error) {
+	switch k := key.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k.ECDH()
+	}
+	return nil, fmt.Errorf("%w: %T can not be used with ECDH-ES", ErrUnsupportedKey, key)
+}
+
+// ecdhWrap - This function will create an ephemeral key on the curve of the
+// recipient public key, derive a key encryption key from the shared secret
+// and wrap the content encryption key with it. It returns the wrapped key and
+// the ephemeral public key.
+func ecdhWrap(random io.Reader, alg string, pub *ecdh.PublicKey, cek []byte) ([]byte, *JWK, error) {
+	ephemeral, err := pub.Curve().GenerateKey(random)
+	if err != nil {
+		return nil, nil, err
+	}
+	z, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	epk, err := newJWK(ephemeral.PublicKey())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err := wrapKey(concatKDF(z, alg, nil, nil, 256), cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	return wrapped, epk, nil
+}
+
+// ecdhUnwrap - This function will derive the key encryption key from the
+// private key and the ephemeral public key in the header and unwrap the
+// content encryption key with it.
+func ecdhUnwrap(priv *ecdh.PrivateKey, header *Header, wrapped []byte) ([]byte, error) {
+	if header.EphemeralKey == nil {
+		return nil, fmt.Errorf("%w: the epk header is missing", ErrInvalidEnvelope)
+	}
+	epk, err := header.EphemeralKey.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	if epk.Curve() != priv.Curve() {
+		return nil, fmt.Errorf("%w: the epk header is on a different curve", ErrInvalidEnvelope)
+	}
+	z, err := priv.ECDH(epk)
+	if err != nil {
+		return nil, err
+	}
+
+	apu, err := decodeSegment(header.PartyUInfo)
+	if err != nil {
+		return nil, err
+	}
+	apv, err := decodeSegment(header.PartyVInfo)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapKey(concatKDF(z, header.Algorithm, apu, apv, 256), wrapped)
+}
+
+// concatKDF - This function will derive a key from the shared secret with the
+// Concat KDF from NIST SP 800-56A using SHA-256, with the inputs defined in
+// RFC 7518 section 4.6.2. The key length is in bits.
+func concatKDF(z []byte, alg string, apu, apv []byte, keyLength int) []byte {
+	var otherInfo []byte
+	for _, v := range [][]byte{[]byte(alg), apu, apv} {
+		otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(v)))
+		otherInfo = append(otherInfo, v...)
+	}
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keyLength))
+
+	var key []byte
+	for counter := uint32(1); len(key) < keyLength/8; counter++ {
+		h := sha256.New()
+		binary.Write(h, binary.BigEndian, counter)
+		h.Write(z)
+		h.Write(otherInfo)
+		key = h.Sum(key)
+	}
+	return key[:keyLength/8]
+}
+
+// newJWK - This function will return the JWK form of an ECDH public key
+func newJWK(pub *ecdh.PublicKey) (*JWK, error) {
+	b := pub.Bytes()
+	switch pub.Curve() {
+	case ecdh.X25519():
+		return &JWK{KeyType: "OKP", Curve: "X25519", X: base64.RawURLEncoding.EncodeToString(b)}, nil
+	case ecdh.P256(), ecdh.P384(), ecdh.P521():
+		// The uncompressed point is 0x04 || x || y
+		size := (len(b) - 1) / 2
+		return &JWK{
+			KeyType: "EC",
+			Curve:   curveName(pub.Curve()),
+			X:       base64.RawURLEncoding.EncodeToString(b[1 : 1+size]),
+			Y:       base64.RawURLEncoding.EncodeToString(b[1+size:]),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: the curve is not supported", ErrUnsupportedKey)
+}
+
+// curveName - This function will return the JWK name of a NIST curve
+func curveName(curve ecdh.Curve) string {
+	switch curve {
+	case ecdh.P256():
+		return "P-256"
+	case ecdh.P384():
+		return "P-384"
+	}
+	return "P-521"
+}
+
+// ----------------------------------------------------------------------
+// Private JWK Methods
+// ----------------------------------------------------------------------
+
+// publicKey - This method will return the ECDH public key of the JWK. The point
+// is checked to be on the curve.
+func (j *JWK) publicKey() (*ecdh.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the epk header is not valid: %s", ErrInvalidEnvelope, err)
+	}
+
+	var curve ecdh.Curve
+	var size int
+	switch {
+	case j.KeyType == "OKP" && j.Curve == "X25519":
+		return ecdh.X25519().NewPublicKey(x)
+	case j.KeyType == "EC" && j.Curve == "P-256":
+		curve, size = ecdh.P256(), 32
+	case j.KeyType == "EC" && j.Curve == "P-384":
+		curve, size = ecdh.P384(), 48
+	case j.KeyType == "EC" && j.Curve == "P-521":
+		curve, size = ecdh.P521(), 66
+	default:
+		return nil, fmt.Errorf("%w: the epk header uses the unsupported curve %s", ErrInvalidEnvelope, j.Curve)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(j.Y)
+	if err != nil || len(x) != size || len(y) != size {
+		return nil, fmt.Errorf("%w: the epk header is not a valid %s point", ErrInvalidEnvelope, j.Curve)
+	}
+	point := append(append([]byte{4}, x...), y...)
+	return curve.NewPublicKey(point)
+}
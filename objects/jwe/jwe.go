@@ -0,0 +1,428 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package jwe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // RSA-OAEP uses SHA-1
+	_ "crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// These are the key management algorithms (alg) that are supported
+const (
+	RSAOAEP       = "RSA-OAEP"
+	RSAOAEP256    = "RSA-OAEP-256"
+	ECDHESA256KW  = "ECDH-ES+A256KW"
+	defaultRSAAlg = RSAOAEP256
+)
+
+// These are the content encryption algorithms (enc) that are supported
+const (
+	A128GCM = "A128GCM"
+	A192GCM = "A192GCM"
+	A256GCM = "A256GCM"
+)
+
+// These errors are returned, possibly wrapped, when an envelope can not be
+// created or opened, so that callers can tell why.
+var (
+	ErrUnsupportedKey       = errors.New("the key is not supported")
+	ErrUnsupportedAlgorithm = errors.New("the algorithm is not supported")
+	ErrInvalidEnvelope      = errors.New("the JWE is not valid")
+	ErrNoRecipient          = errors.New("the JWE could not be decrypted with the key")
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// Header - This type defines the JOSE header members that are used by the
+// supported algorithms. The header of a recipient is the union of the
+// protected header, the shared unprotected header and its own header.
+type Header struct {
+	Algorithm    string   `json:"alg,omitempty"`
+	Encryption   string   `json:"enc,omitempty"`
+	KeyID        string   `json:"kid,omitempty"`
+	ContentType  string   `json:"cty,omitempty"`
+	Compression  string   `json:"zip,omitempty"`
+	Critical     []string `json:"crit,omitempty"`
+	EphemeralKey *JWK     `json:"epk,omitempty"`
+	PartyUInfo   string   `json:"apu,omitempty"`
+	PartyVInfo   string   `json:"apv,omitempty"`
+}
+
+// Recipient - This type defines a recipient of an envelope. The key is an
+// *rsa.PublicKey for RSA-OAEP and RSA-OAEP-256, or an *ecdsa.PublicKey or
+// *ecdh.PublicKey for ECDH-ES+A256KW. If the algorithm is empty it is picked
+// from the type of the key. The key ID is optional and helps the recipient
+// find its private key.
+type Recipient struct {
+	Algorithm string
+	KeyID     string
+	Key       crypto.PublicKey
+}
+
+// Envelope - This type defines the JSON serialization of a JWE. The general
+// form uses Recipients and the flattened form uses Header and EncryptedKey.
+type Envelope struct {
+	Protected    string              `json:"protected,omitempty"`
+	Unprotected  *Header             `json:"unprotected,omitempty"`
+	Recipients   []EnvelopeRecipient `json:"recipients,omitempty"`
+	Header       *Header             `json:"header,omitempty"`
+	EncryptedKey string              `json:"encrypted_key,omitempty"`
+	AAD          string              `json:"aad,omitempty"`
+	IV           string              `json:"iv"`
+	Ciphertext   string              `json:"ciphertext"`
+	Tag          string              `json:"tag"`
+}
+
+// EnvelopeRecipient - This type defines the per recipient members of the
+// general JSON serialization of a JWE.
+type EnvelopeRecipient struct {
+	Header       *Header `json:"header,omitempty"`
+	EncryptedKey string  `json:"encrypted_key,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// Encrypt - This function will encrypt the plaintext with a new content
+// encryption key using the content encryption algorithm, like A256GCM, and
+// wrap the key for each of the recipients. The content type is added to the
+// protected header when it is not empty. It returns the general JSON
+// serialization of the JWE.
+func Encrypt(plaintext []byte, enc, contentType string, recipients ...Recipient) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one recipient is needed")
+	}
+	size, err := keySize(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	cek := make([]byte, size)
+	if _, err := rand.Read(cek); err != nil {
+		return nil, err
+	}
+
+	var env Envelope
+	for _, r := range recipients {
+		header, encryptedKey, err := wrapCEK(r, cek)
+		if err != nil {
+			return nil, err
+		}
+		env.Recipients = append(env.Recipients, EnvelopeRecipient{
+			Header:       header,
+			EncryptedKey: encodeSegment(encryptedKey),
+		})
+	}
+
+	protected, err := json.Marshal(Header{Encryption: enc, ContentType: contentType})
+	if err != nil {
+		return nil, err
+	}
+	env.Protected = encodeSegment(protected)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(env.Protected))
+	tagStart := len(sealed) - gcm.Overhead()
+
+	env.IV = encodeSegment(iv)
+	env.Ciphertext = encodeSegment(sealed[:tagStart])
+	env.Tag = encodeSegment(sealed[tagStart:])
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Decrypt - This function will decrypt a JWE in the general JSON, flattened
+// JSON or compact serialization with the private key of one of the
+// recipients. The key is an *rsa.PrivateKey or other crypto.Decrypter with an
+// RSA public key, or an *ecdsa.PrivateKey or *ecdh.PrivateKey. It returns the
+// plaintext and the protected header. ErrNoRecipient is returned if the key
+// does not open the envelope.
+func Decrypt(data []byte, key crypto.PrivateKey) ([]byte, *Header, error) {
+	env, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	protected := new(Header)
+	if env.Protected != "" {
+		b, err := decodeSegment(env.Protected)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(b, protected); err != nil {
+			return nil, nil, fmt.Errorf("%w: the protected header is not valid: %s", ErrInvalidEnvelope, err)
+		}
+	}
+
+	iv, err := decodeSegment(env.IV)
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext, err := decodeSegment(env.Ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag, err := decodeSegment(env.Tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	aad := env.Protected
+	if env.AAD != "" {
+		aad += "." + env.AAD
+	}
+
+	recipients := env.Recipients
+	if len(recipients) == 0 {
+		recipients = []EnvelopeRecipient{{Header: env.Header, EncryptedKey: env.EncryptedKey}}
+	}
+
+	for _, r := range recipients {
+		header, err := mergeHeaders(protected, env.Unprotected, r.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !canUnwrap(header.Algorithm, key) {
+			continue
+		}
+		encryptedKey, err := decodeSegment(r.EncryptedKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cek, err := unwrapCEK(header, key, encryptedKey)
+		if err != nil {
+			continue
+		}
+		size, err := keySize(header.Encryption)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(cek) != size {
+			continue
+		}
+
+		gcm, err := newGCM(cek)
+		if err != nil || len(iv) != gcm.NonceSize() || len(tag) != gcm.Overhead() {
+			continue
+		}
+		plaintext, err := gcm.Open(nil, iv, append(ciphertext[:len(ciphertext):len(ciphertext)], tag...), []byte(aad))
+		if err != nil {
+			continue
+		}
+		return plaintext, protected, nil
+	}
+	return nil, nil, ErrNoRecipient
+}
+
+// Parse - This function will parse a JWE in the general JSON, flattened JSON
+// or compact serialization. The compact serialization is returned in the
+// flattened form.
+func Parse(data []byte) (*Envelope, error) {
+	data = bytes.TrimSpace(data)
+	var env Envelope
+
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &env); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+		}
+	} else {
+		parts := strings.Split(string(data), ".")
+		if len(parts) != 5 {
+			return nil, fmt.Errorf("%w: the compact serialization must have five parts", ErrInvalidEnvelope)
+		}
+		env.Protected = parts[0]
+		env.EncryptedKey = parts[1]
+		env.IV = parts[2]
+		env.Ciphertext = parts[3]
+		env.Tag = parts[4]
+	}
+
+	if env.IV == "" || env.Tag == "" {
+		return nil, fmt.Errorf("%w: the iv and tag are required", ErrInvalidEnvelope)
+	}
+	if len(env.Recipients) > 0 && (env.Header != nil || env.EncryptedKey != "") {
+		return nil, fmt.Errorf("%w: the general and flattened serializations can not be mixed", ErrInvalidEnvelope)
+	}
+	return &env, nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// wrapCEK - This function will wrap the content encryption key for the
+// recipient and return the recipient header and the encrypted key.
+func wrapCEK(r Recipient, cek []byte) (*Header, []byte, error) {
+	alg := r.Algorithm
+	if alg == "" {
+		if _, ok := r.Key.(*rsa.PublicKey); ok {
+			alg = defaultRSAAlg
+		} else {
+			alg = ECDHESA256KW
+		}
+	}
+	header := &Header{Algorithm: alg, KeyID: r.KeyID}
+
+	switch alg {
+	case RSAOAEP, RSAOAEP256:
+		pub, ok := r.Key.(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s needs an *rsa.PublicKey, not %T", ErrUnsupportedKey, alg, r.Key)
+		}
+		encryptedKey, err := rsa.EncryptOAEP(oaepHash(alg).New(), rand.Reader, pub, cek, nil)
+		return header, encryptedKey, err
+
+	case ECDHESA256KW:
+		pub, err := ecdhPublicKey(r.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedKey, epk, err := ecdhWrap(rand.Reader, alg, pub, cek)
+		header.EphemeralKey = epk
+		return header, encryptedKey, err
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+}
+
+// unwrapCEK - This function will unwrap the content encryption key with the
+// private key of the recipient.
+func unwrapCEK(header *Header, key crypto.PrivateKey, encryptedKey []byte) ([]byte, error) {
+	switch header.Algorithm {
+	case RSAOAEP, RSAOAEP256:
+		decrypter := key.(crypto.Decrypter)
+		return decrypter.Decrypt(rand.Reader, encryptedKey, &rsa.OAEPOptions{Hash: oaepHash(header.Algorithm)})
+
+	case ECDHESA256KW:
+		priv, err := ecdhPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return ecdhUnwrap(priv, header, encryptedKey)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, header.Algorithm)
+}
+
+// canUnwrap - This function will return true if the key can be used with the
+// key management algorithm.
+func canUnwrap(alg string, key crypto.PrivateKey) bool {
+	switch alg {
+	case RSAOAEP, RSAOAEP256:
+		decrypter, ok := key.(crypto.Decrypter)
+		if !ok {
+			return false
+		}
+		_, ok = decrypter.Public().(*rsa.PublicKey)
+		return ok
+	case ECDHESA256KW:
+		_, err := ecdhPrivateKey(key)
+		return err == nil
+	}
+	return false
+}
+
+// mergeHeaders - This function will return the union of the headers. Header
+// members can not be repeated and the zip and crit members are not supported.
+func mergeHeaders(headers ...*Header) (*Header, error) {
+	merged := new(Header)
+	for _, h := range headers {
+		if h == nil {
+			continue
+		}
+		if h.Compression != "" || len(h.Critical) > 0 {
+			return nil, fmt.Errorf("%w: the zip and crit headers are not supported", ErrInvalidEnvelope)
+		}
+
+		for _, m := range []struct{ dst, src *string }{
+			{&merged.Algorithm, &h.Algorithm},
+			{&merged.Encryption, &h.Encryption},
+			{&merged.KeyID, &h.KeyID},
+			{&merged.ContentType, &h.ContentType},
+			{&merged.PartyUInfo, &h.PartyUInfo},
+			{&merged.PartyVInfo, &h.PartyVInfo},
+		} {
+			if *m.src == "" {
+				continue
+			}
+			if *m.dst != "" {
+				return nil, fmt.Errorf("%w: a header member is repeated", ErrInvalidEnvelope)
+			}
+			*m.dst = *m.src
+		}
+		if h.EphemeralKey != nil {
+			if merged.EphemeralKey != nil {
+				return nil, fmt.Errorf("%w: the epk header is repeated", ErrInvalidEnvelope)
+			}
+			merged.EphemeralKey = h.EphemeralKey
+		}
+	}
+	return merged, nil
+}
+
+// keySize - This function will return the key size in bytes of the content
+// encryption algorithm.
+func keySize(enc string) (int, error) {
+	switch enc {
+	case A128GCM:
+		return 16, nil
+	case A192GCM:
+		return 24, nil
+	case A256GCM:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, enc)
+}
+
+// newGCM - This function will return AES-GCM with a 96 bit IV and 128 bit tag
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// oaepHash - This function will return the hash used by the RSA-OAEP algorithm
+func oaepHash(alg string) crypto.Hash {
+	if alg == RSAOAEP {
+		return crypto.SHA1
+	}
+	return crypto.SHA256
+}
+
+// encodeSegment - This function will base64url encode without padding
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSegment - This function will decode base64url without padding
+func decodeSegment(seg string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEnvelope, err)
+	}
+	return data, nil
+}
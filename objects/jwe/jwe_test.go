@@ -0,0 +1,185 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package jwe
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestKeyWrap - This will test AES Key Wrap with the 256 bit key and 256 bit
+// key data test vector from RFC 3394 section 4.6
+func TestKeyWrap(t *testing.T) {
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+	want, _ := hex.DecodeString("28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21")
+
+	wrapped, err := wrapKey(kek, key)
+	if err != nil || !bytes.Equal(wrapped, want) {
+		t.Errorf("1.1 wrapKey returned %x %v", wrapped, err)
+	}
+	unwrapped, err := unwrapKey(kek, want)
+	if err != nil || !bytes.Equal(unwrapped, key) {
+		t.Errorf("1.2 unwrapKey returned %x %v", unwrapped, err)
+	}
+
+	want[0] ^= 1
+	if _, err := unwrapKey(kek, want); err == nil {
+		t.Errorf("1.3 unwrapKey did not detect a modified key")
+	}
+}
+
+// TestConcatKDF - This will test the Concat KDF with the ECDH-ES example from
+// RFC 7518 appendix C
+func TestConcatKDF(t *testing.T) {
+	epk := &JWK{
+		KeyType: "EC",
+		Curve:   "P-256",
+		X:       "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+		Y:       "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+	}
+	d, _ := base64.RawURLEncoding.DecodeString("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw")
+	priv, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		t.Fatalf("2.1 unable to create the private key: %s", err)
+	}
+	pub, err := epk.publicKey()
+	if err != nil {
+		t.Fatalf("2.2 unable to parse the epk: %s", err)
+	}
+	z, _ := priv.ECDH(pub)
+
+	key := concatKDF(z, "A128GCM", []byte("Alice"), []byte("Bob"), 128)
+	if got := base64.RawURLEncoding.EncodeToString(key); got != "VqqN6vgjbSBcIijNcacQGg" {
+		t.Errorf("2.3 concatKDF returned %s", got)
+	}
+}
+
+// TestEncrypt - This will test encrypting for several recipients and that
+// each of them, and nobody else, can decrypt
+func TestEncrypt(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	xKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	plaintext := []byte(`{"type": "playbook"}`)
+
+	data, err := Encrypt(plaintext, A256GCM, "json",
+		Recipient{Key: &rsaKey.PublicKey, KeyID: "partner-1"},
+		Recipient{Algorithm: RSAOAEP, Key: &rsaKey.PublicKey},
+		Recipient{Key: &ecKey.PublicKey},
+		Recipient{Key: xKey.PublicKey()},
+	)
+	if err != nil {
+		t.Fatalf("3.1 unable to encrypt: %s", err)
+	}
+	if bytes.Contains(data, []byte("playbook")) {
+		t.Errorf("3.2 the envelope contains the plaintext")
+	}
+
+	env, _ := Parse(data)
+	want := []string{RSAOAEP256, RSAOAEP, ECDHESA256KW, ECDHESA256KW}
+	for i, r := range env.Recipients {
+		if r.Header.Algorithm != want[i] {
+			t.Errorf("3.3.%d the recipient uses %s instead of %s", i, r.Header.Algorithm, want[i])
+		}
+	}
+	if env.Recipients[3].Header.EphemeralKey.Curve != "X25519" {
+		t.Errorf("3.4 the epk header is not correct %+v", env.Recipients[3].Header.EphemeralKey)
+	}
+
+	for i, key := range []interface{}{rsaKey, ecKey, xKey} {
+		got, header, err := Decrypt(data, key)
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Errorf("3.5.%d unable to decrypt: %v", i, err)
+			continue
+		}
+		if header.Encryption != A256GCM || header.ContentType != "json" {
+			t.Errorf("3.6.%d the protected header is not correct %+v", i, header)
+		}
+	}
+
+	// Use RSA-OAEP alone, so the first recipient can not be used
+	env.Recipients = env.Recipients[1:2]
+	single, _ := json.Marshal(env)
+	if got, _, err := Decrypt(single, rsaKey); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("3.7 unable to decrypt with RSA-OAEP: %v", err)
+	}
+
+	if _, _, err := Decrypt(data, otherKey); !errors.Is(err, ErrNoRecipient) {
+		t.Errorf("3.8 Decrypt with a key that is not a recipient returned %v", err)
+	}
+
+	// Any change to the protected header or ciphertext is detected
+	env, _ = Parse(data)
+	env.Protected = strings.Replace(env.Protected, "e", "f", 1)
+	tampered, _ := json.Marshal(env)
+	if _, _, err := Decrypt(tampered, ecKey); err == nil {
+		t.Errorf("3.9 Decrypt did not detect a modified protected header")
+	}
+
+	if _, err := Encrypt(plaintext, "A256CBC-HS512", "", Recipient{Key: &rsaKey.PublicKey}); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("3.10 Encrypt with an unsupported enc returned %v", err)
+	}
+	if _, err := Encrypt(plaintext, A256GCM, "", Recipient{Algorithm: RSAOAEP, Key: &ecKey.PublicKey}); !errors.Is(err, ErrUnsupportedKey) {
+		t.Errorf("3.11 Encrypt with the wrong key type returned %v", err)
+	}
+}
+
+// TestCompact - This will test decrypting the flattened and compact
+// serializations, where the whole header is protected
+func TestCompact(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	plaintext := []byte("secret")
+
+	cek := make([]byte, 16)
+	rand.Read(cek)
+	pub, _ := ecKey.PublicKey.ECDH()
+	encryptedKey, epk, err := ecdhWrap(rand.Reader, ECDHESA256KW, pub, cek)
+	if err != nil {
+		t.Fatalf("4.1 unable to wrap the key: %s", err)
+	}
+	header, _ := json.Marshal(Header{Algorithm: ECDHESA256KW, Encryption: A128GCM, EphemeralKey: epk})
+
+	env := Envelope{Protected: encodeSegment(header), EncryptedKey: encodeSegment(encryptedKey)}
+	gcm, _ := newGCM(cek)
+	iv := make([]byte, gcm.NonceSize())
+	rand.Read(iv)
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(env.Protected))
+	env.IV = encodeSegment(iv)
+	env.Ciphertext = encodeSegment(sealed[:len(sealed)-gcm.Overhead()])
+	env.Tag = encodeSegment(sealed[len(sealed)-gcm.Overhead():])
+
+	compact := strings.Join([]string{env.Protected, env.EncryptedKey, env.IV, env.Ciphertext, env.Tag}, ".")
+	if got, _, err := Decrypt([]byte(compact), ecKey); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("4.2 unable to decrypt the compact serialization: %v", err)
+	}
+
+	flattened, _ := json.Marshal(env)
+	if got, _, err := Decrypt(flattened, ecKey); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("4.3 unable to decrypt the flattened serialization: %v", err)
+	}
+
+	env.Tag = encodeSegment(make([]byte, gcm.Overhead()))
+	flattened, _ = json.Marshal(env)
+	if _, _, err := Decrypt(flattened, ecKey); !errors.Is(err, ErrNoRecipient) {
+		t.Errorf("4.4 Decrypt did not detect a modified tag, returned %v", err)
+	}
+
+	if _, err := Parse([]byte("a.b.c")); !errors.Is(err, ErrInvalidEnvelope) {
+		t.Errorf("4.5 Parse accepted a compact serialization with three parts")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package markings
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode - This function will decode a single data marking definition into the
+// concrete marking type identified by its type property and return it as a
+// DataMarkingObject. The id value is the dictionary key that the marking was
+// stored under in the playbook. If the marking does not carry its own id
+// property, the key is restored into the ID property of the marking.
+func Decode(id string, data []byte) (DataMarkingObject, error) {
+	var common CommonProperties
+	if err := json.Unmarshal(data, &common); err != nil {
+		return nil, err
+	}
+
+	var m DataMarkingObject
+	switch common.ObjectType {
+	case "marking-tlp":
+		m = new(MarkingTLP)
+	case "marking-statement":
+		m = new(MarkingStatement)
+	case "marking-iep":
+		m = new(MarkingIEP)
+	default:
+		return nil, fmt.Errorf("the data marking %s has a type of \"%s\" that is not supported", id, common.ObjectType)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.GetCommon().ID == "" {
+		m.SetID(id)
+	}
+
+	return m, nil
+}
@@ -20,6 +20,12 @@ func (m *CommonProperties) SetNewID(s string) error {
 	return nil
 }
 
+// SetID - This method will set the ID of the marking object to the value
+// passed in.
+func (m *CommonProperties) SetID(id string) {
+	m.ID = id
+}
+
 // GetID - This method returns the ID of the marking object
 func (m *CommonProperties) GetID() string {
 	return m.ID
@@ -52,3 +58,10 @@ func (m *CommonProperties) NewExternalReference(r ...objects.ExternalReference)
 	m.ExternalReferences = append(m.ExternalReferences, er)
 	return &m.ExternalReferences[positionThatAppendWillUse], nil
 }
+
+// AddExtension - This method takes in the ID of an extension definition and a
+// value that represents the extension and adds it to the marking_extensions
+// property.
+func (m *CommonProperties) AddExtension(id string, v interface{}) error {
+	return m.MarkingExtensions.Add(id, v)
+}
@@ -5,7 +5,10 @@
 
 package markings
 
-import "github.com/openplaybooks/libcacao/objects"
+import (
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/extensions"
+)
 
 // ----------------------------------------------------------------------
 // Define Object Model
@@ -14,6 +17,7 @@ import "github.com/openplaybooks/libcacao/objects"
 // DataMarkingObject - This interface defines a data marking object
 type DataMarkingObject interface {
 	GetCommon() CommonProperties
+	SetID(id string)
 }
 
 // CommonProperties - Each data marking object contains some base properties
@@ -31,7 +35,7 @@ type CommonProperties struct {
 	ValidUntil         string                      `json:"valid_until,omitempty"`
 	Labels             []string                    `json:"labels,omitempty"`
 	ExternalReferences []objects.ExternalReference `json:"external_references,omitempty"`
-	//marking_extensions
+	MarkingExtensions  extensions.Extensions       `json:"marking_extensions,omitempty"`
 }
 
 // MarkingTLP - This type implements the FIRST TLPv2 object as a CACAO 2.0 TLP
@@ -62,16 +66,15 @@ type MarkingStatement struct {
 // specification, please refer to that document [IEP].
 type MarkingIEP struct {
 	CommonProperties
-	TLP                        string   `json:"tlp,omitempty"`
-	IEPVersion                 string   `json:"iep_version,omitempty"`
-	StartDate                  string   `json:"start_date,omitempty"`
-	EndDate                    string   `json:"end_date,omitempty"`
-	EncryptInTransit           string   `json:"encrypt_in_transit,omitempty"`
-	PermittedActions           string   `json:"permitted_actions,omitempty"`
-	AffectedPartyNotifications string   `json:"affected_party_notifications,omitempty"`
-	Attribution                string   `json:"attribution,omitempty"`
-	UnmodifiedResale           string   `json:"unmodified_resale,omitempty"`
-	ExternalReferences         []string `json:"external_references,omitempty"`
+	TLP                        string `json:"tlp,omitempty"`
+	IEPVersion                 string `json:"iep_version,omitempty"`
+	StartDate                  string `json:"start_date,omitempty"`
+	EndDate                    string `json:"end_date,omitempty"`
+	EncryptInTransit           string `json:"encrypt_in_transit,omitempty"`
+	PermittedActions           string `json:"permitted_actions,omitempty"`
+	AffectedPartyNotifications string `json:"affected_party_notifications,omitempty"`
+	Attribution                string `json:"attribution,omitempty"`
+	UnmodifiedResale           string `json:"unmodified_resale,omitempty"`
 }
 
 // ----------------------------------------------------------------------
@@ -149,9 +152,14 @@ func NewStatementMarking() *MarkingStatement {
 	return &m
 }
 
-// NewIEPMarking - This function will create a new iep marking object and return it
-// as a pointer.
+// NewIEPMarking - This will create and initialize a new FIRST IEP 2.0 marking
+// object and return it as a pointer.
 func NewIEPMarking() *MarkingIEP {
 	var m MarkingIEP
+	m.ObjectType = "marking-iep"
+	m.SetNewID(m.ObjectType)
+	m.Created = m.GetCurrentTime("milli")
+	m.Revoked = false
+	m.IEPVersion = "2.0"
 	return &m
 }
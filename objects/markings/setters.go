@@ -5,6 +5,14 @@
 
 package markings
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
 // ----------------------------------------------------------------------
 // Define TLP Functions and Methods
 // ----------------------------------------------------------------------
@@ -31,3 +39,57 @@ func (m *MarkingStatement) GetCommon() CommonProperties {
 func (m *MarkingIEP) GetCommon() CommonProperties {
 	return m.CommonProperties
 }
+
+// SetStartDate - This method takes in a timestamp in either time.Time or string
+// format and updates the start_date property with it. If an end_date is
+// already present, the start_date must be before it.
+func (m *MarkingIEP) SetStartDate(t interface{}) error {
+	ts, err := objects.TimeToString(t, "milli")
+	if err != nil {
+		return err
+	}
+
+	if m.EndDate != "" {
+		start, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return fmt.Errorf("the start_date timestamp is invalid: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, m.EndDate)
+		if err != nil {
+			return fmt.Errorf("the existing end_date timestamp is invalid: %w", err)
+		}
+		if !start.Before(end) {
+			return errors.New("the start_date timestamp is invalid, it is not before the end_date timestamp")
+		}
+	}
+
+	m.StartDate = ts
+	return nil
+}
+
+// SetEndDate - This method takes in a timestamp in either time.Time or string
+// format and updates the end_date property with it. If a start_date is already
+// present, the end_date must be after it.
+func (m *MarkingIEP) SetEndDate(t interface{}) error {
+	ts, err := objects.TimeToString(t, "milli")
+	if err != nil {
+		return err
+	}
+
+	if m.StartDate != "" {
+		start, err := time.Parse(time.RFC3339, m.StartDate)
+		if err != nil {
+			return fmt.Errorf("the existing start_date timestamp is invalid: %w", err)
+		}
+		end, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			return fmt.Errorf("the end_date timestamp is invalid: %w", err)
+		}
+		if !end.After(start) {
+			return errors.New("the end_date timestamp is invalid, it is not after the start_date timestamp")
+		}
+	}
+
+	m.EndDate = ts
+	return nil
+}
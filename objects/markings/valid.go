@@ -0,0 +1,235 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package markings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Valid - This method will verify that the object is correct. It will return a
+// boolean, an integer that tracks the number of problems found, and a slice of
+// strings that contain the detailed results, whether good or bad. If debug is
+// enabled, then resultDetails will contain entries for successful checks not
+// just failures.
+func (m *MarkingIEP) Valid(debug bool) (bool, int, []string) {
+	r := m.validate(debug)
+
+	// Return real values not pointers
+	if r.ProblemsFound > 0 {
+		return false, r.ProblemsFound, r.ResultDetails
+	}
+	return true, r.ProblemsFound, r.ResultDetails
+}
+
+// Check - This method will run the same checks as Valid() and return the
+// results as a list of findings. The JSON Pointer of each finding is relative
+// to the data marking.
+func (m *MarkingIEP) Check(debug bool) []objects.Finding {
+	r := m.validate(debug)
+	return r.Findings
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// checkVocab - This function will check that an optional property, if
+// populated, contains a value from its vocabulary.
+func checkVocab(r *objects.Results, propertyName, value string, vocab []string) {
+	if value == "" {
+		return
+	}
+
+	r.At("data_marking."+propertyName, propertyName)
+
+	if objects.IsVocabValueValid(value, vocab) {
+		str := fmt.Sprintf("++ the %s property contains a valid %s value of \"%s\"", propertyName, propertyName, value)
+		r.LogValid(str)
+	} else {
+		str := fmt.Sprintf("-- the %s property contains a value of \"%s\" that is not in the vocabulary", propertyName, value)
+		r.LogProblem(str)
+	}
+}
+
+// isIDValid - This function will take in an CACAO ID and an object type and
+// check to see if it is a valid identifier for that object type.
+func isIDValid(id, objType string) bool {
+	idparts := strings.Split(id, "--")
+
+	if len(idparts) != 2 {
+		return false
+	}
+
+	// First check to see if the object type is valid, if not return false.
+	if idparts[0] != objType {
+		// Short circuit if the object type part is wrong
+		return false
+	}
+
+	// If the type is valid, then check to see if the ID is a UUID, if not return
+	// false.
+	return objects.IsUUIDValid(idparts[1])
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// validate - This method will run each of the checks and return the results.
+func (m *MarkingIEP) validate(debug bool) *objects.Results {
+	r := new(objects.Results)
+	r.Debug = debug
+
+	// Check each property in the model
+	m.checkObjectType(r, "marking-iep")
+	m.checkID(r, "marking-iep")
+	m.checkCreatedBy(r)
+	m.checkCreated(r)
+	m.checkIEPVersion(r)
+	checkVocab(r, "tlp", m.TLP, GetTLPv2LevelsVocab())
+	m.checkStartDate(r)
+	m.checkEndDate(r)
+	checkVocab(r, "encrypt_in_transit", m.EncryptInTransit, GetIEPEncryptInTransitVocab())
+	checkVocab(r, "permitted_actions", m.PermittedActions, GetIEPPermittedActionsVocab())
+	checkVocab(r, "affected_party_notifications", m.AffectedPartyNotifications, GetIEPAffectedPartyNotificationsVocab())
+	checkVocab(r, "attribution", m.Attribution, GetIEPAttributionVocab())
+	checkVocab(r, "unmodified_resale", m.UnmodifiedResale, GetIEPUnmodifiedResaleVocab())
+
+	return r
+}
+
+// Each of these methods will check a specific property. It is done this way
+// to reduce the complexity of the main valid() function. This way all of the
+// checks for each property are self contained in their own function.
+
+func (m *CommonProperties) checkObjectType(r *objects.Results, objType string) {
+	r.At("data_marking.type", "type")
+
+	if m.ObjectType == "" {
+		r.RequiredButMissing("type")
+		return
+	}
+
+	r.RequiredAndFound("type")
+	if m.ObjectType != objType {
+		str := fmt.Sprintf("-- the type property does not contain a value of %s", objType)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the type property contains a valid type value of \"%s\"", m.ObjectType)
+		r.LogValid(str)
+	}
+}
+
+func (m *CommonProperties) checkID(r *objects.Results, objType string) {
+	r.At("data_marking.id", "id")
+
+	if m.ID == "" {
+		r.RequiredButMissing("id")
+		return
+	}
+
+	r.RequiredAndFound("id")
+	if valid := isIDValid(m.ID, objType); valid == false {
+		r.LogProblem("-- the id property does not contain a valid identifier")
+	} else {
+		str := fmt.Sprintf("++ the id property contains a valid identifier value of \"%s\"", m.ID)
+		r.LogValid(str)
+	}
+}
+
+func (m *CommonProperties) checkCreatedBy(r *objects.Results) {
+	r.At("data_marking.created_by", "created_by")
+
+	if m.CreatedBy == "" {
+		r.RequiredButMissing("created_by")
+		return
+	}
+
+	r.RequiredAndFound("created_by")
+	if valid := isIDValid(m.CreatedBy, "identity"); valid == false {
+		r.LogProblem("-- the created_by property does not contain a valid identifier")
+	} else {
+		str := fmt.Sprintf("++ the created_by property contains a valid identifier value of \"%s\"", m.CreatedBy)
+		r.LogValid(str)
+	}
+}
+
+func (m *CommonProperties) checkCreated(r *objects.Results) {
+	r.At("data_marking.created", "created")
+
+	if m.Created == "" {
+		r.RequiredButMissing("created")
+		return
+	}
+
+	r.RequiredAndFound("created")
+	if valid := objects.IsTimestampValid(m.Created); valid == false {
+		r.LogProblem("-- the created property does not contain a valid timestamp")
+	} else {
+		str := fmt.Sprintf("++ the created property contains a valid timestamp value of \"%s\"", m.Created)
+		r.LogValid(str)
+	}
+}
+
+func (m *MarkingIEP) checkIEPVersion(r *objects.Results) {
+	r.At("data_marking.iep_version", "iep_version")
+
+	if m.IEPVersion == "" {
+		r.RequiredButMissing("iep_version")
+		return
+	}
+
+	r.RequiredAndFound("iep_version")
+	if m.IEPVersion != "2.0" {
+		r.LogProblem("-- the iep_version property does not contain a value of 2.0")
+	} else {
+		r.LogValid("++ the iep_version property contains a valid value of \"2.0\"")
+	}
+}
+
+func (m *MarkingIEP) checkStartDate(r *objects.Results) {
+	r.At("data_marking.start_date", "start_date")
+
+	if m.StartDate != "" {
+		if valid := objects.IsTimestampValid(m.StartDate); valid == false {
+			r.LogProblem("-- the start_date property does not contain a valid timestamp")
+		} else {
+			r.LogValid("++ the start_date property contains a valid timestamp")
+		}
+	}
+}
+
+func (m *MarkingIEP) checkEndDate(r *objects.Results) {
+	r.At("data_marking.end_date", "end_date")
+
+	if m.EndDate != "" {
+		if valid := objects.IsTimestampValid(m.EndDate); valid == false {
+			r.LogProblem("-- the end_date property does not contain a valid timestamp")
+		} else {
+			r.LogValid("++ the end_date property contains a valid timestamp")
+		}
+
+		// If there is an end_date timestamp, then lets check to see if there is
+		// also a start_date and if so is the end_date later than the start_date
+		if m.StartDate != "" {
+			startDate, _ := time.Parse(time.RFC3339, m.StartDate)
+			endDate, _ := time.Parse(time.RFC3339, m.EndDate)
+			if endDate.After(startDate) {
+				r.LogValid("++ the end_date timestamp is later than the start_date timestamp")
+			} else {
+				r.LogProblem("-- the end_date timestamp is not later than the start_date timestamp")
+			}
+		}
+	}
+}
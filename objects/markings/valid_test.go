@@ -0,0 +1,78 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package markings
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewIEPMarking - This will test that a new IEP marking is initialized
+func TestNewIEPMarking(t *testing.T) {
+	m := NewIEPMarking()
+
+	if m.ObjectType != "marking-iep" {
+		t.Errorf("1.1 the type property was not set, have %s", m.ObjectType)
+	}
+	if !strings.HasPrefix(m.ID, "marking-iep--") {
+		t.Errorf("1.2 the id property was not set, have %s", m.ID)
+	}
+	if m.Created == "" || m.IEPVersion != "2.0" {
+		t.Errorf("1.3 the created and iep_version properties were not set")
+	}
+}
+
+// TestIEPValid - This will test the IEP Valid() method
+func TestIEPValid(t *testing.T) {
+	m := NewIEPMarking()
+	m.CreatedBy = "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1"
+	m.TLP = "TLP:AMBER"
+	m.EncryptInTransit = "must"
+	m.PermittedActions = "contact-for-instruction"
+	m.AffectedPartyNotifications = "may"
+	m.Attribution = "must-not"
+	m.UnmodifiedResale = "must-not"
+	m.StartDate = "2023-01-01T00:00:00.000Z"
+	m.EndDate = "2023-12-31T23:59:59.999Z"
+
+	if valid, count, details := m.Valid(false); !valid || count != 0 {
+		t.Errorf("2.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check invalid vocabulary values
+	m.EncryptInTransit = "should"
+	m.PermittedActions = "everything"
+	m.Attribution = "maybe"
+	if valid, count, details := m.Valid(false); valid || count != 3 {
+		t.Errorf("2.2 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check end date before start date
+	m.EncryptInTransit = "may"
+	m.PermittedActions = "nothing"
+	m.Attribution = "may"
+	m.EndDate = "2022-12-31T23:59:59.999Z"
+	if valid, count, details := m.Valid(false); valid || count != 1 {
+		t.Errorf("2.3 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check setters refuse out of order dates
+	if err := m.SetEndDate("2022-06-01T00:00:00.000Z"); err == nil {
+		t.Errorf("2.4 SetEndDate accepted an end date before the start date")
+	}
+	if err := m.SetEndDate("2024-06-01T00:00:00.000Z"); err != nil {
+		t.Errorf("2.5 SetEndDate returned an error %s", err)
+	}
+
+	// Check setters report a start_date that can not be parsed
+	m.StartDate = "yesterday"
+	if err := m.SetEndDate("2025-06-01T00:00:00.000Z"); err == nil || m.EndDate != "2024-06-01T00:00:00.000Z" {
+		t.Errorf("2.6 SetEndDate did not return an error for a start_date that is not valid")
+	}
+	if err := m.SetStartDate("2023-01-01T00:00:00.000Z"); err != nil {
+		t.Errorf("2.7 SetStartDate returned an error %s", err)
+	}
+}
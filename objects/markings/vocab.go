@@ -0,0 +1,67 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package markings
+
+// GetTLPv2LevelsVocab - This will return a slice of officially supported FIRST
+// TLPv2 levels
+func GetTLPv2LevelsVocab() []string {
+	return []string{
+		"TLP:CLEAR",
+		"TLP:GREEN",
+		"TLP:AMBER",
+		"TLP:AMBER+STRICT",
+		"TLP:RED",
+	}
+}
+
+// GetIEPEncryptInTransitVocab - This will return a slice of officially
+// supported FIRST IEP 2.0 encrypt in transit values
+func GetIEPEncryptInTransitVocab() []string {
+	return []string{
+		"must",
+		"may",
+	}
+}
+
+// GetIEPPermittedActionsVocab - This will return a slice of officially
+// supported FIRST IEP 2.0 permitted actions values
+func GetIEPPermittedActionsVocab() []string {
+	return []string{
+		"nothing",
+		"contact-for-instruction",
+		"internally-visible-actions",
+		"externally-visible-indirect-actions",
+		"externally-visible-direct-actions",
+	}
+}
+
+// GetIEPAffectedPartyNotificationsVocab - This will return a slice of
+// officially supported FIRST IEP 2.0 affected party notifications values
+func GetIEPAffectedPartyNotificationsVocab() []string {
+	return []string{
+		"may",
+		"must-not",
+	}
+}
+
+// GetIEPAttributionVocab - This will return a slice of officially supported
+// FIRST IEP 2.0 attribution values
+func GetIEPAttributionVocab() []string {
+	return []string{
+		"may",
+		"must",
+		"must-not",
+	}
+}
+
+// GetIEPUnmodifiedResaleVocab - This will return a slice of officially
+// supported FIRST IEP 2.0 unmodified resale values
+func GetIEPUnmodifiedResaleVocab() []string {
+	return []string{
+		"may",
+		"must-not",
+	}
+}
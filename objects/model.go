@@ -30,13 +30,18 @@ type ExternalReference struct {
 // type. Variables can represent stateful elements that may need to be captured
 // to allow for the successful execution of the playbook. All playbook variables
 // are mutable unless identified as a constant.
+//
+// The value can be any JSON value. When a variable is decoded, numbers are
+// kept as json.Number so that integer and long values do not lose precision.
+// Use Validate() to check the value against the declared type and the typed
+// accessors, like AsInt() and AsIP(), to read it.
 type Variables struct {
-	ObjectType  string `json:"type,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Value       string `json:"value,omitempty"`
-	Constant    bool   `json:"constant,omitempty"`
-	External    bool   `json:"external,omitempty"`
+	ObjectType  string      `json:"type,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Constant    bool        `json:"constant,omitempty"`
+	External    bool        `json:"external,omitempty"`
 }
 
 // NewVariable -
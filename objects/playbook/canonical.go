@@ -0,0 +1,105 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/gowebpki/jcs"
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// FingerprintOptions - This type defines which top level properties are left
+// out of the content that is hashed by Fingerprint(). The signatures and
+// modified properties change without changing what the playbook does, so they
+// can be excluded to find copies of the same playbook.
+type FingerprintOptions struct {
+	ExcludeSignatures bool
+	ExcludeModified   bool
+	Exclude           []string
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Canonical - This method will return the JCS (RFC 8785) version of the
+// playbook. Two playbooks that have the same content have the same canonical
+// form, no matter the order of their properties or map entries or their
+// whitespace. This is the form of the playbook that is hashed when it is
+// signed.
+func (p *Playbook) Canonical() ([]byte, error) {
+	data, err := p.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return jcs.Transform(data)
+}
+
+// Fingerprint - This method will return the hex encoded hash of the canonical
+// form of the playbook, using a hash algorithm from the hash algorithms
+// vocabulary like "sha-256". If options are passed in, the properties they
+// name are removed before the playbook is hashed.
+func (p *Playbook) Fingerprint(alg string, opts *FingerprintOptions) (string, error) {
+	h, err := signature.HashAlgorithm(alg)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := p.Canonical()
+	if err != nil {
+		return "", err
+	}
+
+	if opts != nil {
+		// Copy the list so the caller's array is never written to
+		exclude := append([]string(nil), opts.Exclude...)
+		if opts.ExcludeSignatures {
+			exclude = append(exclude, "signatures")
+		}
+		if opts.ExcludeModified {
+			exclude = append(exclude, "modified")
+		}
+		if data, err = removeProperties(data, exclude); err != nil {
+			return "", err
+		}
+	}
+
+	hash := h.New()
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// removeProperties - This function will remove the top level properties from
+// the JSON object and return the JCS version of what is left.
+func removeProperties(data []byte, properties []string) ([]byte, error) {
+	if len(properties) == 0 {
+		return data, nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for _, k := range properties {
+		delete(m, k)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return jcs.Transform(data)
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// TestFingerprint - This will test that playbooks with the same content have
+// the same canonical form and fingerprint
+func TestFingerprint(t *testing.T) {
+	data1 := []byte(`{
+  "type": "playbook",
+  "spec_version": "cacao-2.0",
+  "id": "playbook--a0777575-5c4c-4710-9f01-15776103837f",
+  "name": "Playbook 1",
+  "created": "2022-05-18T11:31:31.319Z",
+  "modified": "2022-05-18T11:31:31.319Z",
+  "playbook_variables": {
+    "__a__": {"type": "string", "value": "a"},
+    "__b__": {"type": "string", "value": "<b>"}
+  }
+}`)
+	data2 := []byte(`{"playbook_variables":{"__b__":{"value":"<b>","type":"string"},"__a__":{"value":"a","type":"string"}},` +
+		`"modified":"2022-05-18T11:31:31.319Z","name":"Playbook 1","id":"playbook--a0777575-5c4c-4710-9f01-15776103837f",` +
+		`"created":"2022-05-18T11:31:31.319Z","spec_version":"cacao-2.0","type":"playbook"}`)
+
+	p1, _ := Decode(data1)
+	p2, _ := Decode(data2)
+
+	c1, err := p1.Canonical()
+	if err != nil {
+		t.Fatalf("1.1 Canonical returned an error: %s", err)
+	}
+	c2, _ := p2.Canonical()
+	if string(c1) != string(c2) {
+		t.Errorf("1.2 the canonical forms are not the same\n%s\n%s", c1, c2)
+	}
+	if strings.Contains(string(c1), "\n") || !strings.HasPrefix(string(c1), `{"created":`) {
+		t.Errorf("1.3 the canonical form is not in JCS form %s", c1)
+	}
+
+	for _, alg := range signature.GetHashAlgorithmsVocab() {
+		f1, err := p1.Fingerprint(alg, nil)
+		if err != nil {
+			t.Errorf("1.4 Fingerprint with %s returned an error: %s", alg, err)
+			continue
+		}
+		if f2, _ := p2.Fingerprint(alg, nil); f1 != f2 {
+			t.Errorf("1.5 the %s fingerprints are not the same", alg)
+		}
+	}
+	if _, err := p1.Fingerprint("md5", nil); err == nil {
+		t.Errorf("1.6 Fingerprint accepted an unsupported hash algorithm")
+	}
+
+	// Excluding volatile properties
+	base, _ := p1.Fingerprint("sha-256", nil)
+	opts := &FingerprintOptions{ExcludeSignatures: true, ExcludeModified: true}
+	want, _ := p1.Fingerprint("sha-256", opts)
+	if want == base {
+		t.Errorf("1.7 the options did not change the fingerprint")
+	}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sig := signature.New()
+	sig.Signee = "ACME Cyber Company"
+	p2.Modified = "2023-05-18T11:31:31.319Z"
+	p2.Sign("ES256", key, sig)
+
+	if f, _ := p2.Fingerprint("sha-256", nil); f == base {
+		t.Errorf("1.8 the fingerprint did not change when the playbook changed")
+	}
+	if f, _ := p2.Fingerprint("sha-256", opts); f != want {
+		t.Errorf("1.9 the fingerprint without signatures and modified is not the same")
+	}
+	if f, _ := p2.Fingerprint("sha-256", &FingerprintOptions{ExcludeModified: true, Exclude: []string{"signatures", "name"}}); f == want {
+		t.Errorf("1.10 the fingerprint did not exclude the name property")
+	}
+
+	// The options do not write into the array of the Exclude slice
+	exclude := make([]string, 1, 3)
+	exclude[0] = "name"
+	p2.Fingerprint("sha-256", &FingerprintOptions{Exclude: exclude, ExcludeSignatures: true, ExcludeModified: true})
+	if all := exclude[:3]; all[1] != "" || all[2] != "" {
+		t.Errorf("1.11 Fingerprint wrote into the Exclude array %v", all)
+	}
+}
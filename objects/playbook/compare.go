@@ -5,7 +5,14 @@
 
 package playbook
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
 
 // ----------------------------------------------------------------------
 // Public Methods
@@ -14,325 +21,102 @@ import "fmt"
 // Compare - This method will compare two objects to make sure they are the
 // same and will return a boolean, an integer that tracks the number of problems
 // found, and a slice of strings that contain the detailed results, whether good
-// or bad.
+// or bad. The whole playbook is compared in the same way as Diff(), and each
+// change is a problem.
 func (p *Playbook) Compare(p2 *Playbook, debug bool) (bool, int, []string) {
-	var r *results = new(results)
-	r.debug = debug
-
-	// Object Type
-	if p.ObjectType != p2.ObjectType {
-		str := fmt.Sprintf("-- the type property values do not match: %s | %s", p.ObjectType, p2.ObjectType)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the type property values match: %s | %s", p.ObjectType, p2.ObjectType)
-		logValid(r, str)
-	}
-
-	// Spec Version
-	if p.SpecVersion != p2.SpecVersion {
-		str := fmt.Sprintf("-- the spec_version property values do not match: %s | %s", p.SpecVersion, p2.SpecVersion)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the spec_version property values match: %s | %s", p.SpecVersion, p2.SpecVersion)
-		logValid(r, str)
-	}
+	r := p.compare(p2, debug)
 
-	// ID
-	if p.ID != p2.ID {
-		str := fmt.Sprintf("-- the id property values do not match: %s | %s", p.ID, p2.ID)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the id property values match: %s | %s", p.ID, p2.ID)
-		logValid(r, str)
+	if r.ProblemsFound > 0 {
+		return false, r.ProblemsFound, r.ResultDetails
 	}
 
-	// Name
-	if p.Name != p2.Name {
-		str := fmt.Sprintf("-- the names property values do not match: %s | %s", p.Name, p2.Name)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the names property values match: %s | %s", p.Name, p2.Name)
-		logValid(r, str)
-	}
+	return true, 0, r.ResultDetails
+}
 
-	// Description
-	if p.Description != p2.Description {
-		str := fmt.Sprintf("-- the description property values do not match: %s | %s", p.Description, p2.Description)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the description property values match: %s | %s", p.Description, p2.Description)
-		logValid(r, str)
-	}
+// CompareFindings - This method will compare two objects in the same way as
+// Compare() and return the results as a list of findings.
+func (p *Playbook) CompareFindings(p2 *Playbook, debug bool) []objects.Finding {
+	r := p.compare(p2, debug)
+	return r.Findings
+}
 
-	// Playbook Types
-	if len(p.PlaybookTypes) != len(p2.PlaybookTypes) {
-		str := fmt.Sprintf("-- the number of entries in the playbook_types property do not match: %d | %d", len(p.PlaybookTypes), len(p2.PlaybookTypes))
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the number of entries in the playbook_types property match: %d | %d", len(p.PlaybookTypes), len(p2.PlaybookTypes))
-		logValid(r, str)
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
 
-		// If lengths are the same, then check each value
-		for index := range p.PlaybookTypes {
-			if p.PlaybookTypes[index] != p2.PlaybookTypes[index] {
-				str := fmt.Sprintf("-- the playbook_type values do not match: %s | %s", p.PlaybookTypes[index], p2.PlaybookTypes[index])
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the playbook_type values match: %s | %s", p.PlaybookTypes[index], p2.PlaybookTypes[index])
-				logValid(r, str)
-			}
+// compare - This method will compare the two playbooks with Diff() and log a
+// problem for each change, with the rule code of the top level property that
+// changed. If debug is enabled the top level properties that match are also
+// logged.
+func (p *Playbook) compare(p2 *Playbook, debug bool) *objects.Results {
+	var r *objects.Results = new(objects.Results)
+	r.Debug = debug
+
+	changes, err := p.Diff(p2)
+	if err != nil {
+		r.At("compare.playbook")
+		r.LogProblem(fmt.Sprintf("-- the playbooks could not be compared: %s", err))
+		return r
+	}
+
+	changed := make(map[string]bool)
+	for _, c := range changes {
+		tokens := strings.Split(c.Path, "/")[1:]
+		property := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+		changed[property] = true
+
+		// A value added to a set has no index yet, so the pointer is to the list
+		r.Rule = "compare." + property
+		r.Pointer = strings.TrimSuffix(c.Path, "/-")
+
+		switch c.Op {
+		case objects.ChangeAdd:
+			r.LogProblem(fmt.Sprintf("-- %s is only in the second playbook: %s", c.Path, objects.SummarizeValue(c.Value)))
+		case objects.ChangeRemove:
+			r.LogProblem(fmt.Sprintf("-- %s is only in the first playbook: %s", c.Path, objects.SummarizeValue(c.Old)))
+		default:
+			r.LogProblem(fmt.Sprintf("-- the %s values do not match: %s | %s", c.Path, objects.SummarizeValue(c.Old), objects.SummarizeValue(c.Value)))
 		}
 	}
 
-	// CreatedBy
-	if p.CreatedBy != p2.CreatedBy {
-		str := fmt.Sprintf("-- the created by values do not match: %s | %s", p.CreatedBy, p2.CreatedBy)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the created by values match: %s | %s", p.CreatedBy, p2.CreatedBy)
-		logValid(r, str)
-	}
-
-	// Created
-	if p.Created != p2.Created {
-		str := fmt.Sprintf("-- the created dates do not match: %s | %s", p.Created, p2.Created)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the created dates match: %s | %s", p.Created, p2.Created)
-		logValid(r, str)
-	}
-
-	// Modified
-	if p.Modified != p2.Modified {
-		str := fmt.Sprintf("-- the modified dates do not match: %s | %s", p.Modified, p2.Modified)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the modified dates match: %s | %s", p.Modified, p2.Modified)
-		logValid(r, str)
-	}
-
-	// Revoked
-	if p.Revoked != p2.Revoked {
-		str := fmt.Sprintf("-- the revoked values do not match: %t | %t", p.Revoked, p2.Revoked)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the revoked values match: %t | %t", p.Revoked, p2.Revoked)
-		logValid(r, str)
-	}
-
-	// Valid From
-	if p.ValidFrom != p2.ValidFrom {
-		str := fmt.Sprintf("-- the valid_from values do not match: %s | %s", p.ValidFrom, p2.ValidFrom)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the valid_from values match: %s | %s", p.ValidFrom, p2.ValidFrom)
-		logValid(r, str)
-	}
-
-	// Valid Until
-	if p.ValidUntil != p2.ValidUntil {
-		str := fmt.Sprintf("-- the valid_until values do not match: %s | %s", p.ValidUntil, p2.ValidUntil)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the valid_until values match: %s | %s", p.ValidUntil, p2.ValidUntil)
-		logValid(r, str)
-	}
-
-	// Derived From
-	if len(p.DerivedFrom) != len(p2.DerivedFrom) {
-		str := fmt.Sprintf("-- the number of entries in the derived_from property do not match: %d | %d", len(p.DerivedFrom), len(p2.DerivedFrom))
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the number of entries in the derived_from property match: %d | %d", len(p.DerivedFrom), len(p2.DerivedFrom))
-		logValid(r, str)
-
-		// If lengths are the same, then check each value
-		for index := range p.DerivedFrom {
-			if p.DerivedFrom[index] != p2.DerivedFrom[index] {
-				str := fmt.Sprintf("-- the derived_from values do not match: %s | %s", p.DerivedFrom[index], p2.DerivedFrom[index])
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the derived_from values match: %s | %s", p.DerivedFrom[index], p2.DerivedFrom[index])
-				logValid(r, str)
+	if debug {
+		for _, property := range topLevelProperties(p, p2) {
+			if !changed[property] {
+				r.At("compare."+property, property)
+				r.LogValid(fmt.Sprintf("++ the %s property values match", property))
 			}
 		}
 	}
 
-	// Priority
-	if p.Priority != p2.Priority {
-		str := fmt.Sprintf("-- the priority values do not match: %d | %d", p.Priority, p2.Priority)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the priority values match: %d | %d", p.Priority, p2.Priority)
-		logValid(r, str)
-	}
-
-	// Severity
-	if p.Severity != p2.Severity {
-		str := fmt.Sprintf("-- the severity values do not match: %d | %d", p.Severity, p2.Severity)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the severity values match: %d | %d", p.Severity, p2.Severity)
-		logValid(r, str)
-	}
-
-	// Impact
-	if p.Impact != p2.Impact {
-		str := fmt.Sprintf("-- the impact values do not match: %d | %d", p.Impact, p2.Impact)
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the impact values match: %d | %d", p.Impact, p2.Impact)
-		logValid(r, str)
-	}
+	// End
+	return r
+}
 
-	// Labels
-	if len(p.Labels) != len(p2.Labels) {
-		str := fmt.Sprintf("-- the number of entries in the labels property do not match: %d | %d", len(p.Labels), len(p2.Labels))
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the number of entries in the labels property match: %d | %d", len(p.Labels), len(p2.Labels))
-		logValid(r, str)
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
 
-		// If lengths are the same, then check each value
-		for index := range p.Labels {
-			if p.Labels[index] != p2.Labels[index] {
-				str := fmt.Sprintf("-- the label values do not match: %s | %s", p.Labels[index], p2.Labels[index])
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the label values match: %s | %s", p.Labels[index], p2.Labels[index])
-				logValid(r, str)
-			}
+// topLevelProperties - This function returns the sorted names of the
+// properties that are in either playbook.
+func topLevelProperties(p, p2 *Playbook) []string {
+	found := make(map[string]bool)
+	for _, pb := range []*Playbook{p, p2} {
+		data, err := json.Marshal(pb)
+		if err != nil {
+			continue
 		}
-	}
-
-	// Check External References
-	if len(p.ExternalReferences) != len(p2.ExternalReferences) {
-		str := fmt.Sprintf("-- the number of entries in the external references property do not match: %d | %d", len(p.ExternalReferences), len(p2.ExternalReferences))
-		logProblem(r, str)
-	} else {
-		str := fmt.Sprintf("++ the number of entries in the external references property match: %d | %d", len(p.ExternalReferences), len(p2.ExternalReferences))
-		logValid(r, str)
-		for index := range p.ExternalReferences {
-
-			// Check External Reference Name
-			if p.ExternalReferences[index].Name != p2.ExternalReferences[index].Name {
-				str := fmt.Sprintf("-- the name values do not match: %s | %s", p.ExternalReferences[index].Name, p2.ExternalReferences[index].Name)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the name values match: %s | %s", p.ExternalReferences[index].Name, p2.ExternalReferences[index].Name)
-				logValid(r, str)
-			}
-
-			// Check External Reference Descriptions
-			if p.ExternalReferences[index].Description != p2.ExternalReferences[index].Description {
-				str := fmt.Sprintf("-- the description values do not match: %s | %s", p.ExternalReferences[index].Description, p2.ExternalReferences[index].Description)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the description values match: %s | %s", p.ExternalReferences[index].Description, p2.ExternalReferences[index].Description)
-				logValid(r, str)
-			}
-
-			// Check External Reference Source
-			if p.ExternalReferences[index].Source != p2.ExternalReferences[index].Source {
-				str := fmt.Sprintf("-- the source values do not match: %s | %s", p.ExternalReferences[index].Source, p2.ExternalReferences[index].Source)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the source values match: %s | %s", p.ExternalReferences[index].Source, p2.ExternalReferences[index].Source)
-				logValid(r, str)
-			}
-
-			// Check External Reference URLs
-			if p.ExternalReferences[index].URL != p2.ExternalReferences[index].URL {
-				str := fmt.Sprintf("-- the url values do not match: %s | %s", p.ExternalReferences[index].URL, p2.ExternalReferences[index].URL)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the url values match: %s | %s", p.ExternalReferences[index].URL, p2.ExternalReferences[index].URL)
-				logValid(r, str)
-			}
-
-			// Check External Reference External IDs
-			if p.ExternalReferences[index].ExternalID != p2.ExternalReferences[index].ExternalID {
-				str := fmt.Sprintf("-- the external_id values do not match: %s | %s", p.ExternalReferences[index].ExternalID, p2.ExternalReferences[index].ExternalID)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the external_id values match: %s | %s", p.ExternalReferences[index].ExternalID, p2.ExternalReferences[index].ExternalID)
-				logValid(r, str)
-			}
-
-			// Check External Reference Reference IDs
-			if p.ExternalReferences[index].ReferenceID != p2.ExternalReferences[index].ReferenceID {
-				str := fmt.Sprintf("-- the reference_id values do not match: %s | %s", p.ExternalReferences[index].ReferenceID, p2.ExternalReferences[index].ReferenceID)
-				logProblem(r, str)
-			} else {
-				str := fmt.Sprintf("++ the reference_id values match: %s | %s", p.ExternalReferences[index].ReferenceID, p2.ExternalReferences[index].ReferenceID)
-				logValid(r, str)
+		var m map[string]json.RawMessage
+		if json.Unmarshal(data, &m) == nil {
+			for name := range m {
+				found[name] = true
 			}
 		}
 	}
 
-	// Features
-	// if p.Features.ParallelProcessing != p2.Features.ParallelProcessing {
-	// 	str := fmt.Sprintf("-- the features parallel-processing values do not match: %s | %s", p.Features.ParallelProcessing, p2.Features.ParallelProcessing)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features parallel-processing values match: %s | %s", p.Features.ParallelProcessing, p2.Features.ParallelProcessing)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.IfLogic != p2.Features.IfLogic {
-	// 	str := fmt.Sprintf("-- the features if-logic values do not match: %s | %s", p.Features.IfLogic, p2.Features.IfLogic)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features if-logic values match: %s | %s", p.Features.IfLogic, p2.Features.IfLogic)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.WhileLogic != p2.Features.WhileLogic {
-	// 	str := fmt.Sprintf("-- the features while-logic values do not match: %s | %s", p.Features.WhileLogic, p2.Features.WhileLogic)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features while-logic values match: %s | %s", p.Features.WhileLogic, p2.Features.WhileLogic)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.SwitchLogic != p2.Features.SwitchLogic {
-	// 	str := fmt.Sprintf("-- the features switch-logic values do not match: %s | %s", p.Features.SwitchLogic, p2.Features.SwitchLogic)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features switch-logic values match: %s | %s", p.Features.SwitchLogic, p2.Features.SwitchLogic)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.TemporalLogic != p2.Features.TemporalLogic {
-	// 	str := fmt.Sprintf("-- the features temporal-logic values do not match: %s | %s", p.Features.TemporalLogic, p2.Features.TemporalLogic)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features temporal-logic values match: %s | %s", p.Features.TemporalLogic, p2.Features.TemporalLogic)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.DataMarkings != p2.Features.DataMarkings {
-	// 	str := fmt.Sprintf("-- the features data-markings values do not match: %s | %s", p.Features.DataMarkings, p2.Features.DataMarkings)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features data-markings values match: %s | %s", p.Features.DataMarkings, p2.Features.DataMarkings)
-	// 	logValid(r, str)
-	// }
-
-	// if p.Features.Extensions != p2.Features.Extensions {
-	// 	str := fmt.Sprintf("-- the features extensions values do not match: %s | %s", p.Features.Extensions, p2.Features.Extensions)
-	// 	logProblem(r, str)
-	// } else {
-	// 	str := fmt.Sprintf("++ the features extensions values match: %s | %s", p.Features.Extensions, p2.Features.Extensions)
-	// 	logValid(r, str)
-	// }
-
-	// TODO need finish fleshing this out
-
-	// End
-	if r.problemsFound > 0 {
-		return false, r.problemsFound, r.resultDetails
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
 	}
-
-	return true, 0, r.resultDetails
+	sort.Strings(names)
+	return names
 }
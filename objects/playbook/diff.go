@@ -0,0 +1,60 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// diffOptions - These are the lists that the specification does not give an
+// order to, so they are compared as sets, and the lists of objects that are
+// paired by their ID. Every other list, like the commands of an action step or
+// a certificate chain, is compared in order.
+var diffOptions = &objects.DiffOptions{
+	SetLists: []string{
+		"category",
+		"derived_from",
+		"in_args",
+		"industry_sectors",
+		"labels",
+		"logical",
+		"markings",
+		"next_steps",
+		"out_args",
+		"playbook_activities",
+		"playbook_types",
+		"related_to",
+		"targets",
+	},
+	KeyedLists: map[string]string{
+		"signatures": "id",
+	},
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Diff - This method will compare the playbook with another playbook, like a
+// newer version of it, and return the changes that turn this playbook into
+// the other one. The whole playbook is compared, including the workflow,
+// variables, agents, targets, data markings, extensions and signatures.
+// Workflow steps and the other dictionaries are paired by their keys and
+// signatures by their IDs. Lists that the specification does not give an
+// order to, like labels and the next steps of a parallel step, are compared
+// as sets. The changes can be rendered with objects.RenderChanges() or
+// encoded as a JSON Patch with objects.JSONPatch().
+func (p *Playbook) Diff(p2 *Playbook) ([]objects.Change, error) {
+	a, err := p.Canonical()
+	if err != nil {
+		return nil, err
+	}
+	b, err := p2.Canonical()
+	if err != nil {
+		return nil, err
+	}
+	return objects.DiffJSON(a, b, diffOptions)
+}
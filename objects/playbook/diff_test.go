@@ -0,0 +1,105 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/signature"
+	"github.com/openplaybooks/libcacao/objects/workflow"
+)
+
+// TestDiff - This will test the structural diff of two versions of a playbook
+// and comparing them
+func TestDiff(t *testing.T) {
+	p1 := newSpecExamplePlaybook()
+	data, _ := p1.Encode()
+	p2, _ := Decode(data)
+
+	if changes, err := p1.Diff(p2); err != nil || len(changes) != 0 {
+		t.Fatalf("1.1 the same playbook has changes %v %v", changes, err)
+	}
+	if valid, problems, details := p1.Compare(p2, true); !valid || problems != 0 || !strings.HasPrefix(details[0], "++ ") {
+		t.Errorf("1.2 Compare of the same playbook returned %t %d %v", valid, problems, details)
+	}
+
+	// Labels are a set, so reordering them is not a change
+	p2.Labels = []string{"apt", "malware", "fuzzypanda", "new"}
+	p2.Workflow["action--7f40f9d7-de39-4027-ab97-15035beff2ff"].(*workflow.ActionStep).Name = "IP Search"
+	step, _ := workflow.NewActionStep()
+	step.ID = "action--36aa2b76-1a3c-4f0c-8b8e-2f1b7ba7b2b6"
+	step.Name = "Block IP"
+	p2.AddWorkflowStep(step)
+	p2.PlaybookVariables["__data_exfil_site__"] = objects.Variables{ObjectType: "ipv4-addr", Value: "5.6.7.8"}
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sig := signature.New()
+	sig.ID = "jss--b9f1e6a8-6c3a-4c5e-9d0a-6f3b2c1d0e9f"
+	sig.Signee = "ACME"
+	sig.Created, sig.Modified = "2023-03-01T00:00:00.000Z", "2023-03-01T00:00:00.000Z"
+	if err := p2.Sign("ES256", key, sig); err != nil {
+		t.Fatalf("unable to sign: %s", err)
+	}
+
+	changes, err := p1.Diff(p2)
+	if err != nil {
+		t.Fatalf("2.1 Diff returned an error: %s", err)
+	}
+	report := objects.RenderChanges(changes)
+	want := []string{
+		`+ /labels/-: "new"`,
+		`- /playbook_variables/__data_exfil_site__/description: "The IP address for the data exfiltration site"`,
+		`~ /playbook_variables/__data_exfil_site__/value: "1.2.3.4" -> "5.6.7.8"`,
+		`+ /signatures: [{"algorithm":"ES256","created":"2023-03-01T00:00:00.000Z","id":"jss--b9f1e6a8-6...`,
+		`+ /workflow/action--36aa2b76-1a3c-4f0c-8b8e-2f1b7ba7b2b6: action "Block IP"`,
+		`~ /workflow/action--7f40f9d7-de39-4027-ab97-15035beff2ff/name: "IP Lookup" -> "IP Search"`,
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("2.2 the report is not correct\n%s", strings.Join(report, "\n"))
+	}
+
+	patch, err := objects.JSONPatch(changes)
+	if err != nil || !strings.Contains(string(patch), `"op": "replace",`+"\n"+`    "path": "/workflow/action--7f40f9d7-de39-4027-ab97-15035beff2ff/name"`) {
+		t.Errorf("2.3 the JSON Patch is not correct %v\n%s", err, patch)
+	}
+
+	// A second signature is paired with the first by its ID
+	p3, _ := Decode(mustEncode(t, p2))
+	sig2 := signature.New()
+	sig2.Signee = "ACME"
+	p3.Sign("ES256", key, sig2)
+	p3.Signatures[0].Revoked = true
+	changes, _ = p2.Diff(p3)
+	if report := objects.RenderChanges(changes); len(report) != 2 || report[0] != "+ /signatures/0/revoked: true" || !strings.HasPrefix(report[1], "+ /signatures/-: ") {
+		t.Errorf("2.4 the signatures were not paired by ID\n%s", strings.Join(report, "\n"))
+	}
+
+	valid, problems, _ := p1.Compare(p2, false)
+	if valid || problems != len(want) {
+		t.Errorf("2.5 Compare returned %t %d", valid, problems)
+	}
+	findings := p1.CompareFindings(p2, false)
+	if f := findings[0]; f.Rule != "compare.labels" || f.Pointer != "/labels" || f.Message != `/labels/- is only in the second playbook: "new"` {
+		t.Errorf("2.6 the finding is not correct %+v", f)
+	}
+	if f := findings[len(findings)-1]; f.Rule != "compare.workflow" || f.Message != `the /workflow/action--7f40f9d7-de39-4027-ab97-15035beff2ff/name values do not match: "IP Lookup" | "IP Search"` {
+		t.Errorf("2.7 the finding is not correct %+v", f)
+	}
+}
+
+// mustEncode - This will encode a playbook or fail the test
+func mustEncode(t *testing.T, p *Playbook) []byte {
+	data, err := p.Encode()
+	if err != nil {
+		t.Fatalf("unable to encode playbook: %s", err)
+	}
+	return data
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto"
+
+	"github.com/openplaybooks/libcacao/objects/jwe"
+)
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// DecodeJWE - This function will decrypt a JWE envelope created by Encrypt()
+// with the private key of one of the recipients and decode the playbook in
+// it. The signatures on the playbook are not verified, use Verify() or
+// VerifyAll() for that.
+func DecodeJWE(data []byte, key crypto.PrivateKey) (*Playbook, error) {
+	plaintext, _, err := jwe.Decrypt(data, key)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(plaintext)
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Encrypt - This method will encode the playbook and encrypt it in a JWE
+// envelope with A256GCM, so that only the recipients can read it. This is
+// meant for playbooks that are marked TLP:RED. The envelope covers the
+// signatures on the playbook, so sign the playbook first to sign-then-encrypt.
+func (p *Playbook) Encrypt(recipients ...jwe.Recipient) ([]byte, error) {
+	data, err := p.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return jwe.Encrypt(data, jwe.A256GCM, "json", recipients...)
+}
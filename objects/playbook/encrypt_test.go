@@ -0,0 +1,70 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/jwe"
+	"github.com/openplaybooks/libcacao/objects/markings"
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// TestEncrypt - This will test that a signed TLP:RED playbook can be
+// encrypted for several partners and that each of them can decrypt it and
+// verify the signature
+func TestEncrypt(t *testing.T) {
+	signingKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	partner1, _ := rsa.GenerateKey(rand.Reader, 2048)
+	partner2, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	intermediary, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	p := newSpecExamplePlaybook()
+	red := markings.NewTLPRedMarking()
+	p.AddMarkingDefinition(red)
+	p.AddMarkings(red.ID)
+
+	sig := signature.New()
+	sig.Signee = "ACME Cyber Company"
+	if err := p.Sign("ES256", signingKey, sig); err != nil {
+		t.Fatalf("1.1 unable to sign playbook: %s", err)
+	}
+
+	data, err := p.Encrypt(
+		jwe.Recipient{Algorithm: jwe.RSAOAEP, KeyID: "partner-1", Key: &partner1.PublicKey},
+		jwe.Recipient{Algorithm: jwe.ECDHESA256KW, KeyID: "partner-2", Key: &partner2.PublicKey},
+	)
+	if err != nil {
+		t.Fatalf("1.2 unable to encrypt playbook: %s", err)
+	}
+	if bytes.Contains(data, []byte(p.ID)) || bytes.Contains(data, []byte("TLP:RED")) {
+		t.Errorf("1.3 the envelope exposes the playbook")
+	}
+
+	for i, key := range []interface{}{partner1, partner2} {
+		p2, err := DecodeJWE(data, key)
+		if err != nil {
+			t.Errorf("1.4.%d unable to decrypt playbook: %s", i, err)
+			continue
+		}
+		if p2.ID != p.ID || p2.DataMarkingDefinitions[red.ID] == nil {
+			t.Errorf("1.5.%d the decrypted playbook is not the same", i)
+		}
+		if err := p2.Verify(&p2.Signatures[0], &signingKey.PublicKey); err != nil {
+			t.Errorf("1.6.%d the signature did not verify after decryption: %s", i, err)
+		}
+	}
+
+	if _, err := DecodeJWE(data, intermediary); !errors.Is(err, jwe.ErrNoRecipient) {
+		t.Errorf("1.7 DecodeJWE with a key that is not a recipient returned %v", err)
+	}
+}
@@ -5,37 +5,115 @@
 
 package playbook
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/agents"
+	"github.com/openplaybooks/libcacao/objects/authinfo"
+	"github.com/openplaybooks/libcacao/objects/markings"
+	"github.com/openplaybooks/libcacao/objects/workflow"
+)
 
 // Decode - This function is a simple wrapper for decoding JSON data. It will
 // decode a slice of bytes into an actual struct and return a pointer to that
-// object along with any errors.
+// object along with any errors. Syntax and type errors in the JSON are returned
+// as an *objects.DecodeError with the line and column where they were found.
 func Decode(data []byte) (*Playbook, error) {
-	var p Playbook
-
-	if err := json.Unmarshal(data, &p); err != nil {
-		return nil, err
+	p, err := decode(data)
+	if err != nil {
+		return nil, objects.NewDecodeError(data, err)
 	}
 
-	return &p, nil
+	return p, nil
 }
 
-// // UnmarshalJSON - This method will over write the default UnmarshalJSON method
-// // to enable custom processing that we may need to do.
-// func (p *Playbook) UnmarshalJSON(b []byte) error {
+// UnmarshalJSON - This method will over write the default UnmarshalJSON method
+// to enable custom processing that we need to do. The workflow, authentication
+// information, agent, target, and data marking dictionaries hold interface
+// values, so each entry is decoded into its concrete type based on its type
+// property.
+func (p *Playbook) UnmarshalJSON(b []byte) error {
+
+	type alias Playbook
+	temp := &struct {
+		Workflow                      map[string]json.RawMessage `json:"workflow,omitempty"`
+		AuthenticationInfoDefinitions map[string]json.RawMessage `json:"authentication_info_definitions,omitempty"`
+		AgentDefinitions              map[string]json.RawMessage `json:"agent_definitions,omitempty"`
+		TargetDefinitions             map[string]json.RawMessage `json:"target_definitions,omitempty"`
+		DataMarkingDefinitions        map[string]json.RawMessage `json:"data_marking_definitions,omitempty"`
+		*alias
+	}{
+		alias: (*alias)(p),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	if temp.Workflow != nil {
+		p.Workflow = make(map[string]workflow.StepObject, len(temp.Workflow))
+		for k, v := range temp.Workflow {
+			step, err := workflow.Decode(k, v)
+			if err != nil {
+				return nestedError(err, "workflow step", "workflow", k)
+			}
+			p.Workflow[k] = step
+		}
+	}
+
+	if temp.AuthenticationInfoDefinitions != nil {
+		p.AuthenticationInfoDefinitions = make(map[string]authinfo.AuthInfoObject, len(temp.AuthenticationInfoDefinitions))
+		for k, v := range temp.AuthenticationInfoDefinitions {
+			info, err := authinfo.Decode(k, v)
+			if err != nil {
+				return nestedError(err, "authentication info", "authentication_info_definitions", k)
+			}
+			p.AuthenticationInfoDefinitions[k] = info
+		}
+	}
+
+	if temp.AgentDefinitions != nil {
+		p.AgentDefinitions = make(map[string]agents.AgentObject, len(temp.AgentDefinitions))
+		for k, v := range temp.AgentDefinitions {
+			agent, err := agents.Decode(k, v)
+			if err != nil {
+				return nestedError(err, "agent", "agent_definitions", k)
+			}
+			p.AgentDefinitions[k] = agent
+		}
+	}
+
+	if temp.TargetDefinitions != nil {
+		p.TargetDefinitions = make(map[string]agents.AgentObject, len(temp.TargetDefinitions))
+		for k, v := range temp.TargetDefinitions {
+			target, err := agents.Decode(k, v)
+			if err != nil {
+				return nestedError(err, "target", "target_definitions", k)
+			}
+			p.TargetDefinitions[k] = target
+		}
+	}
+
+	for k, v := range p.ExtensionDefinitions {
+		v.SetID(k)
+		p.ExtensionDefinitions[k] = v
+	}
 
-// 	type alias Playbook
-// 	temp := &struct {
-// 		*alias
-// 	}{
-// 		alias: (*alias)(p),
-// 	}
-// 	if err := json.Unmarshal(b, &temp); err != nil {
-// 		return err
-// 	}
+	if temp.DataMarkingDefinitions != nil {
+		p.DataMarkingDefinitions = make(map[string]markings.DataMarkingObject, len(temp.DataMarkingDefinitions))
+		for k, v := range temp.DataMarkingDefinitions {
+			marking, err := markings.Decode(k, v)
+			if err != nil {
+				return nestedError(err, "data marking", "data_marking_definitions", k)
+			}
+			p.DataMarkingDefinitions[k] = marking
+		}
+	}
 
-// 	return nil
-// }
+	p.updateExtensionsFeature()
+
+	return nil
+}
 
 // Encode - This method is a simple wrapper for encoding an object into JSON
 func (p *Playbook) Encode() ([]byte, error) {
@@ -57,3 +135,34 @@ func (p *Playbook) EncodeToString() (string, error) {
 	}
 	return string(data), nil
 }
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// decode - This function will decode the JSON data into a playbook
+func decode(data []byte) (*Playbook, error) {
+	var p Playbook
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// nestedError - This function will wrap a type or syntax error from a nested
+// decoder with the object that was being decoded and where it is in the
+// playbook. The offset in these errors is into the object and not the
+// playbook, so Decode() uses the JSON Pointer to work out the line and column.
+func nestedError(err error, object, property, id string) error {
+	switch err.(type) {
+	case *json.UnmarshalTypeError, *json.SyntaxError:
+		return &objects.NestedError{
+			Pointer: objects.JSONPointer(property, id),
+			Object:  object + " " + id,
+			Err:     err,
+		}
+	}
+	return err
+}
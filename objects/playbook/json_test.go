@@ -0,0 +1,327 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/agents"
+	"github.com/openplaybooks/libcacao/objects/authinfo"
+	"github.com/openplaybooks/libcacao/objects/extensions"
+	"github.com/openplaybooks/libcacao/objects/markings"
+	"github.com/openplaybooks/libcacao/objects/signature"
+	"github.com/openplaybooks/libcacao/objects/workflow"
+)
+
+// newSpecExamplePlaybook - This will create the playbook from the section 3.1
+// example so it can be used in various tests
+func newSpecExamplePlaybook() *Playbook {
+	p := New()
+	p.ID = "playbook--61a6c41e-6efc-4516-a242-dfbc5c89d562"
+	p.Name = "Find Malware FuzzyPanda"
+	p.Description = "This playbook will look for FuzzyPanda on the network and in a SIEM"
+	p.AddPlaybookTypes("investigation")
+	p.AddPlaybookActivities("analyze-collected-data,identify-indicators")
+	p.CreatedBy = "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1"
+	p.Created = "2023-02-19T08:00:24.918Z"
+	p.Modified = "2023-02-19T08:00:24.918Z"
+	p.ValidFrom = "2023-02-19T08:00:24.918Z"
+	p.ValidUntil = "2023-12-31T23:59:59.999Z"
+	p.AddDerivedFrom("playbook--00ee41a2-c2ca-41da-8ea9-681344eb3926")
+	p.Priority = 3
+	p.Severity = 70
+	p.Impact = 5
+	p.AddIndustrySectors("aerospace,defense")
+	p.AddLabels("malware,fuzzypanda,apt")
+
+	r1, _ := p.NewExternalReference()
+	r1.Name = "ACME Security FuzzyPanda Report"
+	r1.Description = "ACME security review of FuzzyPanda 2021"
+	r1.URL = "hxxp://www[.]example[.]com/info/fuzzypanda2021.html"
+
+	m1 := markings.NewStatementMarking()
+	m1.ID = "marking-statement--6424867b-0440-4885-bd0b-604d51786d06"
+	m1.Statement = "Copyright 2023 ACME Security Company"
+	m1.CreatedBy = "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1"
+	m1.Created = "2023-02-19T08:00:24.918Z"
+	m2 := markings.NewTLPGreenMarking()
+	p.AddMarkings([]string{m1.GetID(), m2.GetID()})
+	p.AddMarkingDefinition(m1)
+	p.AddMarkingDefinition(m2)
+
+	v1 := objects.NewVariable()
+	v1.ObjectType = "ipv4-addr"
+	v1.Name = "__data_exfil_site__"
+	v1.Description = "The IP address for the data exfiltration site"
+	v1.Value = "1.2.3.4"
+	p.AddVariable(*v1)
+
+	a1, _ := agents.NewIndividual()
+	a1.ID = "individual--8e7f3d95-7e86-4b3b-9a58-5e4b8e0b0f4e"
+	a1.Name = "SOC Analyst"
+	a1.NewContact().AddEmail("work", "soc@example.com")
+	p.AddAgent(a1)
+
+	ai1, _ := authinfo.NewHTTPBasic()
+	ai1.ID = "http-basic--2b0dc6a3-9c86-4c5a-9a4c-1a3c1f2b3c4d"
+	ai1.UserID = "soc"
+	ai1.KMS = true
+	ai1.KMSKeyIdentifier = "vault:secret/data/soc"
+	p.AddAuthenticationInfo(ai1)
+
+	t1, _ := agents.NewNetAddress()
+	t1.ID = "net-address--d4d2f2a4-55f6-4d4d-9b0a-4c0f0fa1d6a1"
+	t1.Name = "Exfil Site"
+	t1.AddAddress("ipv4", "1.2.3.4")
+	p.AddTarget(t1)
+
+	start, _ := workflow.NewStartStep()
+	start.ID = "start--07bea005-4a36-4a77-bd1f-79a6e4682a13"
+	end, _ := workflow.NewEndStep()
+	end.ID = "end--6b23c237-ade8-4d00-9aa1-75999738d557"
+
+	step1, _ := workflow.NewActionStep()
+	step1.ID = "action--7f40f9d7-de39-4027-ab97-15035beff2ff"
+	step1.Name = "IP Lookup"
+	step1.Agent = a1.ID
+	step1.Targets = []string{t1.ID}
+	cmd1, _ := step1.NewCommand()
+	cmd1.SetManual()
+	cmd1.Command = "Look up IP __data_exfil_site__:value in SIEM"
+
+	step2, _ := workflow.NewIfStep()
+	step2.ID = "if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3"
+	step2.Condition = "__data_exfil_site__:value != \"\""
+	step2.AddOnTrue(end.ID)
+
+	p.WorkflowStart = start.ID
+	start.OnCompletion = step1.ID
+	step1.OnCompletion = step2.ID
+	step2.OnCompletion = end.ID
+
+	p.AddWorkflowStep(start)
+	p.AddWorkflowStep(step1)
+	p.AddWorkflowStep(step2)
+	p.AddWorkflowStep(end)
+	return p
+}
+
+// TestDecodeRoundTrip - This will test that encoding and decoding a playbook
+// with polymorphic workflow steps, agents, targets, and markings is lossless
+func TestDecodeRoundTrip(t *testing.T) {
+	p := newSpecExamplePlaybook()
+	for k, v := range p.AuthenticationInfoDefinitions {
+		if v.GetCommon().ID != "" {
+			t.Errorf("1.0 AddAuthenticationInfo did not clear the ID of %s", k)
+		}
+	}
+
+	data1, err := p.Encode()
+	if err != nil {
+		t.Fatalf("1.1 unable to encode playbook: %s", err)
+	}
+
+	p2, err := Decode(data1)
+	if err != nil {
+		t.Fatalf("1.2 unable to decode playbook: %s", err)
+	}
+
+	data2, err := p2.Encode()
+	if err != nil {
+		t.Fatalf("1.3 unable to encode decoded playbook: %s", err)
+	}
+
+	if string(data1) != string(data2) {
+		t.Errorf("1.4 round trip was not lossless\nExpected: %s\nHave: %s", data1, data2)
+	}
+
+	if step, ok := p2.Workflow["action--7f40f9d7-de39-4027-ab97-15035beff2ff"].(*workflow.ActionStep); !ok {
+		t.Errorf("1.5 action step was not decoded as *workflow.ActionStep")
+	} else {
+		if step.ID != "action--7f40f9d7-de39-4027-ab97-15035beff2ff" {
+			t.Errorf("1.6 action step ID was not restored from the map key, have %s", step.ID)
+		}
+		if len(step.Commands) != 1 || step.Commands[0].ObjectType != "manual" {
+			t.Errorf("1.7 action step commands were not decoded correctly")
+		}
+	}
+
+	if _, ok := p2.Workflow["if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3"].(*workflow.IfStep); !ok {
+		t.Errorf("1.8 if step was not decoded as *workflow.IfStep")
+	}
+
+	if _, ok := p2.DataMarkingDefinitions["marking-tlp--bab4a63c-aed9-4cf5-a766-dfca5abac2bb"].(*markings.MarkingTLP); !ok {
+		t.Errorf("1.9 tlp marking was not decoded as *markings.MarkingTLP")
+	}
+
+	if agent, ok := p2.AgentDefinitions["individual--8e7f3d95-7e86-4b3b-9a58-5e4b8e0b0f4e"].(*agents.Individual); !ok {
+		t.Errorf("1.10 agent was not decoded as *agents.Individual")
+	} else if agent.ID != "individual--8e7f3d95-7e86-4b3b-9a58-5e4b8e0b0f4e" {
+		t.Errorf("1.11 agent ID was not restored from the map key, have %s", agent.ID)
+	}
+
+	if info, ok := p2.AuthenticationInfoDefinitions["http-basic--2b0dc6a3-9c86-4c5a-9a4c-1a3c1f2b3c4d"].(*authinfo.HTTPBasic); !ok {
+		t.Errorf("1.13 authentication information was not decoded as *authinfo.HTTPBasic")
+	} else if info.ID != "http-basic--2b0dc6a3-9c86-4c5a-9a4c-1a3c1f2b3c4d" {
+		t.Errorf("1.14 authentication information ID was not restored from the map key, have %s", info.ID)
+	}
+
+	if _, ok := p2.TargetDefinitions["net-address--d4d2f2a4-55f6-4d4d-9b0a-4c0f0fa1d6a1"].(*agents.NetAddress); !ok {
+		t.Errorf("1.12 target was not decoded as *agents.NetAddress")
+	}
+}
+
+// TestDecodeUnknownStepType - This will test that an unknown workflow step type
+// is reported as an error instead of being silently dropped
+func TestDecodeUnknownStepType(t *testing.T) {
+	data := []byte(`{"type":"playbook","workflow":{"foo--1":{"type":"foo"}}}`)
+	if _, err := Decode(data); err == nil {
+		t.Errorf("2.1 decoding an unknown workflow step type did not return an error")
+	}
+}
+
+// TestDecodeExtensions - This will test that playbook, step, and marking
+// extensions survive a round trip and set the processing summary
+func TestDecodeExtensions(t *testing.T) {
+	p := newSpecExamplePlaybook()
+
+	e := extensions.New()
+	e.ID = "extension-definition--1f7b9b52-2b0d-4a8c-9d25-2f54ec0c1d5b"
+	e.Name = "ACME Extension"
+	e.CreatedBy = "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1"
+	e.Schema = "https://example.com/acme/schema.json"
+	e.Version = "1.0.0"
+	p.AddExtensionDefinition(e)
+	p.AddExtension(e.ID, map[string]string{"ticket": "ACME-1"})
+
+	step, _ := workflow.NewEndStep()
+	step.AddExtension(e.ID, map[string]int{"retries": 3})
+	p.AddWorkflowStep(step)
+
+	if p.PlaybookProcessingSummary == nil || !p.PlaybookProcessingSummary.Extensions {
+		t.Errorf("3.1 the extensions processing summary flag was not set")
+	}
+
+	data1, _ := p.Encode()
+	p2, err := Decode(data1)
+	if err != nil {
+		t.Fatalf("3.2 unable to decode playbook: %s", err)
+	}
+	data2, _ := p2.Encode()
+	if string(data1) != string(data2) {
+		t.Errorf("3.3 round trip was not lossless\nExpected: %s\nHave: %s", data1, data2)
+	}
+
+	if p2.ExtensionDefinitions[e.ID].ID != e.ID {
+		t.Errorf("3.4 extension definition ID was not restored from the map key")
+	}
+
+	r := new(objects.Results)
+	setup(r)
+	p2.checkExtensions(r)
+	if r.ProblemsFound != 0 {
+		t.Errorf("3.5 checkExtensions returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	setup(r)
+	delete(p2.ExtensionDefinitions, e.ID)
+	p2.checkExtensions(r)
+	if r.ProblemsFound != 2 {
+		t.Errorf("3.6 checkExtensions returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// A playbook with only step extensions records them when it is decoded
+	data := []byte(`{"type":"playbook","workflow":{"end--1":{"type":"end","step_extensions":{"` + e.ID + `":{"retries":3}}}}}`)
+	p3, err := Decode(data)
+	if err != nil || p3.PlaybookProcessingSummary == nil || !p3.PlaybookProcessingSummary.Extensions {
+		t.Errorf("3.7 the extensions processing summary flag was not set when decoding step extensions %v", err)
+	}
+
+	// An extension added to a step after the step was added to the playbook is
+	// recorded when the playbook is signed
+	p4 := New()
+	end, _ := workflow.NewEndStep()
+	p4.AddWorkflowStep(end)
+	end.AddExtension(e.ID, map[string]int{"retries": 3})
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sig := signature.New()
+	sig.Signee = "ACME"
+	if err := p4.Sign("ES256", key, sig); err != nil {
+		t.Fatalf("unable to sign: %s", err)
+	}
+	if !p4.PlaybookProcessingSummary.Extensions {
+		t.Errorf("3.8 the extensions processing summary flag was not set for an extension added to a step later")
+	}
+}
+
+// TestDecodeErrors - This will test that decode errors report the line and
+// column in the JSON
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		data   string
+		line   int
+		column int
+	}{
+		{"{\n  \"type\": \"playbook\",\n  \"name\": \"x\",,\n}", 3, 15},
+		{"{\n  \"type\": \"playbook\",\n  \"name\": 5\n}", 3, 11},
+		{"{\"type\": \"playbook\"", 1, 19},
+		{"{\n  \"type\": \"playbook\",\n  \"name\": 1234\n}", 3, 11},
+		{"{\n  \"type\": \"playbook\",\n  \"labels\": \"a \\\" b\"\n}", 3, 13},
+	}
+
+	for i, test := range tests {
+		_, err := Decode([]byte(test.data))
+		var de *objects.DecodeError
+		if !errors.As(err, &de) || de.Line != test.line || de.Column != test.column {
+			t.Errorf("4.%d Decode returned %v instead of an error at line %d column %d", i, err, test.line, test.column)
+		}
+	}
+
+	// Errors in nested objects say which object and have the position in the
+	// playbook
+	data := "{\n  \"workflow\": {\n    \"action--1\": {\"type\": \"action\", \"name\": 55}\n  }\n}"
+	_, err := Decode([]byte(data))
+	var de *objects.DecodeError
+	if !errors.As(err, &de) || de.Line != 3 || de.Column != 45 || !strings.HasSuffix(err.Error(), "in workflow step action--1") {
+		t.Errorf("4.5 Decode returned %v for an error in a workflow step", err)
+	}
+	_, err = Decode([]byte(`{"agent_definitions": {"individual--1": {"type": "individual", "name": ["x"]}}}`))
+	if !errors.As(err, &de) || de.Line != 1 || de.Column != 72 || !strings.HasSuffix(err.Error(), "in agent individual--1") {
+		t.Errorf("4.6 Decode returned %v for an error in an agent", err)
+	}
+}
+
+// TestDecodeUnknownAgentType - This will test that an agent or target with a
+// type that is not defined by the specification is kept, since the agent and
+// target types are an open vocabulary
+func TestDecodeUnknownAgentType(t *testing.T) {
+	data := []byte(`{"type":"playbook","agent_definitions":{"acme-soar--1":{"type":"acme-soar","name":"SOAR","endpoint":{"url":"https://soar.example.com"}}}}`)
+	p, err := Decode(data)
+	if err != nil {
+		t.Fatalf("5.1 decoding an unknown agent type returned an error: %s", err)
+	}
+	agent, ok := p.AgentDefinitions["acme-soar--1"].(*agents.Generic)
+	if !ok || agent.ID != "acme-soar--1" || agent.Name != "SOAR" || string(agent.Properties["endpoint"]) != `{"url":"https://soar.example.com"}` {
+		t.Fatalf("5.2 the unknown agent type was not decoded as *agents.Generic %+v", p.AgentDefinitions["acme-soar--1"])
+	}
+
+	out, _ := json.Marshal(p.AgentDefinitions)
+	if string(out) != `{"acme-soar--1":{"endpoint":{"url":"https://soar.example.com"},"name":"SOAR","type":"acme-soar"}}` {
+		t.Errorf("5.3 the unknown agent type was not encoded without loss %s", out)
+	}
+
+	data = []byte(`{"type":"playbook","target_definitions":{"target--1":{"name":"No type"}}}`)
+	if _, err := Decode(data); err == nil {
+		t.Errorf("5.4 decoding a target without a type did not return an error")
+	}
+}
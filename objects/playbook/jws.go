@@ -0,0 +1,98 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// JWSPayload - This method will return the detached payload of the JWS for the
+// signature with the ID given. It is the JCS version of the playbook that was
+// signed by Sign(), so generic JOSE tools can verify an exported JWS with it.
+func (p *Playbook) JWSPayload(id string) ([]byte, error) {
+	sig := p.findSignature(id)
+	if sig == nil {
+		return nil, fmt.Errorf("the playbook does not have a signature with the id %s", id)
+	}
+	return p.signingPayload(*sig)
+}
+
+// ExportJWS - This method will export the signature with the ID given as a
+// compact JWS with a detached payload (RFC 7515 appendix F). The payload is
+// the JCS version of the playbook that was signed, see JWSPayload(). A JWS
+// signature can not be derived from the signature value, so the playbook is
+// signed again with the key, which must be the key that created the signature.
+// If the signature was imported from a JWS, it is returned as is and the key
+// can be nil.
+func (p *Playbook) ExportJWS(id string, key interface{}) (string, error) {
+	sig := p.findSignature(id)
+	if sig == nil {
+		return "", fmt.Errorf("the playbook does not have a signature with the id %s", id)
+	}
+	if signature.IsDetachedJWS(sig.Value) {
+		return sig.Value, nil
+	}
+
+	// Make sure the JWS is created by the same key as the signature
+	k, ok := key.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return "", fmt.Errorf("%w: a private key or signer is needed, not %T", ErrMissingKey, key)
+	}
+	if err := p.Verify(sig, k.Public()); err != nil {
+		return "", err
+	}
+
+	payload, err := p.signingPayload(*sig)
+	if err != nil {
+		return "", err
+	}
+	return sig.DetachedJWS(key, payload)
+}
+
+// ImportJWS - This method will import a compact JWS with a detached payload,
+// created by ExportJWS() or by JOSE tools with the same protected header, and
+// attach it to the playbook as a signature object. The JWS is verified with
+// the public key against the JCS version of the playbook before it is
+// attached, and the value of the signature object is the JWS itself, so
+// Verify() checks it the same way later.
+func (p *Playbook) ImportJWS(jws string, key crypto.PublicKey) (*signature.Signature, error) {
+	header, err := signature.ParseDetachedJWS(jws)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := header.Signature
+	if sig == nil {
+		return nil, fmt.Errorf("%w: the cacao_jss header is missing", signature.ErrInvalidJWS)
+	}
+	if header.KeyID != "" && header.KeyID != sig.ID {
+		return nil, fmt.Errorf("%w: the kid header %s does not match the signature id %s", signature.ErrInvalidJWS, header.KeyID, sig.ID)
+	}
+	if header.Algorithm != signature.JOSEAlgorithm(sig.Algorithm) {
+		return nil, fmt.Errorf("%w: the alg header %s does not match the algorithm %s", signature.ErrInvalidJWS, header.Algorithm, sig.Algorithm)
+	}
+	if sig.Value != "" || sig.Signature != nil {
+		return nil, fmt.Errorf("%w: the signature object in the header must not have a value or countersignatures", signature.ErrInvalidJWS)
+	}
+	if p.findSignature(sig.ID) != nil {
+		return nil, fmt.Errorf("the playbook already has a signature with the id %s", sig.ID)
+	}
+
+	sig.Value = jws
+	if err := p.Verify(sig, key); err != nil {
+		return nil, err
+	}
+
+	p.Signatures = append(p.Signatures, *sig)
+	return &p.Signatures[len(p.Signatures)-1], nil
+}
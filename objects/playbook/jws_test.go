@@ -0,0 +1,127 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// TestJWS - This will test exporting signatures as detached JWS and importing
+// them again
+func TestJWS(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	p := newSpecExamplePlaybook()
+	s1 := signature.New()
+	s1.Signee = "ACME Cyber Company"
+	s1.SetPublicKey(ecKey.Public())
+	if err := p.Sign("ES256", ecKey, s1); err != nil {
+		t.Fatalf("1.1 unable to sign playbook: %s", err)
+	}
+	s2 := signature.New()
+	s2.Signee = "ACME Security Company"
+	s2.SetPublicKey(edKey.Public())
+	if err := p.Sign("Ed25519", edKey, s2); err != nil {
+		t.Fatalf("1.2 unable to sign playbook: %s", err)
+	}
+
+	jws1, err := p.ExportJWS(s1.ID, ecKey)
+	if err != nil {
+		t.Fatalf("1.3 unable to export JWS: %s", err)
+	}
+	jws2, err := p.ExportJWS(s2.ID, edKey)
+	if err != nil {
+		t.Fatalf("1.4 unable to export JWS: %s", err)
+	}
+	if !strings.Contains(jws1, "..") || !signature.IsDetachedJWS(jws2) {
+		t.Errorf("1.5 the JWS does not have a detached payload %s", jws1)
+	}
+	if _, err := p.ExportJWS(s1.ID, otherKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("1.6 ExportJWS with a different key returned %v", err)
+	}
+
+	// Verify the JWS the way generic JOSE tools do
+	payload, _ := p.JWSPayload(s1.ID)
+	parts := strings.Split(jws1, ".")
+	if err := jwt.SigningMethodES256.Verify(parts[0]+"."+jwt.EncodeSegment(payload), parts[2], &ecKey.PublicKey); err != nil {
+		t.Errorf("1.7 the JWS did not verify with the detached payload: %s", err)
+	}
+	header, err := signature.ParseDetachedJWS(jws2)
+	if err != nil || header.Algorithm != "EdDSA" || header.KeyID != s2.ID {
+		t.Errorf("1.8 the JWS header is not correct %+v %v", header, err)
+	}
+
+	// Import the JWS into a copy of the playbook without the signatures
+	data, _ := p.Encode()
+	p2, _ := Decode(data)
+	p2.Signatures = nil
+	if _, err := p2.ImportJWS(jws1, &otherKey.PublicKey); !errors.Is(err, ErrInvalidSignature) || len(p2.Signatures) != 0 {
+		t.Errorf("1.9 ImportJWS with a different key returned %v", err)
+	}
+	imported, err := p2.ImportJWS(jws1, &ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("1.10 unable to import JWS: %s", err)
+	}
+	if imported.ID != s1.ID || imported.Signee != s1.Signee || imported.Value != jws1 {
+		t.Errorf("1.11 the imported signature is not correct %+v", imported)
+	}
+	if _, err := p2.ImportJWS(jws2, edKey.Public()); err != nil {
+		t.Fatalf("1.12 unable to import JWS: %s", err)
+	}
+	if err := p2.Verify(&p2.Signatures[0], &ecKey.PublicKey); err != nil {
+		t.Errorf("1.13 the imported JWS did not verify: %s", err)
+	}
+	if err := p2.Verify(&p2.Signatures[1], edKey.Public()); err != nil {
+		t.Errorf("1.14 the imported JWS did not verify: %s", err)
+	}
+	if valid, count, details := p2.Valid(false); !valid {
+		t.Errorf("1.15 the playbook with imported signatures is not valid, errors %d and results %s", count, details)
+	}
+
+	// An imported signature is exported as is
+	if jws, err := p2.ExportJWS(s1.ID, nil); err != nil || jws != jws1 {
+		t.Errorf("1.16 ExportJWS did not return the imported JWS: %v", err)
+	}
+
+	if _, err := p2.ImportJWS(jws1, &ecKey.PublicKey); err == nil {
+		t.Errorf("1.17 ImportJWS accepted a signature that is already attached")
+	}
+	if _, err := p2.ImportJWS(parts[0]+"."+jwt.EncodeSegment(payload)+"."+parts[2], &ecKey.PublicKey); !errors.Is(err, signature.ErrInvalidJWS) {
+		t.Errorf("1.18 ImportJWS accepted a JWS with an attached payload, returned %v", err)
+	}
+
+	// A JWS made by a JOSE tool over the JCS payload is accepted
+	p3, _ := Decode(data)
+	p3.Signatures = nil
+	s3 := signature.New()
+	s3.Signee = "ACME Cyber Company"
+	s3.Algorithm = "ES256"
+	s3.SetPublicKey(ecKey.Public())
+	joseHeader, _ := json.Marshal(signature.JWSHeader{Algorithm: "ES256", KeyID: s3.ID, Signature: s3})
+	josePayload, _ := p3.signingPayload(*s3)
+	protected := jwt.EncodeSegment(joseHeader)
+	joseValue, _ := jwt.SigningMethodES256.Sign(protected+"."+jwt.EncodeSegment(josePayload), ecKey)
+	if _, err := p3.ImportJWS(protected+".."+joseValue, &ecKey.PublicKey); err != nil {
+		t.Errorf("1.19 ImportJWS did not accept a JWS made by a JOSE tool: %s", err)
+	}
+
+	p2.Name = "Something else"
+	if err := p2.Verify(&p2.Signatures[0], &ecKey.PublicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("1.20 Verify did not detect tampered content, returned %v", err)
+	}
+}
@@ -17,6 +17,8 @@ package playbook
 import (
 	"github.com/openplaybooks/libcacao/objects"
 	"github.com/openplaybooks/libcacao/objects/agents"
+	"github.com/openplaybooks/libcacao/objects/authinfo"
+	"github.com/openplaybooks/libcacao/objects/extensions"
 	"github.com/openplaybooks/libcacao/objects/markings"
 	"github.com/openplaybooks/libcacao/objects/signature"
 	"github.com/openplaybooks/libcacao/objects/workflow"
@@ -29,40 +31,40 @@ import (
 // Playbook - This type implements the CACAO 2.0 Playbook object and defines all
 // of the properties and methods needed to create and work with this object.
 type Playbook struct {
-	ObjectType                string                         `json:"type,omitempty"`
-	SpecVersion               string                         `json:"spec_version,omitempty"`
-	ID                        string                         `json:"id,omitempty"`
-	Name                      string                         `json:"name,omitempty"`
-	Description               string                         `json:"description,omitempty"`
-	PlaybookTypes             []string                       `json:"playbook_types,omitempty"`
-	PlaybookActivities        []string                       `json:"playbook_activities,omitempty"`
-	PlaybookProcessingSummary *ProcessingSummary             `json:"playbook_processing_summary,omitempty"`
-	CreatedBy                 string                         `json:"created_by,omitempty"`
-	Created                   string                         `json:"created,omitempty"`
-	Modified                  string                         `json:"modified,omitempty"`
-	Revoked                   bool                           `json:"revoked,omitempty"`
-	ValidFrom                 string                         `json:"valid_from,omitempty"`
-	ValidUntil                string                         `json:"valid_until,omitempty"`
-	DerivedFrom               []string                       `json:"derived_from,omitempty"`
-	RelatedTo                 []string                       `json:"related_to,omitempty"`
-	Priority                  int                            `json:"priority,omitempty"`
-	Severity                  int                            `json:"severity,omitempty"`
-	Impact                    int                            `json:"impact,omitempty"`
-	IndustrySectors           []string                       `json:"industry_sectors,omitempty"`
-	Labels                    []string                       `json:"labels,omitempty"`
-	ExternalReferences        []objects.ExternalReference    `json:"external_references,omitempty"`
-	Markings                  []string                       `json:"markings,omitempty"`
-	PlaybookVariables         map[string]objects.Variables   `json:"playbook_variables,omitempty"`
-	WorkflowStart             string                         `json:"workflow_start,omitempty"`
-	WorkflowException         string                         `json:"workflow_exception,omitempty"`
-	Workflow                  map[string]workflow.StepObject `json:"workflow,omitempty"`
-	//PlaybookExtensions            map[string]????                     `json:"playbook_extensions,omitempty"`
-	//AuthenticationInfoDefinitions map[string]????                     `json:"authentication_info_definitions,omitempty"`
-	AgentDefinitions  map[string]agents.AgentObject `json:"agent_definitions,omitempty"`
-	TargetDefinitions map[string]agents.AgentObject `json:"target_definitions,omitempty"`
-	//ExtensionDefinitions          map[string]????                     `json:extension_definitions,omitempty"`
-	DataMarkingDefinitions map[string]markings.DataMarkingObject `json:"data_marking_definitions,omitempty"`
-	Signatures             []signature.Signature                 `json:"signatures,omitempty"`
+	ObjectType                    string                                    `json:"type,omitempty"`
+	SpecVersion                   string                                    `json:"spec_version,omitempty"`
+	ID                            string                                    `json:"id,omitempty"`
+	Name                          string                                    `json:"name,omitempty"`
+	Description                   string                                    `json:"description,omitempty"`
+	PlaybookTypes                 []string                                  `json:"playbook_types,omitempty"`
+	PlaybookActivities            []string                                  `json:"playbook_activities,omitempty"`
+	PlaybookProcessingSummary     *ProcessingSummary                        `json:"playbook_processing_summary,omitempty"`
+	CreatedBy                     string                                    `json:"created_by,omitempty"`
+	Created                       string                                    `json:"created,omitempty"`
+	Modified                      string                                    `json:"modified,omitempty"`
+	Revoked                       bool                                      `json:"revoked,omitempty"`
+	ValidFrom                     string                                    `json:"valid_from,omitempty"`
+	ValidUntil                    string                                    `json:"valid_until,omitempty"`
+	DerivedFrom                   []string                                  `json:"derived_from,omitempty"`
+	RelatedTo                     []string                                  `json:"related_to,omitempty"`
+	Priority                      int                                       `json:"priority,omitempty"`
+	Severity                      int                                       `json:"severity,omitempty"`
+	Impact                        int                                       `json:"impact,omitempty"`
+	IndustrySectors               []string                                  `json:"industry_sectors,omitempty"`
+	Labels                        []string                                  `json:"labels,omitempty"`
+	ExternalReferences            []objects.ExternalReference               `json:"external_references,omitempty"`
+	Markings                      []string                                  `json:"markings,omitempty"`
+	PlaybookVariables             map[string]objects.Variables              `json:"playbook_variables,omitempty"`
+	WorkflowStart                 string                                    `json:"workflow_start,omitempty"`
+	WorkflowException             string                                    `json:"workflow_exception,omitempty"`
+	Workflow                      map[string]workflow.StepObject            `json:"workflow,omitempty"`
+	PlaybookExtensions            extensions.Extensions                     `json:"playbook_extensions,omitempty"`
+	AuthenticationInfoDefinitions map[string]authinfo.AuthInfoObject        `json:"authentication_info_definitions,omitempty"`
+	AgentDefinitions              map[string]agents.AgentObject             `json:"agent_definitions,omitempty"`
+	TargetDefinitions             map[string]agents.AgentObject             `json:"target_definitions,omitempty"`
+	ExtensionDefinitions          map[string]extensions.ExtensionDefinition `json:"extension_definitions,omitempty"`
+	DataMarkingDefinitions        map[string]markings.DataMarkingObject     `json:"data_marking_definitions,omitempty"`
+	Signatures                    []signature.Signature                     `json:"signatures,omitempty"`
 }
 
 // ProcessingSummary - This type defines a list of playbook processing features
@@ -78,13 +80,6 @@ type ProcessingSummary struct {
 	Extensions         bool `json:"extensions,omitempty"`
 }
 
-// This type is used to capture results from the Valid() and Compare() functions
-type results struct {
-	debug         bool
-	problemsFound int
-	resultDetails []string
-}
-
 // ----------------------------------------------------------------------
 // Initialization Functions
 // ----------------------------------------------------------------------
@@ -0,0 +1,239 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// ErrPolicyNotMet - This error is returned by LoadTrusted when the playbook
+// does not meet the signature policy.
+var ErrPolicyNotMet = errors.New("the playbook does not meet the signature policy")
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// Policy - This type defines the signatures a playbook must have before it can
+// be trusted. At least Threshold of the trusted signees must each have a
+// signature on the playbook that verifies with their key, is not revoked, is
+// within its valid_from and valid_until window at the time of evaluation, and
+// is bound to the playbook by related_to and related_version when they are
+// present. If RequireBinding is true they must be present. If the time is
+// zero the current time is used.
+type Policy struct {
+	Threshold      int
+	Signees        map[string]crypto.PublicKey
+	RequireBinding bool
+	Time           time.Time
+}
+
+// PolicyReport - This type is the result of evaluating a policy. Passed is true
+// if enough trusted signees accepted the playbook. Accepted lists the trusted
+// signees that have a valid signature.
+type PolicyReport struct {
+	Passed    bool
+	Threshold int
+	Accepted  []string
+	Results   []PolicyResult
+}
+
+// PolicyResult - This type is the result of evaluating one signature. The
+// findings list every check that was done, using rule codes that start with
+// "policy." and JSON Pointers to the signature.
+type PolicyResult struct {
+	SignatureID string
+	Signee      string
+	Accepted    bool
+	Findings    []objects.Finding
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewPolicy - This function will create a new policy that requires threshold
+// of the trusted signees to sign the playbook.
+func NewPolicy(threshold int) *Policy {
+	return &Policy{Threshold: threshold, Signees: make(map[string]crypto.PublicKey)}
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// LoadTrusted - This function will decode a playbook and only return it if it
+// meets the signature policy. The report is always returned so the caller can
+// see why a playbook was rejected.
+func LoadTrusted(data []byte, pol *Policy) (*Playbook, *PolicyReport, error) {
+	p, err := Decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := pol.Evaluate(p)
+	if !report.Passed {
+		return nil, report, fmt.Errorf("%w: %s", ErrPolicyNotMet, report.Err())
+	}
+	return p, report, nil
+}
+
+// ----------------------------------------------------------------------
+// Public Policy Methods
+// ----------------------------------------------------------------------
+
+// AddSignee - This method will add a trusted signee and the public key that
+// its signatures must verify with.
+func (pol *Policy) AddSignee(name string, key crypto.PublicKey) {
+	if pol.Signees == nil {
+		pol.Signees = make(map[string]crypto.PublicKey)
+	}
+	pol.Signees[name] = key
+}
+
+// Evaluate - This method will evaluate the signatures on the playbook against
+// the policy. Each trusted signee is only counted once, no matter how many
+// valid signatures it has.
+func (pol *Policy) Evaluate(p *Playbook) *PolicyReport {
+	report := &PolicyReport{Threshold: pol.Threshold}
+	if report.Threshold < 1 {
+		report.Threshold = 1
+	}
+
+	at := pol.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	accepted := make(map[string]bool)
+	for i := range p.Signatures {
+		result := pol.evaluateSignature(p, i, at)
+		if result.Accepted {
+			accepted[result.Signee] = true
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	for signee := range accepted {
+		report.Accepted = append(report.Accepted, signee)
+	}
+	sort.Strings(report.Accepted)
+	report.Passed = len(report.Accepted) >= report.Threshold
+	return report
+}
+
+// ----------------------------------------------------------------------
+// Public PolicyReport Methods
+// ----------------------------------------------------------------------
+
+// Err - This method returns nil if the policy passed, otherwise an error that
+// says how many trusted signees accepted the playbook.
+func (r *PolicyReport) Err() error {
+	if r.Passed {
+		return nil
+	}
+	return fmt.Errorf("the playbook signature policy requires %d trusted signees, but %d accepted it", r.Threshold, len(r.Accepted))
+}
+
+// Findings - This method returns the findings for every signature in order.
+func (r *PolicyReport) Findings() []objects.Finding {
+	var findings []objects.Finding
+	for _, result := range r.Results {
+		findings = append(findings, result.Findings...)
+	}
+	return findings
+}
+
+// ----------------------------------------------------------------------
+// Private Policy Methods
+// ----------------------------------------------------------------------
+
+// evaluateSignature - This method runs every check on one signature.
+func (pol *Policy) evaluateSignature(p *Playbook, index int, at time.Time) PolicyResult {
+	sig := &p.Signatures[index]
+	result := PolicyResult{SignatureID: sig.ID, Signee: sig.Signee}
+	idx := strconv.Itoa(index)
+
+	check := func(rule string, err error, passed string, property ...string) {
+		pointer := objects.JSONPointer(append([]string{"signatures", idx}, property...)...)
+		if err != nil {
+			result.Findings = append(result.Findings, objects.Finding{
+				Severity: objects.SeverityError,
+				Rule:     rule,
+				Pointer:  pointer,
+				Message:  fmt.Sprintf("signature %s: %s", sig.ID, err),
+			})
+			return
+		}
+		result.Findings = append(result.Findings, objects.Finding{
+			Severity: objects.SeverityInfo,
+			Rule:     rule,
+			Pointer:  pointer,
+			Message:  fmt.Sprintf("signature %s: %s", sig.ID, passed),
+		})
+	}
+
+	// Trusted signee and key
+	key, trusted := pol.Signees[sig.Signee]
+	if !trusted {
+		check("policy.trusted_signee", fmt.Errorf("the signee %q is not trusted", sig.Signee), "", "signee")
+	} else {
+		check("policy.trusted_signee", nil, fmt.Sprintf("the signee %q is trusted", sig.Signee), "signee")
+		check("policy.signature", p.Verify(sig, key), "the signature value is valid for the trusted key", "value")
+	}
+
+	// Status
+	var revoked error
+	if sig.Revoked {
+		revoked = signature.ErrRevoked
+	}
+	check("policy.revoked", revoked, "the signature is not revoked", "revoked")
+
+	window := signature.Signature{ValidFrom: sig.ValidFrom, ValidUntil: sig.ValidUntil}
+	check("policy.validity", window.CheckStatus(at), "the signature is within its validity window", "valid_from")
+
+	// Binding to this version of the playbook
+	passed, err := checkBinding(sig.RelatedTo, p.ID, "related_to", "id", pol.RequireBinding)
+	check("policy.related_to", err, passed, "related_to")
+	passed, err = checkBinding(sig.RelatedVersion, p.Modified, "related_version", "modified", pol.RequireBinding)
+	check("policy.related_version", err, passed, "related_version")
+
+	result.Accepted = true
+	for _, f := range result.Findings {
+		if f.IsProblem() {
+			result.Accepted = false
+		}
+	}
+	return result
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// checkBinding - This function checks that a binding property of a signature
+// matches the playbook property it refers to. It returns the message for a
+// passed check or an error.
+func checkBinding(value, want, property, playbookProperty string, required bool) (string, error) {
+	if value == "" {
+		if required {
+			return "", fmt.Errorf("the %s property is required", property)
+		}
+		return fmt.Sprintf("the %s property is not used", property), nil
+	}
+	if value != want {
+		return "", fmt.Errorf("the %s property %q does not match the playbook %s %q", property, value, playbookProperty, want)
+	}
+	return fmt.Sprintf("the %s property matches the playbook %s", property, playbookProperty), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// TestPolicy - This will test evaluating m-of-n signature policies
+func TestPolicy(t *testing.T) {
+	_, alice, _ := ed25519.GenerateKey(rand.Reader)
+	_, bob, _ := ed25519.GenerateKey(rand.Reader)
+	_, carol, _ := ed25519.GenerateKey(rand.Reader)
+	_, mallory, _ := ed25519.GenerateKey(rand.Reader)
+
+	pol := NewPolicy(2)
+	pol.AddSignee("Alice", alice.Public())
+	pol.AddSignee("Bob", bob.Public())
+	pol.AddSignee("Carol", carol.Public())
+
+	type signer struct {
+		name   string
+		key    ed25519.PrivateKey
+		modify func(p *Playbook, s *signature.Signature)
+	}
+	build := func(signers ...signer) *Playbook {
+		p := newSpecExamplePlaybook()
+		for _, sg := range signers {
+			s := signature.New()
+			s.Signee = sg.name
+			s.RelatedTo = p.ID
+			s.RelatedVersion = p.Modified
+			if sg.modify != nil {
+				sg.modify(p, s)
+			}
+			if err := p.Sign("Ed25519", sg.key, s); err != nil {
+				t.Fatalf("unable to sign: %s", err)
+			}
+		}
+		return p
+	}
+
+	revoked := func(p *Playbook, s *signature.Signature) { s.Revoked = true }
+	expired := func(p *Playbook, s *signature.Signature) { s.ValidUntil = "2020-01-01T00:00:00Z" }
+	future := func(p *Playbook, s *signature.Signature) {
+		s.ValidFrom = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	}
+	otherVersion := func(p *Playbook, s *signature.Signature) { s.RelatedVersion = "2020-01-01T00:00:00Z" }
+	unbound := func(p *Playbook, s *signature.Signature) { s.RelatedTo, s.RelatedVersion = "", "" }
+
+	tests := []struct {
+		name     string
+		p        *Playbook
+		passed   bool
+		accepted int
+	}{
+		{"two of three", build(signer{"Alice", alice, nil}, signer{"Bob", bob, nil}), true, 2},
+		{"all three", build(signer{"Alice", alice, nil}, signer{"Bob", bob, nil}, signer{"Carol", carol, nil}), true, 3},
+		{"one signee twice", build(signer{"Alice", alice, nil}, signer{"Alice", alice, nil}), false, 1},
+		{"untrusted signee", build(signer{"Alice", alice, nil}, signer{"Mallory", mallory, nil}), false, 1},
+		{"wrong key for signee", build(signer{"Alice", alice, nil}, signer{"Bob", mallory, nil}), false, 1},
+		{"revoked", build(signer{"Alice", alice, nil}, signer{"Bob", bob, revoked}), false, 1},
+		{"expired", build(signer{"Alice", alice, nil}, signer{"Bob", bob, expired}), false, 1},
+		{"not yet valid", build(signer{"Alice", alice, nil}, signer{"Bob", bob, future}), false, 1},
+		{"other version", build(signer{"Alice", alice, nil}, signer{"Bob", bob, otherVersion}), false, 1},
+		{"unbound is allowed", build(signer{"Alice", alice, nil}, signer{"Bob", bob, unbound}), true, 2},
+	}
+
+	for i, test := range tests {
+		report := pol.Evaluate(test.p)
+		if report.Passed != test.passed || len(report.Accepted) != test.accepted {
+			t.Errorf("1.%d %s: passed %t with %d accepted, expected %t with %d", i, test.name, report.Passed, len(report.Accepted), test.passed, test.accepted)
+		}
+		if (report.Err() == nil) != test.passed {
+			t.Errorf("1.%d %s: Err() does not match the result", i, test.name)
+		}
+	}
+
+	// The findings say why each signature was rejected
+	report := pol.Evaluate(build(signer{"Alice", alice, nil}, signer{"Bob", bob, revoked}))
+	found := false
+	for _, f := range report.Results[1].Findings {
+		if f.IsProblem() && f.Rule == "policy.revoked" && f.Pointer == "/signatures/1/revoked" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("2.0 the report does not contain the revoked finding %+v", report.Results[1].Findings)
+	}
+
+	// Binding can be required
+	pol.RequireBinding = true
+	if pol.Evaluate(build(signer{"Alice", alice, nil}, signer{"Bob", bob, unbound})).Passed {
+		t.Errorf("2.1 an unbound signature was accepted when binding is required")
+	}
+
+	// A playbook changed after signing is rejected when it is loaded
+	p := build(signer{"Alice", alice, nil}, signer{"Bob", bob, nil})
+	p.Name = "A changed name"
+	data, _ := p.Encode()
+	if _, report, err := LoadTrusted(data, pol); !errors.Is(err, ErrPolicyNotMet) || report == nil {
+		t.Errorf("2.2 LoadTrusted accepted a changed playbook")
+	}
+	p.Name = newSpecExamplePlaybook().Name
+	data, _ = p.Encode()
+	if _, _, err := LoadTrusted(data, pol); err != nil {
+		t.Errorf("2.3 LoadTrusted rejected a trusted playbook: %s", err)
+	}
+}
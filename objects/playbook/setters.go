@@ -7,11 +7,12 @@ package playbook
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/openplaybooks/libcacao/objects"
 	"github.com/openplaybooks/libcacao/objects/agents"
+	"github.com/openplaybooks/libcacao/objects/authinfo"
+	"github.com/openplaybooks/libcacao/objects/extensions"
 	"github.com/openplaybooks/libcacao/objects/markings"
 	"github.com/openplaybooks/libcacao/objects/workflow"
 )
@@ -151,10 +152,11 @@ func (p *Playbook) NewExternalReference(r ...objects.ExternalReference) (*object
 }
 
 // AddVariable - This method takes in a Variable object and adds it to the
-// playbook object as a global playbook variable.
+// playbook object as a global playbook variable. The variable is refused if its
+// value does not match its type.
 func (p *Playbook) AddVariable(v objects.Variables) error {
-	if !objects.IsVocabValueValid(v.ObjectType, objects.GetVariableTypesVocab()) {
-		return fmt.Errorf("the variable type %s is not valid", v.ObjectType)
+	if err := v.Validate(); err != nil {
+		return err
 	}
 
 	if p.PlaybookVariables == nil {
@@ -177,6 +179,8 @@ func (p *Playbook) AddMarkingDefinition(v markings.DataMarkingObject) error {
 		p.DataMarkingDefinitions = m
 	}
 	p.DataMarkingDefinitions[k] = v
+
+	p.updateExtensionsFeature()
 	return nil
 }
 
@@ -211,6 +215,8 @@ func (p *Playbook) AddWorkflowStep(v workflow.StepObject) error {
 
 	}
 
+	p.updateExtensionsFeature()
+
 	return nil
 }
 
@@ -255,3 +261,85 @@ func (p *Playbook) AddTarget(v agents.AgentObject) error {
 
 	return nil
 }
+
+// AddAuthenticationInfo - This method takes in an interface represening an
+// authentication information object that satisfies the
+// authinfo.AuthInfoObject interface and adds it to the map.
+func (p *Playbook) AddAuthenticationInfo(v authinfo.AuthInfoObject) error {
+	k := v.GetCommon().ID
+	if p.AuthenticationInfoDefinitions == nil {
+		m := make(map[string]authinfo.AuthInfoObject, 0)
+		p.AuthenticationInfoDefinitions = m
+	}
+	p.AuthenticationInfoDefinitions[k] = v
+	// After we add it to the playbook lets clear out the embedded ID
+	v.ClearID()
+
+	return nil
+}
+
+// AddExtensionDefinition - This method takes in an extension definition object
+// and adds it to the extension_definitions property.
+func (p *Playbook) AddExtensionDefinition(v *extensions.ExtensionDefinition) error {
+	k := v.GetID()
+	if p.ExtensionDefinitions == nil {
+		m := make(map[string]extensions.ExtensionDefinition, 0)
+		p.ExtensionDefinitions = m
+	}
+	p.ExtensionDefinitions[k] = *v
+
+	p.setExtensionsFeature()
+	return nil
+}
+
+// AddExtension - This method takes in the ID of an extension definition and a
+// value that represents the extension and adds it to the playbook_extensions
+// property.
+func (p *Playbook) AddExtension(id string, v interface{}) error {
+	if err := p.PlaybookExtensions.Add(id, v); err != nil {
+		return err
+	}
+
+	p.setExtensionsFeature()
+	return nil
+}
+
+// setExtensionsFeature - This method will record in the processing summary
+// that the playbook uses extensions.
+func (p *Playbook) setExtensionsFeature() {
+	if p.PlaybookProcessingSummary == nil {
+		var ps ProcessingSummary
+		p.PlaybookProcessingSummary = &ps
+	}
+	p.PlaybookProcessingSummary.Extensions = true
+}
+
+// updateExtensionsFeature - This method will record in the processing summary
+// that the playbook uses extensions if the playbook, any workflow step, or any
+// data marking has an extensions property. Extensions can be added to a step
+// or a marking after it has been added to the playbook, so this is also done
+// when the playbook is decoded and when it is signed.
+func (p *Playbook) updateExtensionsFeature() {
+	if p.usesExtensions() {
+		p.setExtensionsFeature()
+	}
+}
+
+// usesExtensions - This method will return true if the playbook, any workflow
+// step, or any data marking has an extensions property.
+func (p *Playbook) usesExtensions() bool {
+	if len(p.PlaybookExtensions) > 0 {
+		return true
+	}
+	for _, v := range p.Workflow {
+		if v != nil && len(v.GetCommon().StepExtensions) > 0 {
+			return true
+		}
+	}
+	for _, v := range p.DataMarkingDefinitions {
+		if v != nil && len(v.GetCommon().MarkingExtensions) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -6,79 +6,84 @@
 package playbook
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"errors"
-	"fmt"
-
-	"github.com/golang-jwt/jwt"
-	"github.com/gowebpki/jcs"
-	"github.com/openplaybooks/libcacao/objects"
 	"github.com/openplaybooks/libcacao/objects/signature"
 )
 
 // Sign - This method will sign a playbook object.
 // It takes in a signing method like "RS256", a key, and a CACAO signature object.
+// The key can be a private key or a signature.Signer, which lets the signature
+// be created by an HSM, a KMS or a signing agent that holds the private key.
+// If the signer has a certificate chain, it is added to the signature object
+// along with the thumbprint of the leaf certificate.
+// If the algorithm property of the signature object is empty it is set to the
+// signing method. An error is returned if the key can not be used with the
+// signing method. The LMS and XMSS methods take an *lms.PrivateKey or an
+// *xmss.PrivateKey, and each signature uses up one of their one-time keys.
 func (p *Playbook) Sign(method string, key interface{}, sig *signature.Signature) error {
-	var err error
-
-	if !objects.IsVocabValueValid(method, signature.GetSigningMethodsVocab()) {
-		return fmt.Errorf("the signing method %s is not valid", method)
-	}
-
-	// Step 2: Save any existing signatures and then zero out the property for signing
-	savedSignatures := p.Signatures
-	p.Signatures = nil
-
-	// Step 3: Add new signature object
-	p.Signatures = append(p.Signatures, *sig)
-	// Convert playbook object to a JSON byte[] so we can run it through JCS
-	pbData, err := p.Encode()
-	if err != nil {
+	if err := sig.PrepareSigning(method, key); err != nil {
 		return err
 	}
 
-	// Step 4: Create JCS version of playbook
-	jcsData, err := jcs.Transform(pbData)
+	// Extensions can be added to a workflow step or a data marking after it
+	// was added to the playbook, so make sure the processing summary records
+	// them before the playbook is signed
+	p.updateExtensionsFeature()
+
+	// Step 2 - 5: Create the hash of the JCS version of the playbook with only
+	// the new signature object in it
+	hash, err := p.signingHash(*sig)
 	if err != nil {
 		return err
 	}
 
-	// Step 5: SHA256 encode JCS version. The Sum256 functions returns a [32]byte
-	hashhex := sha256.Sum256(jcsData)
-	hash := hex.EncodeToString(hashhex[:])
-
 	// Step 6: Digitally sign the hash
-	var signingMethod jwt.SigningMethod
-	// If value is an RSA method the signingMethod will be *jwt.SigningMethodRSA
-	if method == "RS256" {
-		signingMethod = jwt.SigningMethodRS256
-	} else if method == "RS384" {
-		signingMethod = jwt.SigningMethodRS384
-	} else if method == "RS512" {
-		signingMethod = jwt.SigningMethodRS512
-	} else if method == "ES256" {
-		signingMethod = jwt.SigningMethodES256
-	} else if method == "ES384" {
-		signingMethod = jwt.SigningMethodES384
-	} else if method == "ES512" {
-		signingMethod = jwt.SigningMethodES512
-	} else {
-		return errors.New("no valid signing method was given")
-	}
-	sigData, err := signingMethod.Sign(hash, key)
+	sigData, err := signature.SignHash(method, key, hash)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// Step 8
 	// Add signature to signature object
 	sig.Value = sigData
-	// Add original signatures back to the playbook
-	p.Signatures = nil
-	p.Signatures = append(p.Signatures, savedSignatures...)
-	// Add the new signature
+	// Add the new signature after the original signatures
 	p.Signatures = append(p.Signatures, *sig)
 
 	return nil
 }
+
+// SignWith - This method will sign a playbook object with a signer, using the
+// algorithm of the signer as the signing method.
+func (p *Playbook) SignWith(signer signature.Signer, sig *signature.Signature) error {
+	return p.Sign(signer.Algorithm(), signer, sig)
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// signingHash - This method will build the form of the playbook that is
+// signed and return the hex encoded SHA-256 hash of it. The playbook itself is
+// not changed.
+func (p *Playbook) signingHash(sig signature.Signature) (string, error) {
+	pbData, err := p.signingPayload(sig)
+	if err != nil {
+		return "", err
+	}
+
+	return signature.HashJSON(pbData)
+}
+
+// signingPayload - This method will build the JCS version of the form of the
+// playbook that is signed. The signed form is the playbook with all other
+// signatures removed and only the signature object being created or verified,
+// without its value, in the signatures property. Countersignatures on the
+// signature object are removed, since they are added after the playbook is
+// signed. The playbook itself is not changed.
+func (p *Playbook) signingPayload(sig signature.Signature) ([]byte, error) {
+	sig.Value = ""
+	sig.Signature = nil
+	signed := *p
+	signed.Signatures = []signature.Signature{sig}
+
+	return signed.Canonical()
+}
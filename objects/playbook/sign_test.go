@@ -6,14 +6,30 @@
 package playbook
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/pem"
+	"errors"
 	"log"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/openplaybooks/libcacao/objects/signature"
+	"github.com/openplaybooks/libcacao/objects/signature/ed448"
+	"github.com/openplaybooks/libcacao/objects/signature/lms"
+	"github.com/openplaybooks/libcacao/objects/signature/signertest"
+	"github.com/openplaybooks/libcacao/objects/signature/state"
+	"github.com/openplaybooks/libcacao/objects/signature/xmss"
 )
 
 // TestSign - This will test the Sign() method
@@ -132,3 +148,308 @@ fD1JKiHl7MECYEMyBz31PsRCuw==
 		t.Errorf("1.3 public keys were not added to the signature correctly")
 	}
 }
+
+// TestSignAlgorithms - This will test signing and verifying with each of the
+// classical algorithms in the signing methods vocabulary
+func TestSignAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	_, ed448Key, _ := ed448.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		method string
+		key    crypto.Signer
+	}{
+		{"RS256", rsaKey},
+		{"RS384", rsaKey},
+		{"RS512", rsaKey},
+		{"ES256", p256Key},
+		{"ES384", p384Key},
+		{"ES512", p521Key},
+		{"PS256", rsaKey},
+		{"PS384", rsaKey},
+		{"PS512", rsaKey},
+		{"Ed25519", ed25519Key},
+		{"Ed448", ed448Key},
+	}
+
+	for i, test := range tests {
+		p := newSpecExamplePlaybook()
+		s := signature.New()
+		s.Signee = "ACME Cyber Company"
+		s.SetPublicKey(test.key.Public())
+
+		if err := p.Sign(test.method, test.key, s); err != nil {
+			t.Errorf("2.%d unable to sign with %s: %s", i, test.method, err)
+			continue
+		}
+
+		data, _ := p.Encode()
+		p2, _ := Decode(data)
+		key, err := p2.Signatures[0].ParsePublicKey()
+		if err != nil {
+			t.Errorf("2.%d unable to parse %s public key: %s", i, test.method, err)
+			continue
+		}
+		if err := p2.Verify(&p2.Signatures[0], key); err != nil {
+			t.Errorf("2.%d unable to verify %s signature: %s", i, test.method, err)
+		}
+	}
+}
+
+// TestSignHashBased - This will test signing and verifying with the stateful
+// LMS and XMSS algorithms and that each signature advances the key state
+func TestSignHashBased(t *testing.T) {
+	lmsKey, err := lms.GenerateKey(rand.Reader, lms.TypeSHA256M32H5, lms.OTSTypeSHA256N32W4, &state.MemoryStore{})
+	if err != nil {
+		t.Fatalf("unable to generate LMS key: %s", err)
+	}
+	xmssKey, err := xmss.GenerateKey(rand.Reader, xmss.SHA2_10_256, &state.MemoryStore{})
+	if err != nil {
+		t.Fatalf("unable to generate XMSS key: %s", err)
+	}
+
+	tests := []struct {
+		method    string
+		key       crypto.Signer
+		remaining func() uint64
+	}{
+		{"LMS_SHA256_M32_H5", lmsKey, lmsKey.Remaining},
+		{"XMSS-SHA2_10_256", xmssKey, xmssKey.Remaining},
+	}
+
+	for i, test := range tests {
+		before := test.remaining()
+		p := newSpecExamplePlaybook()
+		s := signature.New()
+		s.Signee = "ACME Cyber Company"
+		s.SetPublicKey(test.key.Public())
+
+		if err := p.Sign(test.method, test.key, s); err != nil {
+			t.Errorf("4.%d unable to sign with %s: %s", i, test.method, err)
+			continue
+		}
+		if test.remaining() != before-1 {
+			t.Errorf("4.%d signing with %s did not advance the key state", i, test.method)
+		}
+
+		data, _ := p.Encode()
+		p2, _ := Decode(data)
+		key, err := p2.Signatures[0].ParsePublicKey()
+		if err != nil {
+			t.Errorf("4.%d unable to parse %s public key: %s", i, test.method, err)
+			continue
+		}
+		if err := p2.Verify(&p2.Signatures[0], key); err != nil {
+			t.Errorf("4.%d unable to verify %s signature: %s", i, test.method, err)
+		}
+
+		p2.Name = "A changed name"
+		if err := p2.Verify(&p2.Signatures[0], key); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("4.%d %s signature on a changed playbook returned %v", i, test.method, err)
+		}
+	}
+
+	p := newSpecExamplePlaybook()
+	if err := p.Sign("LMS_SHA256_M32_H10", lmsKey, signature.New()); !errors.Is(err, ErrAlgorithmMismatch) {
+		t.Errorf("4.2 Sign with a key for a different LMS parameter set returned %v", err)
+	}
+}
+
+// TestSignKeyMismatch - This will test that signing with a key that does not
+// match the signing method returns an error instead of panicking
+func TestSignKeyMismatch(t *testing.T) {
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		method string
+		key    interface{}
+	}{
+		{"RS256", ed25519Key},
+		{"PS256", p256Key},
+		{"ES384", p256Key},
+		{"Ed448", ed25519Key},
+		{"Ed25519", "not a key"},
+	}
+
+	for i, test := range tests {
+		p := newSpecExamplePlaybook()
+		err := p.Sign(test.method, test.key, signature.New())
+		if !errors.Is(err, ErrAlgorithmMismatch) {
+			t.Errorf("3.%d Sign with %s returned %v instead of a mismatch error", i, test.method, err)
+		}
+		if len(p.Signatures) != 0 {
+			t.Errorf("3.%d Sign added a signature after an error", i)
+		}
+	}
+}
+
+// TestSignWithSigner - This will test signing with in-memory, PEM file and
+// remote signers and verifying with a key ring
+func TestSignWithSigner(t *testing.T) {
+	dir := t.TempDir()
+
+	// PEM file signer and key ring
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keyDer, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	pubDer, _ := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	os.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600)
+	os.WriteFile(filepath.Join(dir, "pub.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0600)
+
+	pemSigner, err := signature.LoadPEMSigner(filepath.Join(dir, "key.pem"), "PS256")
+	if err != nil {
+		t.Fatalf("unable to load PEM signer: %s", err)
+	}
+	ring := signature.NewKeyRing()
+	if err := ring.AddPEMFile("PEM Signee", filepath.Join(dir, "pub.pem")); err != nil {
+		t.Fatalf("unable to load PEM public key: %s", err)
+	}
+
+	// In-memory signers
+	var signers []signature.Signer
+	signers = append(signers, pemSigner)
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	_, ed448Key, _ := ed448.GenerateKey(rand.Reader)
+	lmsKey, _ := lms.GenerateKey(rand.Reader, lms.TypeSHA256M32H5, lms.OTSTypeSHA256N32W8, &state.MemoryStore{})
+	for _, k := range []struct {
+		alg string
+		key crypto.Signer
+	}{
+		{"RS384", rsaKey},
+		{"ES256", p256Key},
+		{"ES512", p521Key},
+		{"Ed25519", ed25519Key},
+		{"Ed448", ed448Key},
+		{"LMS_SHA256_M32_H5", lmsKey},
+	} {
+		s, err := signature.NewKeySigner(k.alg, k.key)
+		if err != nil {
+			t.Fatalf("unable to create %s signer: %s", k.alg, err)
+		}
+		signers = append(signers, s)
+	}
+
+	// Remote signer over a local socket
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	backend, _ := signature.NewKeySigner("ES384", p384Key)
+	server, err := signertest.NewServer(backend)
+	if err != nil {
+		t.Fatalf("unable to start remote signer: %s", err)
+	}
+	defer server.Close()
+	remote, err := signertest.Dial(server.Network, server.Addr)
+	if err != nil {
+		t.Fatalf("unable to connect to remote signer: %s", err)
+	}
+	signers = append(signers, remote)
+
+	p := newSpecExamplePlaybook()
+	for i, signer := range signers {
+		s := signature.New()
+		s.Signee = signer.Algorithm() + " Signee"
+		if signer == pemSigner {
+			s.Signee = "PEM Signee"
+		} else {
+			ring.Add(s.Signee, signer.Public())
+		}
+		if err := p.SignWith(signer, s); err != nil {
+			t.Errorf("5.%d unable to sign with %s signer: %s", i, signer.Algorithm(), err)
+		}
+	}
+	if server.Requests() != 1 {
+		t.Errorf("5.7 the remote signer handled %d requests, expected 1", server.Requests())
+	}
+
+	for i, result := range p.VerifyAll(ring) {
+		if !result.Valid {
+			t.Errorf("5.8.%d %s signature did not verify: %s", i, result.Algorithm, result.Err)
+		}
+	}
+
+	if err := newSpecExamplePlaybook().Sign("RS256", remote, signature.New()); !errors.Is(err, ErrAlgorithmMismatch) {
+		t.Errorf("5.9 signing with a signer for a different algorithm returned %v", err)
+	}
+}
+
+// TestSignWithCertificates - This will test that the certificate chain of a
+// signer is embedded and validated against the trusted roots
+func TestSignWithCertificates(t *testing.T) {
+	rootKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rootTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDer, _ := x509.CreateCertificate(rand.Reader, &rootTemplate, &rootTemplate, rootKey.Public(), rootKey)
+	root, _ := x509.ParseCertificate(rootDer)
+
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	leafTemplate := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "ACME Cyber Company"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leafDer, _ := x509.CreateCertificate(rand.Reader, &leafTemplate, root, leafKey.Public(), rootKey)
+	leaf, _ := x509.ParseCertificate(leafDer)
+
+	signer, _ := signature.NewKeySigner("ES384", leafKey)
+	if err := signer.SetCertificateChain([]*x509.Certificate{leaf}); err != nil {
+		t.Fatalf("unable to set certificate chain: %s", err)
+	}
+
+	p := newSpecExamplePlaybook()
+	if err := p.SignWith(signer, signature.New()); err != nil {
+		t.Fatalf("unable to sign: %s", err)
+	}
+	if len(p.Signatures[0].PublicCertChain) != 1 || p.Signatures[0].Thumbprint != signature.Thumbprint(leaf) {
+		t.Errorf("6.0 the certificate chain and thumbprint were not added to the signature")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	if result := p.VerifyAll(signature.NewCertResolver(roots))[0]; !result.Valid {
+		t.Errorf("6.1 signature with a trusted chain did not verify: %s", result.Err)
+	}
+	if result := p.VerifyAll(signature.NewCertResolver(x509.NewCertPool()))[0]; result.Valid {
+		t.Errorf("6.2 signature with an untrusted chain was verified")
+	}
+
+	// The chain is covered by the signature, so it can not be swapped
+	p.Signatures[0].PublicCertChain = append(p.Signatures[0].PublicCertChain, base64.StdEncoding.EncodeToString(root.Raw))
+	if result := p.VerifyAll(signature.NewCertResolver(roots))[0]; result.Valid {
+		t.Errorf("6.3 signature with a modified chain was verified")
+	}
+
+	// A signature that already has a public key or certificate URL keeps it as
+	// its only key source
+	s1 := signature.New()
+	s1.SetPublicKey(leafKey.Public())
+	s2 := signature.New()
+	s2.CertURL = "https://example.com/certs/acme.pem"
+	for i, sig := range []*signature.Signature{s1, s2} {
+		sig.Signee = "ACME Cyber Company"
+		p = newSpecExamplePlaybook()
+		if err := p.SignWith(signer, sig); err != nil {
+			t.Fatalf("6.%d unable to sign: %s", i+4, err)
+		}
+		if len(p.Signatures[0].PublicCertChain) != 0 || p.Signatures[0].Thumbprint != "" {
+			t.Errorf("6.%d the certificate chain was added next to another key source", i+4)
+		}
+		if valid, count, details := p.Signatures[0].Valid(false); !valid {
+			t.Errorf("6.%d the signature is not valid, errors %d and results %s", i+4, count, details)
+		}
+	}
+}
@@ -6,11 +6,17 @@
 package playbook
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/agents"
+	"github.com/openplaybooks/libcacao/objects/workflow"
 )
 
 // ----------------------------------------------------------------------
@@ -23,10 +29,34 @@ import (
 // enabled, then resultDetails will contain entries for successful checks not
 // just failures.
 func (p *Playbook) Valid(debug bool) (bool, int, []string) {
-	r := new(results)
+	r := p.validate(debug)
+
+	// Return real values not pointers
+	if r.ProblemsFound > 0 {
+		return false, r.ProblemsFound, r.ResultDetails
+	}
+	return true, r.ProblemsFound, r.ResultDetails
+}
+
+// Check - This method will run the same checks as Valid() and return the
+// results as a list of findings. Each finding has a severity, a stable rule
+// code, a JSON Pointer to the property, and a message. If debug is enabled,
+// then the findings will also contain entries for successful checks.
+func (p *Playbook) Check(debug bool) []objects.Finding {
+	r := p.validate(debug)
+	return r.Findings
+}
+
+// ----------------------------------------------------------------------
+// Private Common Functions
+// ----------------------------------------------------------------------
+
+// validate - This method will run each of the checks and return the results.
+func (p *Playbook) validate(debug bool) *objects.Results {
+	r := new(objects.Results)
 
 	// If debug is enabled record successful checks in addition to failures
-	r.debug = debug
+	r.Debug = debug
 
 	// Check each property in the model
 	p.checkObjectType(r)
@@ -50,55 +80,89 @@ func (p *Playbook) Valid(debug bool) (bool, int, []string) {
 	p.checkExternalReferences(r)
 	// Features - No requirements
 	// Markings
-	// Playbook Variables
-	// Workflow Start
-	// Workflow Exception
-	// Workflow
+	p.checkPlaybookVariables(r)
+	p.checkWorkflowStart(r)
+	p.checkWorkflowException(r)
+	p.checkWorkflow(r)
+	p.checkWorkflowSteps(r)
+	p.checkAuthenticationInfoDefinitions(r)
 	// Targets
-	// Extension Definitions
-	// Data Marking Definitions
-	// Signatures
+	p.checkExtensionDefinitions(r)
+	p.checkExtensions(r)
+	p.checkDataMarkingDefinitions(r)
+	p.checkSignatures(r)
 
 	// Finished Checks
-
-	// Return real values not pointers
-	if r.problemsFound > 0 {
-		return false, r.problemsFound, r.resultDetails
-	}
-	return true, r.problemsFound, r.resultDetails
+	return r
 }
 
 // ----------------------------------------------------------------------
-// Private Common Functions
+// Private Functions
 // ----------------------------------------------------------------------
 
-// These functions will handle common logging tasks for the various checks.
-
-func requiredButMissing(r *results, propertyName string) {
-	str := fmt.Sprintf("-- the %s property is required but missing", propertyName)
-	logProblem(r, str)
+// stepReference - This type holds the step identifiers in one property of a
+// workflow step. The property is the name used in messages, switch cases are
+// named cases.<case>, and the tokens are the JSON Pointer reference tokens of
+// the property within the step.
+type stepReference struct {
+	property string
+	tokens   []string
+	ids      []string
 }
 
-func requiredAndFound(r *results, propertyName string) {
-	str := fmt.Sprintf("++ the %s property is required and is found", propertyName)
-	logValid(r, str)
-}
+// getStepReferences - This function will return every step identifier that a
+// workflow step references, grouped by the property that holds the reference
+// and sorted by the name of the property.
+func getStepReferences(step workflow.StepObject) []stepReference {
+	var refs []stepReference
+	add := func(property string, tokens []string, ids ...string) {
+		ref := stepReference{property: property, tokens: tokens}
+		for _, id := range ids {
+			if id != "" {
+				ref.ids = append(ref.ids, id)
+			}
+		}
+		if len(ref.ids) > 0 {
+			refs = append(refs, ref)
+		}
+	}
 
-func logProblem(r *results, msg string) {
-	r.problemsFound++
-	r.resultDetails = append(r.resultDetails, msg)
+	common := step.GetCommon()
+	add("on_completion", []string{"on_completion"}, common.OnCompletion)
+	add("on_success", []string{"on_success"}, common.OnSuccess)
+	add("on_failure", []string{"on_failure"}, common.OnFailure)
+
+	switch s := step.(type) {
+	case *workflow.ParallelStep:
+		add("next_steps", []string{"next_steps"}, s.NextSteps...)
+	case *workflow.IfStep:
+		add("on_true", []string{"on_true"}, s.OnTrue...)
+		add("on_false", []string{"on_false"}, s.OnFalse...)
+	case *workflow.WhileStep:
+		add("on_true", []string{"on_true"}, s.OnTrue...)
+	case *workflow.SwitchStep:
+		for k, v := range s.Cases {
+			add("cases."+k, []string{"cases", k}, v...)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].property < refs[j].property })
+	return refs
 }
 
-func logValid(r *results, msg string) {
-	if r.debug {
-		r.resultDetails = append(r.resultDetails, msg)
+// sortedKeys - This function will return the keys of a map with string keys in
+// sorted order so that the results of a check are always reported in the same
+// order.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
 	}
+	sort.Strings(keys)
+	return keys
 }
 
-// ----------------------------------------------------------------------
-// Private Functions
-// ----------------------------------------------------------------------
-
 // isIDValid - This function will take in an CACAO ID and check to see if it is
 // a valid identifier per the specification for a playbook object.
 func isIDValid(id string) bool {
@@ -143,6 +207,23 @@ func isCreatedByIDValid(id string) bool {
 	return valid
 }
 
+// walkWorkflow - This function will walk the workflow graph from each of the
+// starting steps following the edges and return every step that was visited.
+func walkWorkflow(start []string, edges map[string][]string) map[string]bool {
+	visited := make(map[string]bool)
+	queue := append([]string{}, start...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		queue = append(queue, edges[id]...)
+	}
+	return visited
+}
+
 // ----------------------------------------------------------------------
 // Private Methods
 // ----------------------------------------------------------------------
@@ -151,121 +232,138 @@ func isCreatedByIDValid(id string) bool {
 // to reduce the complexity of the main valid() function. This way all of the
 // checks for each property are self contained in their own function.
 
-func (p *Playbook) checkObjectType(r *results) {
+func (p *Playbook) checkObjectType(r *objects.Results) {
+	r.At("playbook.type", "type")
+
 	if p.ObjectType == "" {
-		requiredButMissing(r, "type")
+		r.RequiredButMissing("type")
 		return
 	}
 
-	requiredAndFound(r, "type")
+	r.RequiredAndFound("type")
 	if p.ObjectType != "playbook" {
-		logProblem(r, "-- the type property does not contain a value of playbook")
+		r.LogProblem("-- the type property does not contain a value of playbook")
 	} else {
 		str := fmt.Sprintf("++ the type property contains a valid type value of \"%s\"", p.ObjectType)
-		logValid(r, str)
+		r.LogValid(str)
 	}
 }
 
-func (p *Playbook) checkSpecVersion(r *results) {
+func (p *Playbook) checkSpecVersion(r *objects.Results) {
+	r.At("playbook.spec_version", "spec_version")
+
 	if p.SpecVersion == "" {
-		requiredButMissing(r, "spec_version")
+		r.RequiredButMissing("spec_version")
 		return
 	}
 
-	requiredAndFound(r, "spec_version")
-	if p.SpecVersion != "2.0" {
-		logProblem(r, "-- the spec_version property does not contain a value of 2.0")
+	r.RequiredAndFound("spec_version")
+	if p.SpecVersion != objects.GetCurrentSpecVersion() {
+		str := fmt.Sprintf("-- the spec_version property does not contain a value of %s", objects.GetCurrentSpecVersion())
+		r.LogProblem(str)
 	} else {
 		str := fmt.Sprintf("++ the spec_version property contains a valid spec_version value of \"%s\"", p.SpecVersion)
-		logValid(r, str)
+		r.LogValid(str)
 	}
 }
 
-func (p *Playbook) checkID(r *results) {
+func (p *Playbook) checkID(r *objects.Results) {
+	r.At("playbook.id", "id")
+
 	if p.ID == "" {
-		requiredButMissing(r, "id")
+		r.RequiredButMissing("id")
 		return
 	}
 
-	requiredAndFound(r, "id")
+	r.RequiredAndFound("id")
 	if valid := isIDValid(p.ID); valid == false {
-		logProblem(r, "-- the id property does not contain a valid identifier")
+		r.LogProblem("-- the id property does not contain a valid identifier")
 	} else {
 		str := fmt.Sprintf("++ the id property contains a valid identifier value of \"%s\"", p.ID)
-		logValid(r, str)
+		r.LogValid(str)
 	}
 }
 
-func (p *Playbook) checkName(r *results) {
+func (p *Playbook) checkName(r *objects.Results) {
+	r.At("playbook.name", "name")
+
 	if p.Name == "" {
-		requiredButMissing(r, "name")
+		r.RequiredButMissing("name")
 		return
 	}
-	requiredAndFound(r, "name")
+	r.RequiredAndFound("name")
 }
 
 // Nothing to do for Description
 
-func (p *Playbook) checkPlaybookTypes(r *results) {
+func (p *Playbook) checkPlaybookTypes(r *objects.Results) {
+	r.At("playbook.playbook_types", "playbook_types")
+
 	if len(p.PlaybookTypes) == 0 {
-		requiredButMissing(r, "playbook_types")
+		r.RequiredButMissing("playbook_types")
 	} else {
-		requiredAndFound(r, "playbook_types")
+		r.RequiredAndFound("playbook_types")
 
 		for i := 0; i < len(p.PlaybookTypes); i++ {
 			value := p.PlaybookTypes[i]
 			if objects.IsVocabValueValid(value, GetPlaybookTypesVocab()) {
 				str := fmt.Sprintf("++ the playbook_types property contains a valid playbook_types value of \"%s\"", value)
-				logValid(r, str)
+				r.LogValid(str)
 			} else {
 				str := fmt.Sprintf("-- the playbook_types property contains a value of \"%s\" that is not in the vocabulary", value)
-				logProblem(r, str)
+				r.LogProblem(str)
 			}
 		}
 	}
 }
 
-func (p *Playbook) checkCreatedBy(r *results) {
+func (p *Playbook) checkCreatedBy(r *objects.Results) {
+	r.At("playbook.created_by", "created_by")
+
 	if p.CreatedBy == "" {
-		requiredButMissing(r, "created_by")
+		r.RequiredButMissing("created_by")
 	} else {
-		requiredAndFound(r, "created_by")
+		r.RequiredAndFound("created_by")
 
 		if valid := isCreatedByIDValid(p.CreatedBy); valid == false {
-			logProblem(r, "-- the created_by property does not contain a valid identifier")
+			r.LogProblem("-- the created_by property does not contain a valid identifier")
 		} else {
 			str := fmt.Sprintf("++ the created_by property contains a valid identifier value of \"%s\"", p.CreatedBy)
-			logValid(r, str)
+			r.LogValid(str)
 		}
 	}
 }
 
-func (p *Playbook) checkCreated(r *results) {
+func (p *Playbook) checkCreated(r *objects.Results) {
+	r.At("playbook.created", "created")
+
 	if p.Created == "" {
-		requiredButMissing(r, "created")
+		r.RequiredButMissing("created")
 	} else {
-		requiredAndFound(r, "created")
+		r.RequiredAndFound("created")
 
 		if valid := objects.IsTimestampValid(p.Created); valid == false {
-			logProblem(r, "-- the created property does not contain a valid timestamp")
+			r.LogProblem("-- the created property does not contain a valid timestamp")
 		} else {
 			str := fmt.Sprintf("++ the created property contains a valid timestamp value of \"%s\"", p.Created)
-			logValid(r, str)
+			r.LogValid(str)
 		}
 	}
 }
 
-func (p *Playbook) checkModified(r *results) {
+func (p *Playbook) checkModified(r *objects.Results) {
+	r.At("playbook.modified", "modified")
+
 	if p.Modified == "" {
-		requiredButMissing(r, "modified")
+		r.RequiredButMissing("modified")
 	} else {
-		requiredAndFound(r, "modified")
+		r.RequiredAndFound("modified")
 
 		if valid := objects.IsTimestampValid(p.Modified); valid == false {
-			logProblem(r, "-- the modified property does not contain a valid timestamp")
+			r.LogProblem("-- the modified property does not contain a valid timestamp")
 		} else {
 			str := fmt.Sprintf("++ the modified property contains a valid timestamp value of \"%s\"", p.Modified)
-			logValid(r, str)
+			r.LogValid(str)
 		}
 
 		// Make sure the modified timestampe is equal to or greater than created
@@ -273,9 +371,9 @@ func (p *Playbook) checkModified(r *results) {
 			created, _ := time.Parse(time.RFC3339, p.Created)
 			modified, _ := time.Parse(time.RFC3339, p.Modified)
 			if modified.After(created) || modified.Equal(created) {
-				logValid(r, "++ the modified timestamp is later than or equal to the created timestamp")
+				r.LogValid("++ the modified timestamp is later than or equal to the created timestamp")
 			} else {
-				logProblem(r, "-- the modified timestamp is not later than or eqaul to the created timestamp")
+				r.LogProblem("-- the modified timestamp is not later than or eqaul to the created timestamp")
 			}
 		}
 	}
@@ -283,22 +381,26 @@ func (p *Playbook) checkModified(r *results) {
 
 // Nothing to do for Revoked
 
-func (p *Playbook) checkValidFrom(r *results) {
+func (p *Playbook) checkValidFrom(r *objects.Results) {
+	r.At("playbook.valid_from", "valid_from")
+
 	if p.ValidFrom != "" {
 		if valid := objects.IsTimestampValid(p.ValidFrom); valid == false {
-			logProblem(r, "-- the valid_from property does not contain a valid timestamp")
+			r.LogProblem("-- the valid_from property does not contain a valid timestamp")
 		} else {
-			logValid(r, "++ the valid_from property contains a valid timestamp")
+			r.LogValid("++ the valid_from property contains a valid timestamp")
 		}
 	}
 }
 
-func (p *Playbook) checkValidUntil(r *results) {
+func (p *Playbook) checkValidUntil(r *objects.Results) {
+	r.At("playbook.valid_until", "valid_until")
+
 	if p.ValidUntil != "" {
 		if valid := objects.IsTimestampValid(p.ValidUntil); valid == false {
-			logProblem(r, "-- the valid_until property does not contain a valid timestamp")
+			r.LogProblem("-- the valid_until property does not contain a valid timestamp")
 		} else {
-			logValid(r, "++ the valid_until property contains a valid timestamp")
+			r.LogValid("++ the valid_until property contains a valid timestamp")
 		}
 
 		// If there is a valid_until timestamp, then lets check to see if there is also a valid_from and if so
@@ -307,15 +409,17 @@ func (p *Playbook) checkValidUntil(r *results) {
 			validFrom, _ := time.Parse(time.RFC3339, p.ValidFrom)
 			validUntil, _ := time.Parse(time.RFC3339, p.ValidUntil)
 			if validUntil.After(validFrom) {
-				logValid(r, "++ the valid_until timestamp is later than the valid_from timestamp")
+				r.LogValid("++ the valid_until timestamp is later than the valid_from timestamp")
 			} else {
-				logProblem(r, "-- the valid_until timestamp is not later than the valid_from timestamp")
+				r.LogProblem("-- the valid_until timestamp is not later than the valid_from timestamp")
 			}
 		}
 	}
 }
 
-func (p *Playbook) checkDerivedFrom(r *results) {
+func (p *Playbook) checkDerivedFrom(r *objects.Results) {
+	r.At("playbook.derived_from", "derived_from")
+
 	if len(p.DerivedFrom) == 0 {
 		return
 	}
@@ -323,54 +427,62 @@ func (p *Playbook) checkDerivedFrom(r *results) {
 		value := p.DerivedFrom[i]
 
 		if valid := isIDValid(value); valid == false {
-			logProblem(r, "-- the derived_from property does not contain a valid identifier")
+			r.LogProblem("-- the derived_from property does not contain a valid identifier")
 		} else {
 			str := fmt.Sprintf("++ the derived_from property contains a valid identifier value of \"%s\"", value)
-			logValid(r, str)
+			r.LogValid(str)
 		}
 	}
 }
 
-func (p *Playbook) checkPriority(r *results) {
+func (p *Playbook) checkPriority(r *objects.Results) {
+	r.At("playbook.priority", "priority")
+
 	if p.Priority < 0 {
-		logProblem(r, "-- the priority property does not contain a valid value, it is less than zero")
+		r.LogProblem("-- the priority property does not contain a valid value, it is less than zero")
 	} else if p.Priority > 100 {
-		logProblem(r, "-- the priority property does not contain a valid value, it is greater than 100")
+		r.LogProblem("-- the priority property does not contain a valid value, it is greater than 100")
 	} else if p.Priority >= 0 && p.Priority <= 100 {
-		logValid(r, "++ the priority property contains a valid value")
+		r.LogValid("++ the priority property contains a valid value")
 	}
 }
 
-func (p *Playbook) checkSeverity(r *results) {
+func (p *Playbook) checkSeverity(r *objects.Results) {
+	r.At("playbook.severity", "severity")
+
 	if p.Severity < 0 {
-		logProblem(r, "-- the severity property does not contain a valid value, it is less than zero")
+		r.LogProblem("-- the severity property does not contain a valid value, it is less than zero")
 	} else if p.Severity > 100 {
-		logProblem(r, "-- the severity property does not contain a valid value, it is greater than 100")
+		r.LogProblem("-- the severity property does not contain a valid value, it is greater than 100")
 	} else if p.Severity >= 0 && p.Severity <= 100 {
-		logValid(r, "++ the severity property contains a valid value")
+		r.LogValid("++ the severity property contains a valid value")
 	}
 }
 
-func (p *Playbook) checkImpact(r *results) {
+func (p *Playbook) checkImpact(r *objects.Results) {
+	r.At("playbook.impact", "impact")
+
 	if p.Impact < 0 {
-		logProblem(r, "-- the impact property does not contain a valid value, it is less than zero")
+		r.LogProblem("-- the impact property does not contain a valid value, it is less than zero")
 	} else if p.Impact > 100 {
-		logProblem(r, "-- the impact property does not contain a valid value, it is greater than 100")
+		r.LogProblem("-- the impact property does not contain a valid value, it is greater than 100")
 	} else if p.Impact >= 0 && p.Impact <= 100 {
-		logValid(r, "++ the impact property contains a valid value")
+		r.LogValid("++ the impact property contains a valid value")
 	}
 }
 
-func (p *Playbook) checkIndustrySectors(r *results) {
+func (p *Playbook) checkIndustrySectors(r *objects.Results) {
+	r.At("playbook.industry_sectors", "industry_sectors")
+
 	if len(p.IndustrySectors) > 0 {
 		for i := 0; i < len(p.IndustrySectors); i++ {
 			value := p.IndustrySectors[i]
 			if objects.IsVocabValueValid(value, objects.GetIndustrySectorsVocab()) {
 				str := fmt.Sprintf("++ the industry_sectors property contains a valid industry_sectors value of \"%s\"", value)
-				logValid(r, str)
+				r.LogValid(str)
 			} else {
 				str := fmt.Sprintf("-- the industry_sectors property contains a value of \"%s\" that is not in the vocabulary", value)
-				logProblem(r, str)
+				r.LogProblem(str)
 			}
 		}
 	}
@@ -378,25 +490,350 @@ func (p *Playbook) checkIndustrySectors(r *results) {
 
 // Nothing to do for Labels
 
-func (p *Playbook) checkExternalReferences(r *results) {
+func (p *Playbook) checkExternalReferences(r *objects.Results) {
 	if len(p.ExternalReferences) > 0 {
 		for i := range p.ExternalReferences {
+			r.At("playbook.external_references", "external_references", strconv.Itoa(i), "name")
 			if p.ExternalReferences[i].Name == "" {
-				logProblem(r, "-- the name property in an external reference is required but missing")
+				r.LogProblem("-- the name property in an external reference is required but missing")
 			} else {
-				logValid(r, "++ the name property in an external reference is required and is present")
+				r.LogValid("++ the name property in an external reference is required and is present")
 			}
 		}
 	}
 }
 
+func (p *Playbook) checkPlaybookVariables(r *objects.Results) {
+	for _, k := range sortedKeys(p.PlaybookVariables) {
+		v := p.PlaybookVariables[k]
+		r.At("playbook.playbook_variables", "playbook_variables", k, "value")
+		if err := v.Validate(); err != nil {
+			str := fmt.Sprintf("-- the playbook variable %s is not valid: %s", k, err)
+			r.LogProblem(str)
+		} else {
+			str := fmt.Sprintf("++ the playbook variable %s contains a valid %s value", k, v.ObjectType)
+			r.LogValid(str)
+		}
+	}
+}
+
 // Features
 // Markings
-// Playbook Variables
-// Workflow Start
-// Workflow Exception
-// Workflow
+
+func (p *Playbook) checkWorkflowStart(r *objects.Results) {
+	r.At("playbook.workflow_start", "workflow_start")
+
+	if p.WorkflowStart == "" {
+		r.RequiredButMissing("workflow_start")
+		return
+	}
+
+	r.RequiredAndFound("workflow_start")
+	step, found := p.Workflow[p.WorkflowStart]
+	if !found {
+		str := fmt.Sprintf("-- the workflow_start property references step %s which is not in the workflow", p.WorkflowStart)
+		r.LogProblem(str)
+		return
+	}
+
+	if step.GetCommon().ObjectType != "start" {
+		str := fmt.Sprintf("-- the workflow_start property references step %s which is not a start step", p.WorkflowStart)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the workflow_start property references the start step %s", p.WorkflowStart)
+		r.LogValid(str)
+	}
+}
+
+func (p *Playbook) checkWorkflowException(r *objects.Results) {
+	r.At("playbook.workflow_exception", "workflow_exception")
+
+	if p.WorkflowException == "" {
+		return
+	}
+
+	if _, found := p.Workflow[p.WorkflowException]; !found {
+		str := fmt.Sprintf("-- the workflow_exception property references step %s which is not in the workflow", p.WorkflowException)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the workflow_exception property references the step %s", p.WorkflowException)
+		r.LogValid(str)
+	}
+}
+
+// checkWorkflow - This method will check the workflow as a graph. Every
+// reference from one step to another must resolve to a step in the workflow,
+// every step must be reachable from the workflow_start or workflow_exception
+// step, and every step must be on a path that ends at an end step.
+func (p *Playbook) checkWorkflow(r *objects.Results) {
+	r.At("playbook.workflow", "workflow")
+	if len(p.Workflow) == 0 {
+		r.RequiredButMissing("workflow")
+		return
+	}
+	r.RequiredAndFound("workflow")
+
+	// Build the graph from the references that resolve, and report those that
+	// do not
+	next := make(map[string][]string, len(p.Workflow))
+	previous := make(map[string][]string, len(p.Workflow))
+	ids := make([]string, 0, len(p.Workflow))
+	for k := range p.Workflow {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+
+	for _, k := range ids {
+		for _, refs := range getStepReferences(p.Workflow[k]) {
+			property := refs.property
+			r.At("workflow.reference", append([]string{"workflow", k}, refs.tokens...)...)
+			for _, ref := range refs.ids {
+				if _, found := p.Workflow[ref]; !found {
+					str := fmt.Sprintf("-- the %s property in step %s references step %s which is not in the workflow", property, k, ref)
+					r.LogProblem(str)
+					continue
+				}
+				str := fmt.Sprintf("++ the %s property in step %s references step %s", property, k, ref)
+				r.LogValid(str)
+				next[k] = append(next[k], ref)
+				previous[ref] = append(previous[ref], k)
+			}
+		}
+	}
+
+	// Walk the graph forward from the entry points to find unreachable steps
+	var roots []string
+	if _, found := p.Workflow[p.WorkflowStart]; found {
+		roots = append(roots, p.WorkflowStart)
+	}
+	if _, found := p.Workflow[p.WorkflowException]; found {
+		roots = append(roots, p.WorkflowException)
+	}
+	reachable := walkWorkflow(roots, next)
+
+	// Walk the graph backward from the end steps to find steps that can never
+	// finish
+	var ends []string
+	for _, k := range ids {
+		if p.Workflow[k].GetCommon().ObjectType == "end" {
+			ends = append(ends, k)
+		}
+	}
+	finishes := walkWorkflow(ends, previous)
+
+	for _, k := range ids {
+		r.At("workflow.reachable", "workflow", k)
+		if len(roots) > 0 {
+			if reachable[k] {
+				str := fmt.Sprintf("++ the step %s is reachable from the start of the workflow", k)
+				r.LogValid(str)
+			} else {
+				str := fmt.Sprintf("-- the step %s is not reachable from the start of the workflow", k)
+				r.LogProblem(str)
+			}
+		}
+
+		r.At("workflow.termination", "workflow", k)
+		if finishes[k] {
+			str := fmt.Sprintf("++ the step %s is on a path that ends at an end step", k)
+			r.LogValid(str)
+		} else if len(next[k]) == 0 {
+			str := fmt.Sprintf("-- the step %s does not reference a next step and is not an end step", k)
+			r.LogProblem(str)
+		} else {
+			str := fmt.Sprintf("-- the step %s is not on a path that ends at an end step", k)
+			r.LogProblem(str)
+		}
+	}
+}
+
+// checkWorkflowSteps - This method will make sure that the key of each step in
+// the workflow starts with the type of the step and then call the Check()
+// method on each step to enforce the step type specific rules. The key is the
+// only identifier of the step that is checked.
+func (p *Playbook) checkWorkflowSteps(r *objects.Results) {
+	ids := make([]string, 0, len(p.Workflow))
+	for k := range p.Workflow {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+
+	for _, k := range ids {
+		v := p.Workflow[k]
+		objType := v.GetCommon().ObjectType
+		r.At("workflow.step_id", "workflow", k)
+		if !strings.HasPrefix(k, objType+"--") || !objects.IsUUIDValid(strings.TrimPrefix(k, objType+"--")) {
+			str := fmt.Sprintf("-- the workflow step %s does not have an identifier that starts with its type %s", k, objType)
+			r.LogProblem(str)
+		}
+
+		// The ID of a decoded or added step is its key, which is checked
+		// above, so the finding of the step for its own ID is left out
+		var findings []objects.Finding
+		for _, f := range v.Check(r.Debug) {
+			if f.Rule != "workflow.step.id" {
+				findings = append(findings, f)
+			}
+		}
+
+		valid := r.LogNested(findings, "workflow step "+k, "workflow", k)
+		if valid {
+			r.At("workflow.step", "workflow", k)
+			str := fmt.Sprintf("++ the workflow step %s is valid", k)
+			r.LogValid(str)
+		}
+	}
+}
+
+// checkAuthenticationInfoDefinitions - This method will call the Valid()
+// method on each authentication information object and make sure that the
+// authentication_info property of each agent and target refers to one of them.
+func (p *Playbook) checkAuthenticationInfoDefinitions(r *objects.Results) {
+	for _, k := range sortedKeys(p.AuthenticationInfoDefinitions) {
+		v := p.AuthenticationInfoDefinitions[k]
+		valid := r.LogNested(v.Check(r.Debug), "authentication information "+k, "authentication_info_definitions", k)
+		if valid {
+			r.At("authentication_info.object", "authentication_info_definitions", k)
+			str := fmt.Sprintf("++ the authentication information %s is valid", k)
+			r.LogValid(str)
+		}
+	}
+
+	// Every agent or target that refers to authentication information must
+	// refer to an object defined in the authentication_info_definitions
+	check := func(property string, m map[string]agents.AgentObject) {
+		for _, k := range sortedKeys(m) {
+			v := m[k]
+			r.At("authentication_info.reference", property+"_definitions", k, "authentication_info")
+			var ref string
+			switch a := v.(type) {
+			case *agents.SSHCLI:
+				ref = a.AuthenticationInfo
+			case *agents.HTTPAPI:
+				ref = a.AuthenticationInfo
+			case *agents.Linux:
+				ref = a.AuthenticationInfo
+			case *agents.LinuxCLI:
+				ref = a.AuthenticationInfo
+			}
+			if ref == "" {
+				continue
+			}
+			if _, found := p.AuthenticationInfoDefinitions[ref]; found {
+				str := fmt.Sprintf("++ the authentication_info property in %s %s refers to a defined authentication information object", property, k)
+				r.LogValid(str)
+			} else {
+				str := fmt.Sprintf("-- the authentication_info property in %s %s refers to \"%s\" which is not in authentication_info_definitions", property, k, ref)
+				r.LogProblem(str)
+			}
+		}
+	}
+	check("agent", p.AgentDefinitions)
+	check("target", p.TargetDefinitions)
+}
+
 // Targets
-// Extension definitions
-// Data Marking Definitions
-// Signatures
+
+func (p *Playbook) checkExtensionDefinitions(r *objects.Results) {
+	for _, k := range sortedKeys(p.ExtensionDefinitions) {
+		v := p.ExtensionDefinitions[k]
+		r.At("extension_definition.type", "extension_definitions", k, "type")
+		if v.ObjectType != "extension-definition" {
+			str := fmt.Sprintf("-- the type property in extension definition %s does not contain a value of extension-definition", k)
+			r.LogProblem(str)
+		}
+
+		required := map[string]string{
+			"name":       v.Name,
+			"created_by": v.CreatedBy,
+			"schema":     v.Schema,
+			"version":    v.Version,
+		}
+		for _, property := range []string{"name", "created_by", "schema", "version"} {
+			r.At("extension_definition."+property, "extension_definitions", k, property)
+			if required[property] == "" {
+				str := fmt.Sprintf("-- the %s property in extension definition %s is required but missing", property, k)
+				r.LogProblem(str)
+			} else {
+				str := fmt.Sprintf("++ the %s property in extension definition %s is required and is found", property, k)
+				r.LogValid(str)
+			}
+		}
+
+		r.At("extension_definition.created_by", "extension_definitions", k, "created_by")
+		if v.CreatedBy != "" && !isCreatedByIDValid(v.CreatedBy) {
+			str := fmt.Sprintf("-- the created_by property in extension definition %s does not contain a valid identifier", k)
+			r.LogProblem(str)
+		}
+	}
+}
+
+// checkExtensions - This method will make sure that every extension used in the
+// playbook, in a workflow step, or in a data marking refers to an extension
+// definition, and that the processing summary records that extensions are used.
+func (p *Playbook) checkExtensions(r *objects.Results) {
+	found := false
+	check := func(location string, pointer []string, e map[string]json.RawMessage) {
+		for _, k := range sortedKeys(e) {
+			r.At("extension.reference", append(pointer, k)...)
+			found = true
+			if _, defined := p.ExtensionDefinitions[k]; defined {
+				str := fmt.Sprintf("++ the extension %s in %s refers to a defined extension definition", k, location)
+				r.LogValid(str)
+			} else {
+				str := fmt.Sprintf("-- the extension %s in %s does not refer to an extension definition in extension_definitions", k, location)
+				r.LogProblem(str)
+			}
+		}
+	}
+
+	check("playbook_extensions", []string{"playbook_extensions"}, p.PlaybookExtensions)
+	for _, k := range sortedKeys(p.Workflow) {
+		v := p.Workflow[k]
+		check("workflow step "+k, []string{"workflow", k, "step_extensions"}, v.GetCommon().StepExtensions)
+	}
+	for _, k := range sortedKeys(p.DataMarkingDefinitions) {
+		v := p.DataMarkingDefinitions[k]
+		check("data marking "+k, []string{"data_marking_definitions", k, "marking_extensions"}, v.GetCommon().MarkingExtensions)
+	}
+
+	if found {
+		r.At("extension.processing_summary", "playbook_processing_summary", "extensions")
+		if p.PlaybookProcessingSummary != nil && p.PlaybookProcessingSummary.Extensions {
+			r.LogValid("++ the playbook_processing_summary records that extensions are used")
+		} else {
+			r.LogProblem("-- the playbook uses extensions but the extensions property in playbook_processing_summary is not true")
+		}
+	}
+}
+
+func (p *Playbook) checkDataMarkingDefinitions(r *objects.Results) {
+	for _, k := range sortedKeys(p.DataMarkingDefinitions) {
+		// Not every data marking type has its own validation yet
+		m, ok := p.DataMarkingDefinitions[k].(interface {
+			Check(debug bool) []objects.Finding
+		})
+		if !ok {
+			continue
+		}
+
+		valid := r.LogNested(m.Check(r.Debug), "data marking "+k, "data_marking_definitions", k)
+		if valid {
+			r.At("data_marking.object", "data_marking_definitions", k)
+			str := fmt.Sprintf("++ the data marking %s is valid", k)
+			r.LogValid(str)
+		}
+	}
+}
+
+func (p *Playbook) checkSignatures(r *objects.Results) {
+	for i := range p.Signatures {
+		s := &p.Signatures[i]
+		valid := r.LogNested(s.Check(r.Debug), "signature "+s.ID, "signatures", strconv.Itoa(i))
+		if valid {
+			r.At("signature.object", "signatures", strconv.Itoa(i))
+			str := fmt.Sprintf("++ the signature %s is valid", s.ID)
+			r.LogValid(str)
+		}
+	}
+}
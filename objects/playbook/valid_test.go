@@ -6,141 +6,150 @@
 package playbook
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/signature"
+	"github.com/openplaybooks/libcacao/objects/workflow"
 )
 
-func setup(r *results) {
-	r.problemsFound = 0
-	r.resultDetails = nil
-	r.debug = true
+func setup(r *objects.Results) {
+	r.ProblemsFound = 0
+	r.ResultDetails = nil
+	r.Debug = true
 }
 
 // TestCheckObjectType - This will test the object type property
 func TestCheckObjectType(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkObjectType(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("1.1 checkObjectType returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("1.1 checkObjectType returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.ObjectType = "foo"
 	p.checkObjectType(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("1.2 checkObjectType returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("1.2 checkObjectType returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("1.3 checkObjectType returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("1.3 checkObjectType returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.ObjectType = "playbook"
 	p.checkObjectType(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("1.4 checkObjectType returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("1.4 checkObjectType returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("1.5 checkObjectType returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("1.5 checkObjectType returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckSpecVersion - This will check the spec_version property
 func TestCheckSpecVersion(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkSpecVersion(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("2.1 checkSpecVersion returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("2.1 checkSpecVersion returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.SpecVersion = "0.9"
 	p.checkSpecVersion(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("2.2 checkSpecVersion returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("2.2 checkSpecVersion returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("2.3 checkSpecVersion returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("2.3 checkSpecVersion returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
-	p.SpecVersion = "1.0"
+	p.SpecVersion = "cacao-2.0"
 	p.checkSpecVersion(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("2.4 checkSpecVersion returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("2.4 checkSpecVersion returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("2.5 checkSpecVersion returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("2.5 checkSpecVersion returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckID - This will check the id property
 func TestCheckID(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkID(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("3.1 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("3.1 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.ID = "playbook--uuid1"
 	p.checkID(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("3.2 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("3.2 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("3.3 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("3.3 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.ID = "foo--60cfe320-f6b4-4523-8558-14a042223797"
 	p.checkID(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("3.4 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("3.4 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("3.5 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("3.5 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.ID = "playbook--60cfe320-f6b4-4523-8558-14a042223797"
 	p.checkID(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("3.6 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("3.6 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("3.7 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("3.7 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckName - This will check the name property
 func TestCheckName(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkName(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("4.1 checkName returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("4.1 checkName returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
@@ -149,13 +158,13 @@ func TestCheckName(t *testing.T) {
 // TestCheckPlaybookTypes - This will check the playbook_types property
 func TestCheckPlaybookTypes(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkPlaybookTypes(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("6.1 checkPlaybookTypes returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("6.1 checkPlaybookTypes returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
@@ -163,11 +172,11 @@ func TestCheckPlaybookTypes(t *testing.T) {
 	p.PlaybookTypes = nil
 	p.PlaybookTypes = append(p.PlaybookTypes, "test")
 	p.checkPlaybookTypes(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("6.2 checkPlaybookTypes returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("6.2 checkPlaybookTypes returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("6.3 checkPlaybookTypes returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("6.3 checkPlaybookTypes returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
@@ -175,35 +184,35 @@ func TestCheckPlaybookTypes(t *testing.T) {
 	p.PlaybookTypes = nil
 	p.PlaybookTypes = append(p.PlaybookTypes, "notification")
 	p.checkPlaybookTypes(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("6.4 checkPlaybookTypes returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("6.4 checkPlaybookTypes returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("6.5 checkPlaybookTypes returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("6.5 checkPlaybookTypes returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckCreatedBy - This will check the created_by property
 func TestCheckCreatedBy(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkCreatedBy(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("7.1 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("7.1 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.CreatedBy = "identity--uuid1"
 	p.checkCreatedBy(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("7.2 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("7.2 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("7.3 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("7.3 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 	// No other checks will be made since it will fail the first part
 
@@ -211,11 +220,11 @@ func TestCheckCreatedBy(t *testing.T) {
 	setup(r)
 	p.CreatedBy = "foo--60cfe320-f6b4-4523-8558-14a042223797"
 	p.checkCreatedBy(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("7.4 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("7.4 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("7.5 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("7.5 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 	// No other checks will be made since it will fail the first part
 
@@ -223,92 +232,92 @@ func TestCheckCreatedBy(t *testing.T) {
 	setup(r)
 	p.CreatedBy = "step--60cfe320-f6b4-4523-8558-14a042223797"
 	p.checkCreatedBy(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("7.6 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("7.6 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("7.7 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("7.7 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check valid value
 	setup(r)
 	p.CreatedBy = "identity--60cfe320-f6b4-4523-8558-14a042223797"
 	p.checkCreatedBy(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("7.8 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("7.8 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("7.9 checkCreatedBy returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("7.9 checkCreatedBy returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckCreated - This will check the created property
 func TestCheckCreated(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkCreated(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("8.1 checkCreated returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("8.1 checkCreated returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.Created = "Some data 2021"
 	p.checkCreated(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("8.2 checkCreated returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("8.2 checkCreated returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("8.3 checkCreated returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("8.3 checkCreated returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.Created = "2021-02-02T12:12:12.123Z"
 	p.checkCreated(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("8.4 checkCreated returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("8.4 checkCreated returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("8.5 checkCreated returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("8.5 checkCreated returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckModified - This will check the modified property
 func TestCheckModified(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is missing
 	setup(r)
 	p.checkModified(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("9.1 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("9.1 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check invalid value
 	setup(r)
 	p.Modified = "Some data 2021"
 	p.checkModified(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("9.2 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("9.2 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("9.3 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("9.3 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.Modified = "2021-02-02T12:12:12.123Z"
 	p.checkModified(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("9.4 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("9.4 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("9.5 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("9.5 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if before Created value
@@ -316,14 +325,14 @@ func TestCheckModified(t *testing.T) {
 	p.Created = "2021-02-03T12:12:12.123Z"
 	p.Modified = "2021-02-02T12:12:12.123Z"
 	p.checkModified(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("9.6 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("9.6 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("9.7 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("9.7 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[2][0:2] != "--" {
-		t.Errorf("9.8 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[2][0:2] != "--" {
+		t.Errorf("9.8 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if same as Created value
@@ -331,14 +340,14 @@ func TestCheckModified(t *testing.T) {
 	p.Created = "2021-02-02T12:12:12.123Z"
 	p.Modified = "2021-02-02T12:12:12.123Z"
 	p.checkModified(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("9.9 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("9.9 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("9.10 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("9.10 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[2][0:2] != "++" {
-		t.Errorf("9.11 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[2][0:2] != "++" {
+		t.Errorf("9.11 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if after Created value
@@ -346,14 +355,14 @@ func TestCheckModified(t *testing.T) {
 	p.Created = "2021-02-01T12:12:12.123Z"
 	p.Modified = "2021-02-02T12:12:12.123Z"
 	p.checkModified(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("9.12 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("9.12 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("9.13 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("9.13 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[2][0:2] != "++" {
-		t.Errorf("9.14 checkModified returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[2][0:2] != "++" {
+		t.Errorf("9.14 checkModified returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
@@ -362,44 +371,44 @@ func TestCheckModified(t *testing.T) {
 // TestCheckValidFrom - This will check the valid_from property
 func TestCheckValidFrom(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check invalid value
 	setup(r)
 	p.ValidFrom = "Some data 2021"
 	p.checkValidFrom(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("11.1 checkValidFrom returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("11.1 checkValidFrom returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.ValidFrom = "2021-02-02T12:12:12.123Z"
 	p.checkValidFrom(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("11.2 checkValidFrom returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("11.2 checkValidFrom returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckValidUntil - This will check the valid_from property
 func TestCheckValidUntil(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check invalid value
 	setup(r)
 	p.ValidUntil = "Some data 2021"
 	p.checkValidUntil(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("12.1 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("12.1 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
 	setup(r)
 	p.ValidUntil = "2021-02-02T12:12:12.123Z"
 	p.checkValidUntil(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("12.2 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("12.2 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if before Valid From value
@@ -407,11 +416,11 @@ func TestCheckValidUntil(t *testing.T) {
 	p.ValidFrom = "2021-02-03T12:12:12.123Z"
 	p.ValidUntil = "2021-02-02T12:12:12.123Z"
 	p.checkValidUntil(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("12.3 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("12.3 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("12.4 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("12.4 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if same as Valid From value
@@ -419,11 +428,11 @@ func TestCheckValidUntil(t *testing.T) {
 	p.ValidFrom = "2021-02-02T12:12:12.123Z"
 	p.ValidUntil = "2021-02-02T12:12:12.123Z"
 	p.checkValidUntil(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("12.5 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("12.5 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 1 || r.resultDetails[1][0:2] != "--" {
-		t.Errorf("12.6 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[1][0:2] != "--" {
+		t.Errorf("12.6 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check if after Valid From value
@@ -431,11 +440,11 @@ func TestCheckValidUntil(t *testing.T) {
 	p.ValidFrom = "2021-02-01T12:12:12.123Z"
 	p.ValidUntil = "2021-02-02T12:12:12.123Z"
 	p.checkValidUntil(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("12.7 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("12.7 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
-	if r.problemsFound != 0 || r.resultDetails[1][0:2] != "++" {
-		t.Errorf("12.8 checkValidUntil returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[1][0:2] != "++" {
+		t.Errorf("12.8 checkValidUntil returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 }
@@ -443,15 +452,15 @@ func TestCheckValidUntil(t *testing.T) {
 // TestCheckDerivedFrom - This will check the id
 func TestCheckDerivedFrom(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check invalid value
 	setup(r)
 	p.DerivedFrom = nil
 	p.DerivedFrom = append(p.DerivedFrom, "playbook--uuid1")
 	p.checkDerivedFrom(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("13.1 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("13.1 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 	// No other checks will be made since it will fail the first part
 
@@ -460,8 +469,8 @@ func TestCheckDerivedFrom(t *testing.T) {
 	p.DerivedFrom = nil
 	p.DerivedFrom = append(p.DerivedFrom, "foo--60cfe320-f6b4-4523-8558-14a042223797")
 	p.checkDerivedFrom(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("13.2 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("13.2 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 	// No other checks will be made since it will fail the first part
 
@@ -470,8 +479,8 @@ func TestCheckDerivedFrom(t *testing.T) {
 	p.DerivedFrom = nil
 	p.DerivedFrom = append(p.DerivedFrom, "step--60cfe320-f6b4-4523-8558-14a042223797")
 	p.checkDerivedFrom(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("13.4 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("13.4 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
@@ -479,161 +488,161 @@ func TestCheckDerivedFrom(t *testing.T) {
 	p.DerivedFrom = nil
 	p.DerivedFrom = append(p.DerivedFrom, "playbook--60cfe320-f6b4-4523-8558-14a042223797")
 	p.checkDerivedFrom(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("13.5 checkID returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("13.5 checkID returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckPriority - This will check the priority property
 func TestCheckPriority(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is invalid
 	setup(r)
 	p.Priority = -1
 	p.checkPriority(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("14.1 checkPriority returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("14.1 checkPriority returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is invalid
 	setup(r)
 	p.Priority = 105
 	p.checkPriority(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("14.2 checkPriority returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("14.2 checkPriority returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Priority = 0
 	p.checkPriority(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("14.3 checkPriority returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("14.3 checkPriority returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Priority = 10
 	p.checkPriority(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("14.4 checkPriority returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("14.4 checkPriority returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Priority = 100
 	p.checkPriority(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("14.5 checkPriority returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("14.5 checkPriority returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckSeverity - This will check the priority property
 func TestCheckSeverity(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is invalid
 	setup(r)
 	p.Severity = -1
 	p.checkSeverity(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("15.1 checkSeverity returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("15.1 checkSeverity returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is invalid
 	setup(r)
 	p.Severity = 105
 	p.checkSeverity(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("15.2 checkSeverity returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("15.2 checkSeverity returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Severity = 0
 	p.checkSeverity(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("15.3 checkSeverity returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("15.3 checkSeverity returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Severity = 10
 	p.checkSeverity(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("15.4 checkSeverity returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("15.4 checkSeverity returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Severity = 100
 	p.checkSeverity(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("15.5 checkSeverity returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("15.5 checkSeverity returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckImpact - This will check the priority property
 func TestCheckImpact(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check when property is invalid
 	setup(r)
 	p.Impact = -1
 	p.checkImpact(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("16.1 checkImpact returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("16.1 checkImpact returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is invalid
 	setup(r)
 	p.Impact = 105
 	p.checkImpact(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("16.2 checkImpact returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("16.2 checkImpact returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Impact = 0
 	p.checkImpact(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("16.3 checkImpact returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("16.3 checkImpact returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Impact = 10
 	p.checkImpact(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("16.4 checkImpact returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("16.4 checkImpact returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check when property is valid
 	setup(r)
 	p.Impact = 100
 	p.checkImpact(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("16.5 checkImpact returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("16.5 checkImpact returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 }
 
 // TestCheckIndustrySectors - This will check the industry_sectors property
 func TestCheckIndustrySectors(t *testing.T) {
 	p := new(Playbook)
-	r := new(results)
+	r := new(objects.Results)
 
 	// Check invalid value
 	setup(r)
 	p.IndustrySectors = nil
 	p.IndustrySectors = append(p.IndustrySectors, "test")
 	p.checkIndustrySectors(r)
-	if r.problemsFound != 1 || r.resultDetails[0][0:2] != "--" {
-		t.Errorf("17.2 checkIndustrySectors returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("17.2 checkIndustrySectors returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
 	}
 
 	// Check correct value
@@ -641,7 +650,251 @@ func TestCheckIndustrySectors(t *testing.T) {
 	p.IndustrySectors = nil
 	p.IndustrySectors = append(p.IndustrySectors, "energy")
 	p.checkIndustrySectors(r)
-	if r.problemsFound != 0 || r.resultDetails[0][0:2] != "++" {
-		t.Errorf("17.4 checkIndustrySectors returned errors %d and results %s which is invalid", r.problemsFound, r.resultDetails)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("17.4 checkIndustrySectors returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+}
+
+// TestCheckPlaybookVariables - This will check the playbook_variables property
+func TestCheckPlaybookVariables(t *testing.T) {
+	p := new(Playbook)
+	r := new(objects.Results)
+
+	// Check that AddVariable refuses a value that does not match its type
+	v := objects.NewVariable()
+	v.ObjectType = "ipv4-addr"
+	v.Name = "__ip__"
+	v.Value = "not an ip"
+	if err := p.AddVariable(*v); err == nil {
+		t.Errorf("18.1 AddVariable accepted an invalid ipv4-addr value")
+	}
+
+	// Check invalid value
+	setup(r)
+	p.PlaybookVariables = map[string]objects.Variables{"__ip__": *v}
+	p.checkPlaybookVariables(r)
+	if r.ProblemsFound != 1 || r.ResultDetails[0][0:2] != "--" {
+		t.Errorf("18.2 checkPlaybookVariables returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check correct value
+	setup(r)
+	p.PlaybookVariables = nil
+	v.Value = "10.0.0.0/8"
+	if err := p.AddVariable(*v); err != nil {
+		t.Errorf("18.3 AddVariable refused a valid ipv4-addr value: %s", err)
+	}
+	p.checkPlaybookVariables(r)
+	if r.ProblemsFound != 0 || r.ResultDetails[0][0:2] != "++" {
+		t.Errorf("18.4 checkPlaybookVariables returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+}
+
+// TestCheckWorkflow - This will check the workflow_start and workflow
+// properties as a graph
+func TestCheckWorkflow(t *testing.T) {
+	p := newSpecExamplePlaybook()
+	r := new(objects.Results)
+
+	// Check correct workflow
+	setup(r)
+	p.checkWorkflowStart(r)
+	p.checkWorkflow(r)
+	if r.ProblemsFound != 0 {
+		t.Errorf("19.1 checkWorkflow returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check workflow_start that does not point at a start step
+	setup(r)
+	p.WorkflowStart = "end--6b23c237-ade8-4d00-9aa1-75999738d557"
+	p.checkWorkflowStart(r)
+	if r.ProblemsFound != 1 || !strings.Contains(r.ResultDetails[1], "not a start step") {
+		t.Errorf("19.2 checkWorkflowStart returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+	p.WorkflowStart = "start--07bea005-4a36-4a77-bd1f-79a6e4682a13"
+
+	// Check a reference that does not resolve
+	setup(r)
+	step := p.Workflow["if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3"].(*workflow.IfStep)
+	step.AddOnFalse("action--00000000-0000-4000-8000-000000000000")
+	p.checkWorkflow(r)
+	if r.ProblemsFound != 1 || !strings.Contains(strings.Join(r.ResultDetails, "\n"), "-- the on_false property in step if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3") {
+		t.Errorf("19.3 checkWorkflow returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+	step.OnFalse = nil
+
+	// Check a step that is not reachable and does not end
+	setup(r)
+	orphan, _ := workflow.NewActionStep()
+	p.Workflow["action--11111111-1111-4111-8111-111111111111"] = orphan
+	p.checkWorkflow(r)
+	if r.ProblemsFound != 2 {
+		t.Errorf("19.4 checkWorkflow returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check a loop that never reaches an end step, which also leaves the if
+	// and end steps unreachable
+	setup(r)
+	orphan.OnCompletion = "action--7f40f9d7-de39-4027-ab97-15035beff2ff"
+	action := p.Workflow["action--7f40f9d7-de39-4027-ab97-15035beff2ff"].(*workflow.ActionStep)
+	action.OnCompletion = "action--11111111-1111-4111-8111-111111111111"
+	p.checkWorkflow(r)
+	if r.ProblemsFound != 5 {
+		t.Errorf("19.5 checkWorkflow returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check that a switch case with a period in its key gets its own pointer
+	p = newSpecExamplePlaybook()
+	r = new(objects.Results)
+	sw, _ := workflow.NewSwitchStep()
+	sw.Cases = map[string][]string{"1.0": {"action--00000000-0000-4000-8000-000000000000"}}
+	p.Workflow["switch-condition--22222222-2222-4222-8222-222222222222"] = sw
+	p.checkWorkflow(r)
+	found := false
+	for _, f := range r.Findings {
+		if f.Rule == "workflow.reference" && f.Pointer == "/workflow/switch-condition--22222222-2222-4222-8222-222222222222/cases/1.0" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("19.6 checkWorkflow did not point at the switch case, findings %+v", r.Findings)
+	}
+}
+
+// TestCheckWorkflowSteps - This will check that the step type specific rules
+// are enforced for each step in the workflow
+func TestCheckWorkflowSteps(t *testing.T) {
+	p := newSpecExamplePlaybook()
+	r := new(objects.Results)
+
+	// Check correct steps
+	setup(r)
+	p.checkWorkflowSteps(r)
+	if r.ProblemsFound != 0 {
+		t.Errorf("20.1 checkWorkflowSteps returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check an end step that uses on_completion and a key that does not match
+	// the type
+	setup(r)
+	end := p.Workflow["end--6b23c237-ade8-4d00-9aa1-75999738d557"].(*workflow.EndStep)
+	end.OnCompletion = "start--07bea005-4a36-4a77-bd1f-79a6e4682a13"
+	p.Workflow["action--6b23c237-ade8-4d00-9aa1-75999738d557"] = end
+	p.checkWorkflowSteps(r)
+	if r.ProblemsFound != 3 {
+		t.Errorf("20.2 checkWorkflowSteps returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// A decoded step has its key as its ID, the bad key is only reported once
+	setup(r)
+	end.SetID("action--6b23c237-ade8-4d00-9aa1-75999738d557")
+	p.checkWorkflowSteps(r)
+	if r.ProblemsFound != 3 {
+		t.Errorf("20.3 checkWorkflowSteps returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+}
+
+// TestCheck - This will test that Check returns findings with rule codes and
+// JSON Pointers that match the strings returned by Valid
+func TestCheck(t *testing.T) {
+	p := newSpecExamplePlaybook()
+	step := p.Workflow["if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3"].(*workflow.IfStep)
+	step.AddOnFalse("action--00000000-0000-4000-8000-000000000000")
+	p.Name = ""
+
+	_, count, details := p.Valid(true)
+	findings := p.Check(true)
+	if len(findings) != len(details) {
+		t.Fatalf("21.1 Check returned %d findings but Valid returned %d results", len(findings), len(details))
+	}
+
+	problems := 0
+	for i, f := range findings {
+		if f.String() != details[i] {
+			t.Errorf("21.2 finding %d rendered as %s instead of %s", i, f.String(), details[i])
+		}
+		if f.Rule == "" || f.Pointer == "" {
+			t.Errorf("21.3 finding %d is missing a rule or pointer %+v", i, f)
+		}
+		if f.IsProblem() {
+			problems++
+		}
+	}
+	if problems != count {
+		t.Errorf("21.4 Check returned %d problems but Valid returned %d", problems, count)
+	}
+
+	want := map[string]string{
+		"/name": "playbook.name",
+		"/workflow/if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3/on_false": "workflow.reference",
+	}
+	for _, f := range p.Check(false) {
+		if rule, found := want[f.Pointer]; found && rule == f.Rule && f.Severity == objects.SeverityError {
+			delete(want, f.Pointer)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("21.5 Check did not return findings for %v", want)
+	}
+
+	// The findings of a nested object keep their own rule and point to the
+	// property in the nested object
+	action := p.Workflow["action--7f40f9d7-de39-4027-ab97-15035beff2ff"].(*workflow.ActionStep)
+	action.Commands[0].ObjectType = ""
+	p.PlaybookVariables["__a__"] = objects.Variables{ObjectType: "ipv4-addr", Value: "x"}
+	p.PlaybookVariables["__b__"] = objects.Variables{ObjectType: "ipv4-addr", Value: "y"}
+	findings = p.Check(false)
+	found := false
+	for _, f := range findings {
+		if f.Rule == "workflow.command.type" && f.Pointer == "/workflow/action--7f40f9d7-de39-4027-ab97-15035beff2ff/commands/0/type" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("21.6 Check did not return the finding for the command %+v", findings)
+	}
+
+	// The findings from the dictionaries are always in the same order
+	for i := 0; i < 10; i++ {
+		if again := p.Check(false); !reflect.DeepEqual(again, findings) {
+			t.Fatalf("21.7 Check returned the findings in another order\n%+v\n%+v", findings, again)
+		}
+	}
+}
+
+// TestCheckSignatures - This will test that each signature object is checked
+func TestCheckSignatures(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p := newSpecExamplePlaybook()
+	r := new(objects.Results)
+
+	sig := signature.New()
+	sig.Signee = "ACME Cyber Company"
+	sig.SetPublicKey(key.Public())
+	if err := p.Sign("ES256", key, sig); err != nil {
+		t.Fatalf("22.1 unable to sign playbook: %s", err)
+	}
+
+	setup(r)
+	p.checkSignatures(r)
+	if r.ProblemsFound != 0 {
+		t.Errorf("22.2 checkSignatures returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	// Check a signature with missing and invalid properties
+	setup(r)
+	p.Signatures[0].Signee = ""
+	p.Signatures[0].ValidUntil = "tomorrow"
+	p.checkSignatures(r)
+	if r.ProblemsFound != 2 || !strings.HasSuffix(r.ResultDetails[len(r.ResultDetails)-1], "in signature "+sig.ID) {
+		t.Errorf("22.3 checkSignatures returned errors %d and results %s which is invalid", r.ProblemsFound, r.ResultDetails)
+	}
+
+	findings := p.Check(false)
+	if len(findings) != 2 || findings[0].Rule != "signature.signee" || findings[0].Pointer != "/signatures/0/signee" {
+		t.Errorf("22.4 Check did not return findings for the signature %+v", findings)
+	}
+	if f := findings[1]; f.Rule != "signature.valid_until" || f.Pointer != "/signatures/0/valid_until" {
+		t.Errorf("22.5 Check did not return findings for the signature %+v", f)
 	}
 }
@@ -0,0 +1,167 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// These errors are returned, possibly wrapped, by Sign(), Verify() and in the
+// results from VerifyAll() so that callers can tell why a signature could not
+// be created or did not verify. They are the same errors as in the signature
+// package.
+var (
+	ErrMissingKey           = signature.ErrMissingKey
+	ErrMissingValue         = signature.ErrMissingValue
+	ErrUnsupportedAlgorithm = signature.ErrUnsupportedAlgorithm
+	ErrAlgorithmMismatch    = signature.ErrKeyMismatch
+	ErrInvalidSignature     = signature.ErrInvalidSignature
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// VerifyResult - This type captures the result of verifying a single
+// signature on a playbook. If the signature is not valid, Err will say why.
+type VerifyResult struct {
+	SignatureID string
+	Signee      string
+	Algorithm   string
+	Valid       bool
+	Err         error
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Verify - This method will verify a signature on the playbook with the public
+// key passed in. It rebuilds the form of the playbook that was signed by
+// Sign(), with all other signatures removed, and checks the signature value
+// against the SHA-256 hash of the JCS version of it. If the signature was
+// imported with ImportJWS(), the value is a detached JWS and it is checked
+// against the JCS version itself. It returns nil if the signature is valid.
+func (p *Playbook) Verify(sig *signature.Signature, key crypto.PublicKey) error {
+	if sig == nil {
+		return errors.New("no signature was given")
+	}
+
+	// Signatures imported from a detached JWS are checked with the JWS form
+	if signature.IsDetachedJWS(sig.Value) {
+		payload, err := p.signingPayload(*sig)
+		if err != nil {
+			return err
+		}
+		return signature.VerifyDetachedJWS(sig.Algorithm, sig.Value, payload, key)
+	}
+
+	hash, err := p.signingHash(*sig)
+	if err != nil {
+		return err
+	}
+	return signature.VerifyHash(sig.Algorithm, hash, sig.Value, key)
+}
+
+// VerifyAll - This method will verify every signature on the playbook using
+// the key resolver to find the public key for each one. It returns a result
+// for each signature in the same order as the signatures property.
+func (p *Playbook) VerifyAll(resolver signature.KeyResolver) []VerifyResult {
+	results := make([]VerifyResult, 0, len(p.Signatures))
+
+	for i := range p.Signatures {
+		sig := &p.Signatures[i]
+		result := VerifyResult{
+			SignatureID: sig.ID,
+			Signee:      sig.Signee,
+			Algorithm:   sig.Algorithm,
+		}
+
+		key, err := resolver.ResolveKey(sig)
+		if err != nil {
+			result.Err = fmt.Errorf("%w: %s", ErrMissingKey, err)
+		} else {
+			result.Err = p.Verify(sig, key)
+		}
+		result.Valid = result.Err == nil
+		results = append(results, result)
+	}
+	return results
+}
+
+// Countersign - This method will countersign the signature on the playbook
+// with the ID given, using the signing method, key and counter signature
+// object. If the signature already has countersignatures, the last one in the
+// chain is signed, so each reviewer vouches for the one before.
+func (p *Playbook) Countersign(id string, method string, key interface{}, counter *signature.Signature) error {
+	sig := p.findSignature(id)
+	if sig == nil {
+		return fmt.Errorf("the playbook does not have a signature with the id %s", id)
+	}
+	return sig.Countersign(method, key, counter)
+}
+
+// VerifyChain - This method will verify the signature on the playbook with the
+// ID given and every countersignature nested in it, using the resolver to find
+// the public keys. The first link is the signature over the playbook and each
+// following link says which signer vouches for which. Every signature in the
+// chain must be valid, not revoked and not expired, otherwise an error for the
+// first problem is returned and no link is reported as valid.
+func (p *Playbook) VerifyChain(id string, resolver signature.KeyResolver) ([]signature.ChainLink, error) {
+	sig := p.findSignature(id)
+	if sig == nil {
+		return nil, fmt.Errorf("the playbook does not have a signature with the id %s", id)
+	}
+
+	link := signature.ChainLink{
+		SignatureID: sig.ID,
+		Signee:      sig.Signee,
+		Algorithm:   sig.Algorithm,
+		SubjectID:   p.ID,
+	}
+	key, err := resolver.ResolveKey(sig)
+	if err != nil {
+		link.Err = fmt.Errorf("%w: %s", ErrMissingKey, err)
+	} else {
+		link.Err = p.Verify(sig, key)
+	}
+
+	counters, err := sig.VerifyCountersignatures(resolver, time.Now())
+	if link.Err != nil {
+		err = fmt.Errorf("signature %s by %s: %w", sig.ID, sig.Signee, link.Err)
+		for i := range counters {
+			counters[i].Valid = false
+			if counters[i].Err == nil {
+				counters[i].Err = fmt.Errorf("the chain is broken: %w", err)
+			}
+		}
+	} else if err != nil {
+		link.Err = fmt.Errorf("the chain is broken: %w", err)
+	}
+	link.Valid = link.Err == nil
+
+	return append([]signature.ChainLink{link}, counters...), err
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// findSignature - This method returns the signature with the ID given, or nil
+// if the playbook does not have one.
+func (p *Playbook) findSignature(id string) *signature.Signature {
+	for i := range p.Signatures {
+		if p.Signatures[i].ID == id {
+			return &p.Signatures[i]
+		}
+	}
+	return nil
+}
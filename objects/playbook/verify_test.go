@@ -0,0 +1,235 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// TestVerifyExample - This will verify the signed playbook from the
+// demoVerifyRSA example using the public key embedded in the signature
+func TestVerifyExample(t *testing.T) {
+	data := []byte(`{
+  "type": "playbook",
+  "spec_version": "cacao-2.0",
+  "id": "playbook--a0777575-5c4c-4710-9f01-15776103837f",
+  "name": "Playbook 1",
+  "created_by": "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1",
+  "created": "2022-05-18T11:31:31.319Z",
+  "modified": "2022-05-18T11:31:31.319Z",
+  "signatures": [
+    {
+      "type": "jss",
+      "id": "jss--af892292-c4b4-47eb-9be6-4897ff4b9388",
+      "created_by": "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1",
+      "created": "2023-01-10T17:39:31.319Z",
+      "modified": "2023-01-10T17:39:31.319Z",
+      "signee": "ACME Cyber Company",
+      "valid_from": "2023-01-10T17:39:31.319Z",
+      "valid_until": "2023-06-10T17:39:31.319Z",
+      "related_to": "playbook--a0777575-5c4c-4710-9f01-15776103837f",
+      "related_version": "2022-05-18T11:31:31.319Z",
+      "hash_algorithm": "sha-256",
+      "algorithm": "RS256",
+      "public_key": "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAptKZyFPStvmOlb0WihOBhlHUr6wFDHC+tW7hJAudfTQ5mHZQpB8PoMz07udZA+dG8dhUIPkmXlp1TgREeYTHdhxhuf0y/GhbpZv5JPYHx3watO+HWO2qYkjRMEcrWhPMdaVkS/Xe/liaMcow4jYoWaFm8VobeYsyVD2bWWdyl4joTEETm1Z47RnnfR15kVhVudVrDzEFmM4nXV/6dmIg184RJE4httwBFxR8qZCQCwTiJmsoyJxfUR0Gs4ePKc5sB0NTkmFZc5klQSitd67RJn2ldhbqE7EpDl4XlIt+UyLJm1guCBltia8Agke7dXuhpB7hQ6LJwY4EjzthkJ8IPwIDAQAB",
+      "value": "gXyPW--uUKvGXRh_oDC7IYlx13hGmkiQw6XX3YX_V_i4Y39OPilf0q0qf46aO3IrvG-5_fgeQ3dIunmizYneaVJKjsXo-gkJczvGDehlTjS7MBUIGyD9DRd41DnaxWWwzU-T4sZCur664_3J0C-WRCDgZxiB_ItFpeSnPJ8rR8GRFO6M9YS-ejEGJl-sd_hfZUqjZOE_S_UWp_Fd9QYlP2cWDvGH8vFhx2ek9KovEob5Bh2LX_Gaf7t237B34wKeN4B8aqapyTh3Q8v_Z61bDTNLyi6ZA8zcrUOgCVLVMx_EFizx31WIDZrePsSpX4bfRurcvRyvNNVdWLaS96JYog"
+    }
+  ]
+}`)
+
+	p, err := Decode(data)
+	if err != nil {
+		t.Fatalf("1.1 unable to decode playbook: %s", err)
+	}
+
+	key, err := p.Signatures[0].ParsePublicKey()
+	if err != nil {
+		t.Fatalf("1.2 unable to parse public key: %s", err)
+	}
+
+	if err := p.Verify(&p.Signatures[0], key); err != nil {
+		t.Errorf("1.3 Verify returned an error for a valid signature: %s", err)
+	}
+
+	p.Name = "Playbook 2"
+	if err := p.Verify(&p.Signatures[0], key); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("1.4 Verify did not detect tampered content, returned %v", err)
+	}
+}
+
+// TestVerifyAll - This will test VerifyAll with several signatures, a missing
+// key, and a key that does not match the algorithm
+func TestVerifyAll(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	p := newSpecExamplePlaybook()
+
+	s1 := signature.New()
+	s1.Signee = "ACME Cyber Company"
+	s1.Algorithm = "RS256"
+	if err := p.Sign("RS256", rsaKey, s1); err != nil {
+		t.Fatalf("2.1 unable to sign playbook: %s", err)
+	}
+
+	s2 := signature.New()
+	s2.Signee = "ACME Security Company"
+	s2.Algorithm = "ES256"
+	if err := p.Sign("ES256", ecKey, s2); err != nil {
+		t.Fatalf("2.2 unable to sign playbook: %s", err)
+	}
+
+	s3 := signature.New()
+	s3.Signee = "Unknown Company"
+	s3.Algorithm = "ES256"
+	if err := p.Sign("ES256", ecKey, s3); err != nil {
+		t.Fatalf("2.3 unable to sign playbook: %s", err)
+	}
+
+	// Round trip the playbook so that verification uses the decoded form
+	data, _ := p.Encode()
+	p2, err := Decode(data)
+	if err != nil {
+		t.Fatalf("2.4 unable to decode playbook: %s", err)
+	}
+
+	keys := map[string]crypto.PublicKey{
+		s1.ID: &rsaKey.PublicKey,
+		s2.ID: &ecKey.PublicKey,
+	}
+	resolver := signature.KeyResolverFunc(func(s *signature.Signature) (crypto.PublicKey, error) {
+		if k, found := keys[s.ID]; found {
+			return k, nil
+		}
+		return nil, errors.New("unknown signee")
+	})
+
+	results := p2.VerifyAll(resolver)
+	if len(results) != 3 {
+		t.Fatalf("2.5 VerifyAll returned %d results instead of 3", len(results))
+	}
+	if !results[0].Valid || !results[1].Valid {
+		t.Errorf("2.6 VerifyAll did not verify valid signatures %+v", results)
+	}
+	if results[2].Valid || !errors.Is(results[2].Err, ErrMissingKey) {
+		t.Errorf("2.7 VerifyAll did not report a missing key %+v", results[2])
+	}
+
+	// Use the RSA key for the ECDSA signature
+	keys[s2.ID] = &rsaKey.PublicKey
+	results = p2.VerifyAll(resolver)
+	if results[1].Valid || !errors.Is(results[1].Err, ErrAlgorithmMismatch) {
+		t.Errorf("2.8 VerifyAll did not report an algorithm mismatch %+v", results[1])
+	}
+
+	// Tamper with the content
+	keys[s2.ID] = &ecKey.PublicKey
+	p2.Description = "Something else"
+	for i, r := range p2.VerifyAll(resolver)[:2] {
+		if r.Valid || !errors.Is(r.Err, ErrInvalidSignature) {
+			t.Errorf("2.%d VerifyAll did not detect tampered content %+v", 9+i, r)
+		}
+	}
+}
+
+// TestVerifyChain - This will test countersigning a signature and verifying
+// the whole chain
+func TestVerifyChain(t *testing.T) {
+	authorKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, reviewerKey, _ := ed25519.GenerateKey(rand.Reader)
+	approverKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	ring := signature.NewKeyRing()
+	ring.Add("Author", authorKey.Public())
+	ring.Add("Reviewer", reviewerKey.Public())
+	ring.Add("Approver", approverKey.Public())
+
+	// build - This will create a playbook signed by the author and countersigned
+	// by the reviewer and then the approver
+	build := func(modify func(author, reviewer *signature.Signature)) *Playbook {
+		p := newSpecExamplePlaybook()
+		author, reviewer, approver := signature.New(), signature.New(), signature.New()
+		author.Signee, reviewer.Signee, approver.Signee = "Author", "Reviewer", "Approver"
+		if modify != nil {
+			modify(author, reviewer)
+		}
+
+		if err := p.Sign("ES256", authorKey, author); err != nil {
+			t.Fatalf("unable to sign: %s", err)
+		}
+		if err := p.Countersign(author.ID, "Ed25519", reviewerKey, reviewer); err != nil {
+			t.Fatalf("unable to countersign: %s", err)
+		}
+		if err := p.Countersign(author.ID, "ES384", approverKey, approver); err != nil {
+			t.Fatalf("unable to countersign: %s", err)
+		}
+
+		// Round trip the playbook so that verification uses the decoded form
+		data, _ := p.Encode()
+		p2, err := Decode(data)
+		if err != nil {
+			t.Fatalf("unable to decode playbook: %s", err)
+		}
+		return p2
+	}
+
+	p := build(nil)
+	id := p.Signatures[0].ID
+	links, err := p.VerifyChain(id, ring)
+	if err != nil {
+		t.Fatalf("3.0 valid chain did not verify: %s", err)
+	}
+	want := [][2]string{{"Author", ""}, {"Reviewer", "Author"}, {"Approver", "Reviewer"}}
+	if len(links) != len(want) {
+		t.Fatalf("3.1 VerifyChain returned %d links instead of %d", len(links), len(want))
+	}
+	for i, w := range want {
+		if !links[i].Valid || links[i].Signee != w[0] || links[i].SubjectSignee != w[1] {
+			t.Errorf("3.2.%d link is not correct %+v", i, links[i])
+		}
+	}
+	if links[0].SubjectID != p.ID {
+		t.Errorf("3.3 the first link does not vouch for the playbook")
+	}
+	if r := p.VerifyAll(ring)[0]; !r.Valid {
+		t.Errorf("3.4 countersignatures broke the playbook signature: %s", r.Err)
+	}
+
+	// An invalid inner signature breaks the whole chain
+	p.Signatures[0].Signature.Value = p.Signatures[0].Signature.Signature.Value
+	links, err = p.VerifyChain(id, ring)
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("3.5 chain with an invalid countersignature returned %v", err)
+	}
+	for i, link := range links {
+		if link.Valid {
+			t.Errorf("3.6.%d link was valid in a broken chain", i)
+		}
+	}
+
+	// Revoked and expired signatures in the chain are rejected
+	p = build(func(author, reviewer *signature.Signature) { reviewer.Revoked = true })
+	if _, err := p.VerifyChain(p.Signatures[0].ID, ring); !errors.Is(err, signature.ErrRevoked) {
+		t.Errorf("3.7 chain with a revoked countersignature returned %v", err)
+	}
+	p = build(func(author, reviewer *signature.Signature) { author.ValidUntil = "2020-01-01T00:00:00Z" })
+	if _, err := p.VerifyChain(p.Signatures[0].ID, ring); !errors.Is(err, signature.ErrExpired) {
+		t.Errorf("3.8 chain with an expired signature returned %v", err)
+	}
+
+	if _, err := p.VerifyChain("jss--unknown", ring); err == nil {
+		t.Errorf("3.9 VerifyChain with an unknown signature ID did not return an error")
+	}
+}
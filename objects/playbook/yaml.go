@@ -0,0 +1,34 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// DecodeYAML - This function will decode a playbook written in YAML. The YAML
+// is converted to JSON and then decoded with Decode(), so it uses the same
+// property names as the JSON, and anchors, aliases and merge keys can be used
+// for repeated content like command blocks.
+func DecodeYAML(data []byte) (*Playbook, error) {
+	jsonData, err := objects.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	// The JSON is generated, so the line and column in it would not help
+	return decode(jsonData)
+}
+
+// EncodeYAML - This method will encode the playbook as YAML. The properties are
+// in the same order as Encode() uses, so a playbook that is converted between
+// YAML and JSON stays the same.
+func (p *Playbook) EncodeYAML() ([]byte, error) {
+	data, err := p.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return objects.JSONToYAML(data)
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package playbook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/markings"
+	"github.com/openplaybooks/libcacao/objects/workflow"
+)
+
+// TestYAMLRoundTrip - This will test that a playbook converted to YAML and back
+// is the same, and that the YAML is the same after a second round trip
+func TestYAMLRoundTrip(t *testing.T) {
+	p := newSpecExamplePlaybook()
+	want, _ := p.Encode()
+
+	y, err := p.EncodeYAML()
+	if err != nil {
+		t.Fatalf("1.1 EncodeYAML returned an error: %s", err)
+	}
+	p2, err := DecodeYAML(y)
+	if err != nil {
+		t.Fatalf("1.2 DecodeYAML returned an error: %s", err)
+	}
+	if got, _ := p2.Encode(); !bytes.Equal(got, want) {
+		t.Errorf("1.3 the playbook changed in the round trip\n%s\n%s", got, want)
+	}
+	if _, ok := p2.Workflow["if-condition--0a3d0e6f-39e1-4e8e-b8a0-5cc4f0b0d2d3"].(*workflow.IfStep); !ok {
+		t.Errorf("1.4 the workflow step was not decoded into its type")
+	}
+	if _, ok := p2.DataMarkingDefinitions["marking-statement--6424867b-0440-4885-bd0b-604d51786d06"].(*markings.MarkingStatement); !ok {
+		t.Errorf("1.5 the data marking was not decoded into its type")
+	}
+	if y2, _ := p2.EncodeYAML(); !bytes.Equal(y, y2) {
+		t.Errorf("1.6 the YAML changed in the round trip\n%s\n%s", y, y2)
+	}
+}
+
+// TestDecodeYAML - This will test decoding a hand written playbook that uses
+// an anchor for a repeated command block
+func TestDecodeYAML(t *testing.T) {
+	data := []byte(`
+type: playbook
+spec_version: cacao-2.0
+id: playbook--61a6c41e-6efc-4516-a242-dfbc5c89d562
+name: Contain host
+playbook_types: [mitigation]
+created_by: identity--5abe695c-7bd5-4c31-8824-2528696cdbf1
+created: 2023-02-19T08:00:24.918Z
+modified: 2023-02-19T08:00:24.918Z
+workflow_start: start--07bea005-4a36-4a77-bd1f-79a6e4682a13
+workflow:
+  start--07bea005-4a36-4a77-bd1f-79a6e4682a13:
+    type: start
+    on_completion: action--7f40f9d7-de39-4027-ab97-15035beff2ff
+  action--7f40f9d7-de39-4027-ab97-15035beff2ff:
+    type: action
+    name: Isolate the first host
+    commands: &isolate
+      - type: manual
+        command: |
+          Isolate the host from the network
+          Notify the owner
+    on_completion: action--9a3f6e29-8c5d-4f6a-9d5e-1c0b7f3a2e41
+  action--9a3f6e29-8c5d-4f6a-9d5e-1c0b7f3a2e41:
+    type: action
+    name: Isolate the second host
+    commands: *isolate
+    on_completion: end--6b23c237-ade8-4d00-9aa1-75999738d557
+  end--6b23c237-ade8-4d00-9aa1-75999738d557:
+    type: end
+data_marking_definitions:
+  marking-tlp--e828b379-4e03-4974-9ac4-e53a884c97c1:
+    type: marking-tlp
+    id: marking-tlp--e828b379-4e03-4974-9ac4-e53a884c97c1
+    created_by: identity--b3b5a57f-1f72-4e5d-bfd7-1b3d8f3e7d4a
+    created: 2022-10-01T00:00:00.000Z
+    tlpv2_level: TLP:RED
+markings:
+  - marking-tlp--e828b379-4e03-4974-9ac4-e53a884c97c1
+`)
+
+	p, err := DecodeYAML(data)
+	if err != nil {
+		t.Fatalf("2.1 DecodeYAML returned an error: %s", err)
+	}
+	if valid, count, details := p.Valid(false); !valid {
+		t.Errorf("2.2 the playbook is not valid, errors %d and results %s", count, details)
+	}
+
+	s1, ok1 := p.Workflow["action--7f40f9d7-de39-4027-ab97-15035beff2ff"].(*workflow.ActionStep)
+	s2, ok2 := p.Workflow["action--9a3f6e29-8c5d-4f6a-9d5e-1c0b7f3a2e41"].(*workflow.ActionStep)
+	if !ok1 || !ok2 || len(s2.Commands) != 1 || s1.Commands[0].Command != s2.Commands[0].Command {
+		t.Fatalf("2.3 the anchored command block was not decoded into both steps")
+	}
+	if s2.Commands[0].Command != "Isolate the host from the network\nNotify the owner\n" {
+		t.Errorf("2.4 the command is not correct %q", s2.Commands[0].Command)
+	}
+	if p.Created != "2023-02-19T08:00:24.918Z" {
+		t.Errorf("2.5 the timestamp was not decoded as a string %s", p.Created)
+	}
+
+	if _, err := DecodeYAML([]byte("type: playbook\nworkflow: [\n")); err == nil {
+		t.Errorf("2.6 DecodeYAML accepted invalid YAML")
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// PositionIndex - This type finds the line and column of JSON Pointers in a
+// JSON or YAML document. The document is only read once, when the index is
+// created, so it is much faster than JSONPointerPosition() and
+// YAMLPointerPosition() when there are many pointers to look up.
+type PositionIndex struct {
+	offsets    map[string]int
+	lineStarts []int
+	yaml       *yaml.Node
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewJSONPositionIndex - This function will read the JSON data and record
+// where every property and array entry is. If the JSON is not valid, the
+// positions before the error are kept.
+func NewJSONPositionIndex(data []byte) *PositionIndex {
+	x := &PositionIndex{offsets: make(map[string]int), lineStarts: lineStarts(data)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	x.indexJSON(dec, data, "", skipSeparators(data, 0))
+	return x
+}
+
+// NewYAMLPositionIndex - This function will parse the YAML data so that the
+// positions can be looked up without parsing it again. If the YAML is not
+// valid, no positions are found.
+func NewYAMLPositionIndex(data []byte) *PositionIndex {
+	x := &PositionIndex{}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err == nil && len(doc.Content) > 0 {
+		x.yaml = doc.Content[0]
+	}
+	return x
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// JSONPointerPosition - This function will return the line and column in the
+// JSON data of the property that the JSON Pointer (RFC 6901) refers to. For a
+// property of an object this is where its name starts, and for an entry in an
+// array where the entry starts. Both start at 1. If the pointer is not found
+// in the data, ok is false.
+func JSONPointerPosition(data []byte, pointer string) (line, column int, ok bool) {
+	pos, _, ok := jsonPointerOffsets(data, pointer)
+	if !ok {
+		return 0, 0, false
+	}
+	line, column = position(data, pos)
+	return line, column, true
+}
+
+// ----------------------------------------------------------------------
+// Public PositionIndex Methods
+// ----------------------------------------------------------------------
+
+// Position - This method will return the line and column of the property that
+// the JSON Pointer refers to, the same as JSONPointerPosition() or
+// YAMLPointerPosition(). If the pointer is not found, ok is false.
+func (x *PositionIndex) Position(pointer string) (line, column int, ok bool) {
+	if x.offsets == nil {
+		return yamlPointerPosition(x.yaml, pointer)
+	}
+
+	offset, found := x.offsets[pointer]
+	if !found {
+		return 0, 0, false
+	}
+	i := sort.SearchInts(x.lineStarts, offset+1) - 1
+	return i + 1, offset - x.lineStarts[i] + 1, true
+}
+
+// ----------------------------------------------------------------------
+// Private PositionIndex Methods
+// ----------------------------------------------------------------------
+
+// indexJSON - This method will record the offset of the value that is next in
+// the decoder, and of everything in it, under the JSON Pointer. The offset is
+// where the property name or array entry starts. If a name is repeated the
+// first one is kept, like JSONPointerPosition().
+func (x *PositionIndex) indexJSON(dec *json.Decoder, data []byte, pointer string, at int) bool {
+	if at >= len(data) {
+		return false
+	}
+	if _, found := x.offsets[pointer]; !found {
+		x.offsets[pointer] = at
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			before := int(dec.InputOffset())
+			key, err := dec.Token()
+			if err != nil {
+				return false
+			}
+			if !x.indexJSON(dec, data, pointer+JSONPointer(key.(string)), skipSeparators(data, before)) {
+				return false
+			}
+		}
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if !x.indexJSON(dec, data, pointer+"/"+strconv.Itoa(i), skipSeparators(data, int(dec.InputOffset()))) {
+				return false
+			}
+		}
+	default:
+		return true
+	}
+
+	// Read the closing delimiter
+	_, err = dec.Token()
+	return err == nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// lineStarts - This function will return the offset where each line in the
+// data starts.
+func lineStarts(data []byte) []int {
+	starts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// jsonPointerOffsets - This function will return the offsets in the JSON data
+// of the property that the JSON Pointer refers to and of its value. For an
+// entry in an array, or the whole document, they are the same.
+func jsonPointerOffsets(data []byte, pointer string) (at, value int, ok bool) {
+	tokens, ok := splitPointer(pointer)
+	if !ok {
+		return 0, 0, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	at = skipSeparators(data, 0)
+	value = at
+
+	for _, t := range tokens {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+
+		found := false
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				before := int(dec.InputOffset())
+				key, err := dec.Token()
+				if err != nil {
+					return 0, 0, false
+				}
+				if key == t {
+					at, found = skipSeparators(data, before), true
+					value = skipSeparators(data, int(dec.InputOffset()))
+					break
+				}
+				if skipJSONValue(dec) != nil {
+					return 0, 0, false
+				}
+			}
+		case json.Delim('['):
+			index, err := strconv.Atoi(t)
+			if err != nil {
+				return 0, 0, false
+			}
+			for i := 0; dec.More(); i++ {
+				if i == index {
+					at, found = skipSeparators(data, int(dec.InputOffset())), true
+					value = at
+					break
+				}
+				if skipJSONValue(dec) != nil {
+					return 0, 0, false
+				}
+			}
+		}
+		if !found {
+			return 0, 0, false
+		}
+	}
+
+	if at >= len(data) || value >= len(data) {
+		return 0, 0, false
+	}
+	return at, value, true
+}
+
+// splitPointer - This function will split a JSON Pointer into its unescaped
+// reference tokens.
+func splitPointer(pointer string) ([]string, bool) {
+	if pointer == "" {
+		return nil, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		tokens[i] = strings.ReplaceAll(t, "~0", "~")
+	}
+	return tokens, true
+}
+
+// skipJSONValue - This function will read the next value from the decoder,
+// including everything in it if it is an object or array.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// skipSeparators - This function will return the offset of the first byte at
+// or after the offset that is not whitespace or a separator. The decoder
+// offset is just after the previous token, before any comma or colon.
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// position - This function will return the line and column of the byte at the
+// offset in the data. Both start at 1.
+func position(data []byte, offset int) (line, column int) {
+	prefix := data[:offset]
+	line = bytes.Count(prefix, []byte("\n")) + 1
+	column = len(prefix) - bytes.LastIndexByte(prefix, '\n')
+	return line, column
+}
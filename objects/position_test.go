@@ -0,0 +1,86 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"testing"
+)
+
+// TestPointerPosition - This will test finding the line and column of JSON
+// Pointers in JSON and YAML documents
+func TestPointerPosition(t *testing.T) {
+	jsonData := []byte(`{
+  "name": "x",
+  "a/b": {"c": [1, {"d": true}]},
+  "workflow": {
+    "start--1": {
+      "type": "start"
+    }
+  }
+}`)
+	yamlData := []byte(`name: x
+a/b:
+  c:
+    - 1
+    - d: true
+defaults: &defaults
+  type: start
+workflow:
+  start--1:
+    <<: *defaults
+`)
+
+	tests := []struct {
+		pointer    string
+		jsonLine   int
+		jsonColumn int
+		yamlLine   int
+		yamlColumn int
+	}{
+		{"", 1, 1, 1, 1},
+		{"/name", 2, 3, 1, 1},
+		{"/a~1b/c", 3, 11, 3, 3},
+		{"/a~1b/c/1/d", 3, 21, 5, 7},
+		{"/workflow/start--1/type", 6, 7, 7, 3},
+	}
+	jsonIndex := NewJSONPositionIndex(jsonData)
+	yamlIndex := NewYAMLPositionIndex(yamlData)
+	for i, test := range tests {
+		line, column, ok := JSONPointerPosition(jsonData, test.pointer)
+		if !ok || line != test.jsonLine || column != test.jsonColumn {
+			t.Errorf("1.%d JSONPointerPosition(%s) returned %d %d %t", i, test.pointer, line, column, ok)
+		}
+		line, column, ok = YAMLPointerPosition(yamlData, test.pointer)
+		if !ok || line != test.yamlLine || column != test.yamlColumn {
+			t.Errorf("2.%d YAMLPointerPosition(%s) returned %d %d %t", i, test.pointer, line, column, ok)
+		}
+
+		// The index gives the same positions
+		line, column, ok = jsonIndex.Position(test.pointer)
+		if !ok || line != test.jsonLine || column != test.jsonColumn {
+			t.Errorf("5.%d the JSON index returned %d %d %t for %s", i, line, column, ok, test.pointer)
+		}
+		line, column, ok = yamlIndex.Position(test.pointer)
+		if !ok || line != test.yamlLine || column != test.yamlColumn {
+			t.Errorf("6.%d the YAML index returned %d %d %t for %s", i, line, column, ok, test.pointer)
+		}
+	}
+
+	for i, pointer := range []string{"/missing", "/a~1b/c/5", "/name/x", "name"} {
+		if _, _, ok := JSONPointerPosition(jsonData, pointer); ok {
+			t.Errorf("3.%d JSONPointerPosition found %s", i, pointer)
+		}
+		if _, _, ok := YAMLPointerPosition(yamlData, pointer); ok {
+			t.Errorf("4.%d YAMLPointerPosition found %s", i, pointer)
+		}
+		if _, _, ok := jsonIndex.Position(pointer); ok {
+			t.Errorf("7.%d the JSON index found %s", i, pointer)
+		}
+		if _, _, ok := yamlIndex.Position(pointer); ok {
+			t.Errorf("8.%d the YAML index found %s", i, pointer)
+		}
+	}
+}
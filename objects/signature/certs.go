@@ -0,0 +1,184 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// Define Interfaces
+// ----------------------------------------------------------------------
+
+// CertificateSigner - This interface is a Signer that also has a certificate
+// chain for its key. Playbook.Sign() will add the chain and its thumbprint to
+// the signature object.
+type CertificateSigner interface {
+	Signer
+	CertificateChain() []*x509.Certificate
+}
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// CertResolver - This type is a KeyResolver that only trusts signatures with a
+// certificate chain that leads to one of the roots. The chain is checked at
+// the time the signature was created, so signatures stay valid after the
+// certificate expires. The leaf certificate must allow digital signatures and
+// one of the extended key usages. If KeyUsages is empty, code signing is
+// required, not server authentication like x509.VerifyOptions.
+type CertResolver struct {
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+	KeyUsages     []x509.ExtKeyUsage
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewCertResolver - This function will create a new certificate resolver that
+// trusts the roots and requires the code signing extended key usage.
+func NewCertResolver(roots *x509.CertPool) *CertResolver {
+	return &CertResolver{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+}
+
+// ----------------------------------------------------------------------
+// Public CertResolver Methods
+// ----------------------------------------------------------------------
+
+// ResolveKey - This method implements KeyResolver. It builds and validates
+// the certificate chain of the signature and returns the public key of the
+// leaf certificate. The thumbprint and the public_key property, if they are
+// present, must match the leaf certificate.
+func (c *CertResolver) ResolveKey(s *Signature) (crypto.PublicKey, error) {
+	if c.Roots == nil {
+		return nil, errors.New("no trusted roots were given")
+	}
+
+	chain, err := s.ParseCertificateChain()
+	if err != nil {
+		return nil, err
+	}
+	leaf := chain[0]
+
+	if s.Thumbprint != "" && subtle.ConstantTimeCompare([]byte(strings.ToLower(s.Thumbprint)), []byte(Thumbprint(leaf))) != 1 {
+		return nil, errors.New("the thumbprint does not match the leaf certificate")
+	}
+
+	if s.PublicKey != "" {
+		key, err := s.ParsePublicKey()
+		if err != nil {
+			return nil, err
+		}
+		k, ok := key.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !k.Equal(leaf.PublicKey) {
+			return nil, errors.New("the public key does not match the leaf certificate")
+		}
+	}
+
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&(x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment) == 0 {
+		return nil, errors.New("the leaf certificate does not allow digital signatures")
+	}
+
+	signed := time.Now()
+	if s.Created != "" {
+		signed, err = time.Parse(time.RFC3339, s.Created)
+		if err != nil {
+			return nil, fmt.Errorf("the created timestamp is not valid: %w", err)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	if c.Intermediates != nil {
+		intermediates = c.Intermediates.Clone()
+	}
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	keyUsages := c.KeyUsages
+	if len(keyUsages) == 0 {
+		keyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         c.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   signed,
+		KeyUsages:     keyUsages,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaf.PublicKey, nil
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// Thumbprint - This function returns the hex encoded SHA-256 hash of the DER
+// encoding of the certificate.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// ----------------------------------------------------------------------
+// Public Signature Type Methods
+// ----------------------------------------------------------------------
+
+// SetCertificateChain - This method will store the certificate chain, with the
+// leaf certificate first, in the public_cert_chain property as base64 encoded
+// DER values, like the JWS x5c header, and set the thumbprint to the SHA-256
+// thumbprint of the leaf certificate.
+func (s *Signature) SetCertificateChain(chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("the certificate chain is empty")
+	}
+
+	s.PublicCertChain = nil
+	for _, cert := range chain {
+		s.PublicCertChain = append(s.PublicCertChain, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	s.Thumbprint = Thumbprint(chain[0])
+	return nil
+}
+
+// ParseCertificateChain - This method will decode the public_cert_chain
+// property. The leaf certificate is first.
+func (s *Signature) ParseCertificateChain() ([]*x509.Certificate, error) {
+	if len(s.PublicCertChain) == 0 {
+		return nil, errors.New("the signature does not contain a certificate chain")
+	}
+
+	var chain []*x509.Certificate
+	for i, value := range s.PublicCertChain {
+		der, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d in the chain is not valid base64: %w", i, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d in the chain is not valid: %w", i, err)
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCert - This will create a certificate signed by the parent, or a self
+// signed certificate if the parent is nil
+func newTestCert(t *testing.T, name string, parent *x509.Certificate, parentKey crypto.Signer, template x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.Subject = pkix.Name{CommonName: name}
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+	}
+	if parent == nil {
+		parent, parentKey = &template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate %s: %s", name, err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// TestCertResolver - This will test building and validating certificate
+// chains
+func TestCertResolver(t *testing.T) {
+	ca := x509.Certificate{
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		NotBefore:             time.Now().Add(-72 * time.Hour),
+		NotAfter:              time.Now().Add(72 * time.Hour),
+	}
+	root, rootKey := newTestCert(t, "Root", nil, nil, ca)
+	inter, interKey := newTestCert(t, "Intermediate", root, rootKey, ca)
+
+	codeSigning := x509.Certificate{
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leaf, leafKey := newTestCert(t, "Signer", inter, interKey, codeSigning)
+
+	serverAuth := codeSigning
+	serverAuth.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverLeaf, _ := newTestCert(t, "Server", inter, interKey, serverAuth)
+
+	noSign := codeSigning
+	noSign.KeyUsage = x509.KeyUsageKeyEncipherment
+	noSignLeaf, _ := newTestCert(t, "Encipher", inter, interKey, noSign)
+
+	past := codeSigning
+	past.NotBefore = time.Now().Add(-48 * time.Hour)
+	past.NotAfter = time.Now().Add(-24 * time.Hour)
+	expiredLeaf, _ := newTestCert(t, "Expired", inter, interKey, past)
+
+	otherRoot, _ := newTestCert(t, "Other Root", nil, nil, ca)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	resolver := NewCertResolver(roots)
+
+	tests := []struct {
+		name    string
+		chain   []*x509.Certificate
+		created time.Time
+		modify  func(s *Signature)
+		valid   bool
+	}{
+		{"valid chain", []*x509.Certificate{leaf, inter}, time.Now(), nil, true},
+		{"missing intermediate", []*x509.Certificate{leaf}, time.Now(), nil, false},
+		{"signed while the certificate was valid", []*x509.Certificate{expiredLeaf, inter}, time.Now().Add(-36 * time.Hour), nil, true},
+		{"signed after the certificate expired", []*x509.Certificate{expiredLeaf, inter}, time.Now(), nil, false},
+		{"server authentication only", []*x509.Certificate{serverLeaf, inter}, time.Now(), nil, false},
+		{"no digital signature key usage", []*x509.Certificate{noSignLeaf, inter}, time.Now(), nil, false},
+		{"untrusted root", []*x509.Certificate{otherRoot}, time.Now(), nil, false},
+		{"wrong thumbprint", []*x509.Certificate{leaf, inter}, time.Now(), func(s *Signature) { s.Thumbprint = Thumbprint(inter) }, false},
+		{"wrong public key", []*x509.Certificate{leaf, inter}, time.Now(), func(s *Signature) { s.SetPublicKey(inter.PublicKey) }, false},
+		{"matching public key", []*x509.Certificate{leaf, inter}, time.Now(), func(s *Signature) { s.SetPublicKey(leafKey.Public()) }, true},
+	}
+
+	for i, test := range tests {
+		s := New()
+		s.SetCreated(test.created)
+		s.SetCertificateChain(test.chain)
+		if test.modify != nil {
+			test.modify(s)
+		}
+
+		key, err := resolver.ResolveKey(s)
+		if test.valid && err != nil {
+			t.Errorf("1.%d %s was not accepted: %s", i, test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("1.%d %s was accepted", i, test.name)
+		}
+		if test.valid && err == nil && !leafKey.PublicKey.Equal(key) && test.chain[0] == leaf {
+			t.Errorf("1.%d %s did not return the leaf key", i, test.name)
+		}
+	}
+
+	// A resolver that does not set the key usages requires code signing
+	zero := &CertResolver{Roots: roots}
+	for i, chain := range [][]*x509.Certificate{{leaf, inter}, {serverLeaf, inter}} {
+		s := New()
+		s.SetCertificateChain(chain)
+		if _, err := zero.ResolveKey(s); (err == nil) != (i == 0) {
+			t.Errorf("1.%d a resolver without key usages returned %v for %s", len(tests)+i, err, chain[0].Subject.CommonName)
+		}
+	}
+
+	// The chain must round trip through the signature object
+	s := New()
+	s.SetCertificateChain([]*x509.Certificate{leaf, inter})
+	chain, err := s.ParseCertificateChain()
+	if err != nil || len(chain) != 2 || !chain[0].Equal(leaf) || !chain[1].Equal(inter) {
+		t.Errorf("2.0 the certificate chain did not round trip")
+	}
+
+	signer, _ := NewKeySigner("ES256", leafKey)
+	if err := signer.SetCertificateChain([]*x509.Certificate{inter}); err == nil {
+		t.Errorf("2.1 a certificate for a different key was accepted for the signer")
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// These errors are returned, possibly wrapped, when a signature in a chain can
+// not be trusted because of its status.
+var (
+	ErrRevoked     = errors.New("the signature has been revoked")
+	ErrNotYetValid = errors.New("the signature is not valid yet")
+	ErrExpired     = errors.New("the signature has expired")
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// ChainLink - This type captures the result of verifying one link in a
+// signature chain, where the signer vouches for the subject. For the first
+// link of a playbook chain the subject is the playbook, for the others it is
+// the signature that was countersigned.
+type ChainLink struct {
+	SignatureID   string
+	Signee        string
+	Algorithm     string
+	SubjectID     string
+	SubjectSignee string
+	Valid         bool
+	Err           error
+}
+
+// ----------------------------------------------------------------------
+// Public Signature Type Methods
+// ----------------------------------------------------------------------
+
+// Chain - This method returns the signature followed by each of its nested
+// countersignatures, from the innermost signer outward.
+func (s *Signature) Chain() []*Signature {
+	var chain []*Signature
+	for c := s; c != nil; c = c.Signature {
+		chain = append(chain, c)
+	}
+	return chain
+}
+
+// Countersign - This method will sign the last signature in the chain with the
+// counter signature object, using the signing method and key, and nest the
+// counter signature in it. This is how a reviewer vouches for the signature
+// of an author, and it can be repeated to build a longer chain. The key can be
+// a private key or a Signer.
+func (s *Signature) Countersign(method string, key interface{}, counter *Signature) error {
+	if counter == nil {
+		return errors.New("no counter signature was given")
+	}
+	if counter.Signature != nil {
+		return errors.New("the counter signature already has a nested signature")
+	}
+
+	chain := s.Chain()
+	target := chain[len(chain)-1]
+	if target.Value == "" {
+		return fmt.Errorf("%w: the signature %s has not been signed", ErrMissingValue, target.ID)
+	}
+
+	if err := counter.PrepareSigning(method, key); err != nil {
+		return err
+	}
+
+	hash, err := countersigningHash(*target, *counter)
+	if err != nil {
+		return err
+	}
+
+	value, err := SignHash(method, key, hash)
+	if err != nil {
+		return err
+	}
+
+	counter.Value = value
+	target.Signature = counter
+	return nil
+}
+
+// VerifyCountersignatures - This method will verify each countersignature in
+// the chain against the signature it signs, using the resolver to find the
+// public keys. Every signature in the chain, including this one, must not be
+// revoked and must be within its validity window at the time given. It
+// returns a link for each countersignature and an error for the first link
+// that is not valid.
+func (s *Signature) VerifyCountersignatures(resolver KeyResolver, at time.Time) ([]ChainLink, error) {
+	chain := s.Chain()
+	var links []ChainLink
+	var first error
+
+	if err := s.CheckStatus(at); err != nil {
+		first = fmt.Errorf("signature %s: %w", s.ID, err)
+	}
+
+	for i := 1; i < len(chain); i++ {
+		subject, counter := chain[i-1], chain[i]
+		link := ChainLink{
+			SignatureID:   counter.ID,
+			Signee:        counter.Signee,
+			Algorithm:     counter.Algorithm,
+			SubjectID:     subject.ID,
+			SubjectSignee: subject.Signee,
+		}
+
+		link.Err = counter.CheckStatus(at)
+		if link.Err == nil {
+			key, err := resolver.ResolveKey(counter)
+			if err != nil {
+				link.Err = fmt.Errorf("%w: %s", ErrMissingKey, err)
+			} else {
+				link.Err = VerifyCountersignature(subject, counter, key)
+			}
+		}
+
+		link.Valid = link.Err == nil
+		if !link.Valid && first == nil {
+			first = fmt.Errorf("countersignature %s by %s: %w", counter.ID, counter.Signee, link.Err)
+		}
+		links = append(links, link)
+	}
+
+	// A link can only be trusted if everything it vouches for can be trusted
+	if first != nil {
+		for i := range links {
+			if links[i].Valid {
+				links[i].Valid = false
+				links[i].Err = fmt.Errorf("the chain is broken: %w", first)
+			}
+		}
+	}
+	return links, first
+}
+
+// CheckStatus - This method returns an error if the signature is revoked or if
+// the time is outside of the valid_from and valid_until window.
+func (s *Signature) CheckStatus(at time.Time) error {
+	if s.Revoked {
+		return ErrRevoked
+	}
+	if s.ValidFrom != "" {
+		from, err := time.Parse(time.RFC3339, s.ValidFrom)
+		if err != nil {
+			return fmt.Errorf("the valid_from timestamp is not valid: %w", err)
+		}
+		if at.Before(from) {
+			return ErrNotYetValid
+		}
+	}
+	if s.ValidUntil != "" {
+		until, err := time.Parse(time.RFC3339, s.ValidUntil)
+		if err != nil {
+			return fmt.Errorf("the valid_until timestamp is not valid: %w", err)
+		}
+		if at.After(until) {
+			return ErrExpired
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// VerifyCountersignature - This function will verify that the counter
+// signature signs the subject signature with the public key. It returns nil if
+// the countersignature is valid.
+func VerifyCountersignature(subject, counter *Signature, key crypto.PublicKey) error {
+	hash, err := countersigningHash(*subject, *counter)
+	if err != nil {
+		return err
+	}
+	return VerifyHash(counter.Algorithm, hash, counter.Value, key)
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// countersigningHash - This function will build the form of the subject
+// signature that is countersigned and return the hex encoded SHA-256 hash of
+// the JCS version of it. The signed form is the subject signature, with its
+// value, and only the counter signature, without its value or any further
+// countersignatures, nested in it.
+func countersigningHash(subject, counter Signature) (string, error) {
+	counter.Value = ""
+	counter.Signature = nil
+	subject.Signature = &counter
+
+	data, err := subject.Encode()
+	if err != nil {
+		return "", err
+	}
+	return HashJSON(data)
+}
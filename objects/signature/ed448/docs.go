@@ -0,0 +1,11 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package ed448 implements the Ed448 signature algorithm from RFC 8032. The
+// curve arithmetic is provided by the constant time implementation in
+// github.com/cloudflare/circl. The API mirrors the crypto/ed25519 package so it
+// can be used in the same places, for example a PrivateKey implements
+// crypto.Signer.
+package ed448
@@ -0,0 +1,193 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package ed448
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strconv"
+
+	circl "github.com/cloudflare/circl/sign/ed448"
+)
+
+const (
+	// PublicKeySize is the size, in bytes, of public keys as used in this package.
+	PublicKeySize = 57
+	// PrivateKeySize is the size, in bytes, of private keys as used in this package.
+	PrivateKeySize = 114
+	// SignatureSize is the size, in bytes, of signatures generated and verified by this package.
+	SignatureSize = 114
+	// SeedSize is the size, in bytes, of private key seeds.
+	SeedSize = 57
+	// ContextMaxSize is the maximum size, in bytes, of a context string.
+	ContextMaxSize = 255
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// PublicKey - This type is the type of Ed448 public keys.
+type PublicKey []byte
+
+// PrivateKey - This type is the type of Ed448 private keys. The first 57 bytes
+// are the seed and the last 57 bytes are the public key.
+type PrivateKey []byte
+
+// Options - This type can be used with PrivateKey.Sign() to set the context
+// string. Ed448 does not support signing a pre-hashed message, so the hash
+// function must be zero.
+type Options struct {
+	Context string
+}
+
+// HashFunc - This method returns zero to tell callers that the message must
+// not be hashed before it is signed.
+func (o *Options) HashFunc() crypto.Hash {
+	return crypto.Hash(0)
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// GenerateKey - This function generates a public/private key pair using
+// entropy from rand. If rand is nil, crypto/rand.Reader will be used.
+func GenerateKey(random io.Reader) (PublicKey, PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+
+	privateKey := NewKeyFromSeed(seed)
+	publicKey := make([]byte, PublicKeySize)
+	copy(publicKey, privateKey[SeedSize:])
+	return publicKey, privateKey, nil
+}
+
+// NewKeyFromSeed - This function calculates a private key from a seed. It
+// will panic if len(seed) is not SeedSize.
+func NewKeyFromSeed(seed []byte) PrivateKey {
+	if l := len(seed); l != SeedSize {
+		panic("ed448: bad seed length: " + strconv.Itoa(l))
+	}
+
+	privateKey := make([]byte, PrivateKeySize)
+	copy(privateKey, circl.NewKeyFromSeed(seed))
+	return privateKey
+}
+
+// Sign - This function signs the message with the private key and returns a
+// signature. It will panic if len(privateKey) is not PrivateKeySize.
+func Sign(privateKey PrivateKey, message []byte) []byte {
+	return sign(privateKey, message, "")
+}
+
+// SignWithContext - This function signs the message with the private key and
+// the context string and returns a signature.
+func SignWithContext(privateKey PrivateKey, message []byte, context string) ([]byte, error) {
+	if len(context) > ContextMaxSize {
+		return nil, errors.New("ed448: bad context length")
+	}
+	return sign(privateKey, message, context), nil
+}
+
+// Verify - This function reports whether sig is a valid signature of the
+// message by the public key.
+func Verify(publicKey PublicKey, message, sig []byte) bool {
+	return verify(publicKey, message, sig, "")
+}
+
+// VerifyWithContext - This function reports whether sig is a valid signature
+// of the message and context string by the public key.
+func VerifyWithContext(publicKey PublicKey, message, sig []byte, context string) bool {
+	if len(context) > ContextMaxSize {
+		return false
+	}
+	return verify(publicKey, message, sig, context)
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Equal - This method reports whether the public key has the same value as x.
+func (pub PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(PublicKey)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(pub, xx)
+}
+
+// Public - This method returns the public key that corresponds to the private
+// key.
+func (priv PrivateKey) Public() crypto.PublicKey {
+	publicKey := make([]byte, PublicKeySize)
+	copy(publicKey, priv[SeedSize:])
+	return PublicKey(publicKey)
+}
+
+// Seed - This method returns the private key seed.
+func (priv PrivateKey) Seed() []byte {
+	seed := make([]byte, SeedSize)
+	copy(seed, priv[:SeedSize])
+	return seed
+}
+
+// Equal - This method reports whether the private key has the same value as x.
+func (priv PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(PrivateKey)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(priv, xx)
+}
+
+// Sign - This method implements crypto.Signer. The message must not be hashed
+// and opts.HashFunc() must return zero. An *Options value can be used to set
+// the context string.
+func (priv PrivateKey) Sign(random io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed448: cannot sign hashed message")
+	}
+
+	context := ""
+	if o, ok := opts.(*Options); ok {
+		context = o.Context
+	}
+	return SignWithContext(priv, message, context)
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// sign - This function implements the signing procedure from RFC 8032
+// section 5.2.6. The curve arithmetic is done by the circl package which runs
+// in constant time with respect to the private key.
+func sign(privateKey PrivateKey, message []byte, context string) []byte {
+	if l := len(privateKey); l != PrivateKeySize {
+		panic("ed448: bad private key length: " + strconv.Itoa(l))
+	}
+	return circl.Sign(circl.PrivateKey(privateKey), message, context)
+}
+
+// verify - This function implements the verification procedure from RFC 8032
+// section 5.2.7.
+func verify(publicKey PublicKey, message, sig []byte, context string) bool {
+	if len(publicKey) != PublicKeySize || len(sig) != SignatureSize {
+		return false
+	}
+	return circl.Verify(circl.PublicKey(publicKey), message, sig, context)
+}
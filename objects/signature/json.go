@@ -7,6 +7,19 @@ package signature
 
 import "encoding/json"
 
+// Decode - This function is a simple wrapper for decoding JSON data. It will
+// decode a slice of bytes into an actual struct and return a pointer to that
+// object along with any errors.
+func Decode(data []byte) (*Signature, error) {
+	var s Signature
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
 // Encode - This method is a simple wrapper for encoding an object into JSON
 func (s *Signature) Encode() ([]byte, error) {
 	data, err := json.MarshalIndent(s, "", "  ")
@@ -0,0 +1,136 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// ErrInvalidJWS is returned, possibly wrapped, when a JWS can not be parsed or
+// does not describe a CACAO signature.
+var ErrInvalidJWS = errors.New("the JWS is not a valid detached CACAO signature")
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// JWSHeader - This type defines the protected header of a detached JWS. The
+// signature object, without its value, is carried in the cacao_jss header
+// parameter so that it can be attached to the playbook when it is imported.
+type JWSHeader struct {
+	Algorithm string     `json:"alg"`
+	KeyID     string     `json:"kid,omitempty"`
+	Signature *Signature `json:"cacao_jss,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// JOSEAlgorithm - This function will return the name that JOSE (RFC 7518 and
+// RFC 8037) uses for a CACAO signing method. The EdDSA methods are called
+// "EdDSA" and the curve comes from the key, all other names are the same.
+func JOSEAlgorithm(method string) string {
+	switch method {
+	case "Ed25519", "Ed448":
+		return "EdDSA"
+	}
+	return method
+}
+
+// IsDetachedJWS - This function will return true if the signature value is a
+// compact JWS with a detached payload (RFC 7515 appendix F) instead of a
+// base64url encoded signature. Base64url never contains a period so the two
+// forms can not be confused.
+func IsDetachedJWS(value string) bool {
+	parts := strings.Split(value, ".")
+	return len(parts) == 3 && parts[0] != "" && parts[1] == "" && parts[2] != ""
+}
+
+// ParseDetachedJWS - This function will parse the protected header of a
+// compact JWS with a detached payload. It does not verify the signature.
+func ParseDetachedJWS(jws string) (*JWSHeader, error) {
+	if !IsDetachedJWS(jws) {
+		return nil, fmt.Errorf("%w: it is not in the compact form with a detached payload", ErrInvalidJWS)
+	}
+
+	data, err := jwt.DecodeSegment(strings.Split(jws, ".")[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJWS, err)
+	}
+
+	var header JWSHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJWS, err)
+	}
+	if header.Algorithm == "" {
+		return nil, fmt.Errorf("%w: the alg header is missing", ErrInvalidJWS)
+	}
+	return &header, nil
+}
+
+// VerifyDetachedJWS - This function will verify a compact JWS with a detached
+// payload against the payload with the public key. The signature is checked
+// over the JWS signing input, like any JOSE tool does, so a JWS created by
+// DetachedJWS() or by other JOSE tools can be verified. The alg header must
+// match the signing method, like "ES256", of the signature object. It returns
+// nil if the signature is valid.
+func VerifyDetachedJWS(method, jws string, payload []byte, key crypto.PublicKey) error {
+	header, err := ParseDetachedJWS(jws)
+	if err != nil {
+		return err
+	}
+	if header.Algorithm != JOSEAlgorithm(method) {
+		return fmt.Errorf("%w: the alg header %s does not match the algorithm %s", ErrInvalidJWS, header.Algorithm, method)
+	}
+
+	parts := strings.Split(jws, ".")
+	signingInput := parts[0] + "." + jwt.EncodeSegment(payload)
+	return VerifyHash(method, signingInput, parts[2], key)
+}
+
+// ----------------------------------------------------------------------
+// Public Signature Type Methods
+// ----------------------------------------------------------------------
+
+// DetachedJWS - This method will sign the payload with the key and return a
+// compact JWS with a detached payload (RFC 7515 appendix F). The protected
+// header has the JOSE name of the algorithm, the signature ID as the key ID,
+// and the signature object without its value or countersignatures. As RFC 7515
+// requires, the signature is over the JWS signing input, the encoded header
+// and the encoded payload joined by a period, so JOSE tools can verify it with
+// the payload. The key can be a private key or a Signer.
+func (s *Signature) DetachedJWS(key interface{}, payload []byte) (string, error) {
+	if s.Algorithm == "" {
+		return "", fmt.Errorf("%w: the algorithm property is empty", ErrUnsupportedAlgorithm)
+	}
+
+	sig := *s
+	sig.Value = ""
+	sig.Signature = nil
+	header := JWSHeader{
+		Algorithm: JOSEAlgorithm(s.Algorithm),
+		KeyID:     s.ID,
+		Signature: &sig,
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	protected := jwt.EncodeSegment(data)
+	value, err := SignHash(s.Algorithm, key, protected+"."+jwt.EncodeSegment(payload))
+	if err != nil {
+		return "", err
+	}
+	return protected + ".." + value, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openplaybooks/libcacao/objects/signature/ed448"
+	"github.com/openplaybooks/libcacao/objects/signature/lms"
+	"github.com/openplaybooks/libcacao/objects/signature/xmss"
+)
+
+// oidEd448 - This is the algorithm identifier for Ed448 keys from RFC 8410.
+// The x509 package does not know about Ed448 so these keys are handled here.
+var oidEd448 = asn1.ObjectIdentifier{1, 3, 101, 113}
+
+// subjectPublicKeyInfo - This type is the ASN.1 structure of a PKIX public key.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// ----------------------------------------------------------------------
+// Define Interfaces
+// ----------------------------------------------------------------------
+
+// KeyResolver - This interface defines an object that can find the public key
+// that should be used to verify a signature. A resolver should return an error
+// if it does not have a key for the signature.
+type KeyResolver interface {
+	ResolveKey(s *Signature) (crypto.PublicKey, error)
+}
+
+// KeyResolverFunc - This type allows an ordinary function to be used as a
+// KeyResolver.
+type KeyResolverFunc func(s *Signature) (crypto.PublicKey, error)
+
+// ResolveKey - This method calls the function.
+func (f KeyResolverFunc) ResolveKey(s *Signature) (crypto.PublicKey, error) {
+	return f(s)
+}
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// KeyRing - This type is a KeyResolver that holds public keys in memory and
+// finds them by the signee of the signature. A KeyRing is safe for concurrent
+// use.
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewKeyRing - This function will create a new empty key ring.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: make(map[string]crypto.PublicKey)}
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// ParsePEMPublicKey - This function will decode the first public key or
+// certificate in the PEM data and return the public key.
+func ParsePEMPublicKey(data []byte) (crypto.PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no public key was found in the PEM data")
+		}
+
+		switch block.Type {
+		case "PUBLIC KEY":
+			return parsePKIXPublicKey(block.Bytes)
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return cert.PublicKey, nil
+		}
+	}
+}
+
+// ----------------------------------------------------------------------
+// Public KeyRing Methods
+// ----------------------------------------------------------------------
+
+// Add - This method will add the public key for the signee, replacing any key
+// that the signee already had.
+func (k *KeyRing) Add(signee string, key crypto.PublicKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[signee] = key
+}
+
+// AddPEMFile - This method will add the public key or certificate in a PEM
+// file for the signee.
+func (k *KeyRing) AddPEMFile(signee, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	key, err := ParsePEMPublicKey(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	k.Add(signee, key)
+	return nil
+}
+
+// ResolveKey - This method implements KeyResolver by returning the key for the
+// signee of the signature.
+func (k *KeyRing) ResolveKey(s *Signature) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[s.Signee]
+	if !ok {
+		return nil, fmt.Errorf("no key is known for the signee %q", s.Signee)
+	}
+	return key, nil
+}
+
+// ----------------------------------------------------------------------
+// Public Signature Type Methods
+// ----------------------------------------------------------------------
+
+// ParsePublicKey - This method will decode the public_key property, which is a
+// base64 encoded DER (PKIX) public key, and return the key. Keys with and
+// without base64 padding are accepted. For the LMS and XMSS algorithms the
+// property holds the public key in the format from RFC 8554 or RFC 8391
+// instead. The key is only as trustworthy as the
+// signature that carries it, so callers should normally resolve keys from a
+// trusted source instead.
+func (s *Signature) ParsePublicKey() (crypto.PublicKey, error) {
+	if s.PublicKey == "" {
+		return nil, errors.New("the signature does not contain a public key")
+	}
+
+	der, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(s.PublicKey, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(s.Algorithm, "LMS_"):
+		return lms.ParsePublicKey(der)
+	case strings.HasPrefix(s.Algorithm, "XMSS-"):
+		return xmss.ParsePublicKey(der)
+	}
+
+	return parsePKIXPublicKey(der)
+}
+
+// SetPublicKey - This method will encode the public key as a base64 encoded
+// DER (PKIX) public key, without padding, and store it in the public_key
+// property. LMS and XMSS public keys are stored in their own formats, since
+// they do not have a PKIX encoding that is widely supported.
+func (s *Signature) SetPublicKey(key crypto.PublicKey) error {
+	var der []byte
+	var err error
+
+	switch k := key.(type) {
+	case ed448.PublicKey:
+		der, err = asn1.Marshal(subjectPublicKeyInfo{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidEd448},
+			PublicKey: asn1.BitString{Bytes: k, BitLength: 8 * len(k)},
+		})
+	case *lms.PublicKey:
+		der = k.Bytes()
+	case *xmss.PublicKey:
+		der = k.Bytes()
+	default:
+		der, err = x509.MarshalPKIXPublicKey(key)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.PublicKey = base64.RawStdEncoding.EncodeToString(der)
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// parsePKIXPublicKey - This function decodes a DER (PKIX) public key, with
+// support for Ed448 keys.
+func parsePKIXPublicKey(der []byte) (crypto.PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &spki); err == nil && spki.Algorithm.Algorithm.Equal(oidEd448) {
+		if len(spki.PublicKey.Bytes) != ed448.PublicKeySize {
+			return nil, errors.New("the ed448 public key is not the correct size")
+		}
+		return ed448.PublicKey(spki.PublicKey.Bytes), nil
+	}
+	return x509.ParsePKIXPublicKey(der)
+}
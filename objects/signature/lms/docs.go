@@ -0,0 +1,18 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package lms implements the Leighton-Micali hash-based signature scheme from
+// RFC 8554 with SHA-256 and a single tree. LMS is a stateful scheme, each
+// private key contains 2^h one-time keys and every signature uses the next
+// one. The private key saves its state to a state.Store before each signature
+// is returned, so the same one-time key is never used twice, even if the
+// process stops while signing.
+//
+// The whole Merkle tree is kept in memory, 2^(h+1) * 32 bytes, and it is
+// built when a key is generated or parsed. This takes a long time and a lot of
+// memory for heights 20 and 25, so private keys are limited to heights up to
+// 15 and the larger heights are not in the signing methods vocabulary.
+// Signatures from keys of any height can be verified.
+package lms
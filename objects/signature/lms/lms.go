@@ -0,0 +1,458 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package lms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/openplaybooks/libcacao/objects/signature/state"
+)
+
+// Type - This type identifies an LMS parameter set.
+type Type uint32
+
+// These are the LMS parameter sets from RFC 8554 section 5.1.
+const (
+	TypeSHA256M32H5  Type = 5
+	TypeSHA256M32H10 Type = 6
+	TypeSHA256M32H15 Type = 7
+	TypeSHA256M32H20 Type = 8
+	TypeSHA256M32H25 Type = 9
+)
+
+// These errors are returned when a private key can not be created or used to
+// sign.
+var (
+	ErrKeyExhausted      = errors.New("lms: all of the one-time keys have been used")
+	ErrNoStore           = errors.New("lms: the private key does not have a state store")
+	ErrUnsupportedHeight = errors.New("lms: private keys with a height above 15 are not supported")
+)
+
+// maxPrivateHeight is the largest tree height that a private key can have.
+// The whole tree is built when a private key is generated or parsed, which is
+// too slow and uses too much memory for heights 20 and 25. Public keys of any
+// height can still be used to verify.
+const maxPrivateHeight = 15
+
+const (
+	// PublicKeySize is the size, in bytes, of public keys.
+	PublicKeySize = 4 + 4 + idSize + n
+
+	// privateKeySize is the size, in bytes, of the private key state.
+	privateKeySize = 4 + 4 + idSize + n + 4
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// PublicKey - This type is an LMS public key.
+type PublicKey struct {
+	typ     Type
+	otsType OTSType
+	id      []byte
+	root    []byte
+}
+
+// PrivateKey - This type is an LMS private key. It holds the index of the next
+// one-time key and the store where that index is saved. A PrivateKey is safe
+// for concurrent use.
+type PrivateKey struct {
+	mu      sync.Mutex
+	typ     Type
+	otsType OTSType
+	id      []byte
+	seed    []byte
+	q       uint32
+	tree    []byte
+	store   state.Store
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// TypeFromName - This function returns the parameter set for a name like
+// "LMS_SHA256_M32_H10".
+func TypeFromName(name string) (Type, error) {
+	for t := TypeSHA256M32H5; t <= TypeSHA256M32H25; t++ {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("lms: unknown parameter set %s", name)
+}
+
+// GenerateKey - This function generates a new private key with entropy from
+// random. If random is nil, crypto/rand.Reader will be used. If the store is
+// not nil the initial state is saved to it. Heights above 15 return
+// ErrUnsupportedHeight.
+func GenerateKey(random io.Reader, typ Type, otsType OTSType, store state.Store) (*PrivateKey, error) {
+	if typ.Height() == 0 {
+		return nil, fmt.Errorf("lms: unknown parameter set %d", typ)
+	}
+	if typ.Height() > maxPrivateHeight {
+		return nil, ErrUnsupportedHeight
+	}
+	if _, ok := otsParamSets[otsType]; !ok {
+		return nil, fmt.Errorf("lms: unknown LM-OTS parameter set %d", otsType)
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	priv := &PrivateKey{
+		typ:     typ,
+		otsType: otsType,
+		id:      make([]byte, idSize),
+		seed:    make([]byte, n),
+		store:   store,
+	}
+	if _, err := io.ReadFull(random, priv.id); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(random, priv.seed); err != nil {
+		return nil, err
+	}
+
+	if store != nil {
+		if err := store.Save(priv.marshal(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	priv.buildTree()
+	return priv, nil
+}
+
+// ParsePrivateKey - This function decodes the private key state created by
+// MarshalBinary and rebuilds the tree. Future signatures will save the state
+// to the store. Keys with a height above 15 return ErrUnsupportedHeight.
+func ParsePrivateKey(data []byte, store state.Store) (*PrivateKey, error) {
+	if len(data) != privateKeySize {
+		return nil, errors.New("lms: the private key is not the correct size")
+	}
+
+	priv := &PrivateKey{
+		typ:     Type(binary.BigEndian.Uint32(data[0:])),
+		otsType: OTSType(binary.BigEndian.Uint32(data[4:])),
+		id:      append([]byte(nil), data[8:8+idSize]...),
+		seed:    append([]byte(nil), data[8+idSize:8+idSize+n]...),
+		q:       binary.BigEndian.Uint32(data[8+idSize+n:]),
+		store:   store,
+	}
+	if priv.typ.Height() == 0 {
+		return nil, fmt.Errorf("lms: unknown parameter set %d", priv.typ)
+	}
+	if priv.typ.Height() > maxPrivateHeight {
+		return nil, ErrUnsupportedHeight
+	}
+	if _, ok := otsParamSets[priv.otsType]; !ok {
+		return nil, fmt.Errorf("lms: unknown LM-OTS parameter set %d", priv.otsType)
+	}
+
+	priv.buildTree()
+	return priv, nil
+}
+
+// ParsePublicKey - This function decodes a public key in the format from RFC
+// 8554 section 5.3.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	if len(data) != PublicKeySize {
+		return nil, errors.New("lms: the public key is not the correct size")
+	}
+
+	pub := &PublicKey{
+		typ:     Type(binary.BigEndian.Uint32(data[0:])),
+		otsType: OTSType(binary.BigEndian.Uint32(data[4:])),
+		id:      append([]byte(nil), data[8:8+idSize]...),
+		root:    append([]byte(nil), data[8+idSize:]...),
+	}
+	if pub.typ.Height() == 0 {
+		return nil, fmt.Errorf("lms: unknown parameter set %d", pub.typ)
+	}
+	if _, ok := otsParamSets[pub.otsType]; !ok {
+		return nil, fmt.Errorf("lms: unknown LM-OTS parameter set %d", pub.otsType)
+	}
+	return pub, nil
+}
+
+// ----------------------------------------------------------------------
+// Public Type Methods
+// ----------------------------------------------------------------------
+
+// String - This method returns the name of the parameter set.
+func (t Type) String() string {
+	if h := t.Height(); h != 0 {
+		return fmt.Sprintf("LMS_SHA256_M32_H%d", h)
+	}
+	return "LMS_UNKNOWN"
+}
+
+// Height - This method returns the height of the tree, or zero if the
+// parameter set is not known.
+func (t Type) Height() int {
+	switch t {
+	case TypeSHA256M32H5:
+		return 5
+	case TypeSHA256M32H10:
+		return 10
+	case TypeSHA256M32H15:
+		return 15
+	case TypeSHA256M32H20:
+		return 20
+	case TypeSHA256M32H25:
+		return 25
+	}
+	return 0
+}
+
+// ----------------------------------------------------------------------
+// Public PublicKey Methods
+// ----------------------------------------------------------------------
+
+// Type - This method returns the LMS parameter set of the key.
+func (pub *PublicKey) Type() Type {
+	return pub.typ
+}
+
+// Bytes - This method encodes the public key in the format from RFC 8554
+// section 5.3.
+func (pub *PublicKey) Bytes() []byte {
+	out := make([]byte, 0, PublicKeySize)
+	out = binary.BigEndian.AppendUint32(out, uint32(pub.typ))
+	out = binary.BigEndian.AppendUint32(out, uint32(pub.otsType))
+	out = append(out, pub.id...)
+	return append(out, pub.root...)
+}
+
+// Equal - This method reports whether the public key has the same value as x.
+func (pub *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(pub.Bytes(), xx.Bytes())
+}
+
+// Verify - This method reports whether sig is a valid LMS signature of the
+// message, following RFC 8554 section 5.4.2.
+func (pub *PublicKey) Verify(message, sig []byte) bool {
+	h := pub.typ.Height()
+	if h == 0 || len(sig) < 8 {
+		return false
+	}
+
+	q := binary.BigEndian.Uint32(sig)
+	otsType := OTSType(binary.BigEndian.Uint32(sig[4:]))
+	params, ok := otsParamSets[otsType]
+	if !ok || otsType != pub.otsType {
+		return false
+	}
+
+	otsEnd := 4 + params.signatureSize()
+	if len(sig) != otsEnd+4+h*n {
+		return false
+	}
+	if Type(binary.BigEndian.Uint32(sig[otsEnd:])) != pub.typ {
+		return false
+	}
+	if q >= 1<<uint(h) {
+		return false
+	}
+
+	kc := otsCandidate(pub.id, q, sig[4:otsEnd], message)
+	path := sig[otsEnd+4:]
+
+	node := uint32(1)<<uint(h) + q
+	tmp := leafHash(pub.id, node, kc)
+	for i := 0; node > 1; i++ {
+		sibling := path[i*n : (i+1)*n]
+		if node%2 == 1 {
+			tmp = interiorHash(pub.id, node/2, sibling, tmp)
+		} else {
+			tmp = interiorHash(pub.id, node/2, tmp, sibling)
+		}
+		node /= 2
+	}
+	return subtle.ConstantTimeCompare(tmp, pub.root) == 1
+}
+
+// ----------------------------------------------------------------------
+// Public PrivateKey Methods
+// ----------------------------------------------------------------------
+
+// Type - This method returns the LMS parameter set of the key.
+func (priv *PrivateKey) Type() Type {
+	return priv.typ
+}
+
+// Public - This method returns the public key for the private key.
+func (priv *PrivateKey) Public() crypto.PublicKey {
+	return priv.PublicKey()
+}
+
+// PublicKey - This method returns the public key for the private key.
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{
+		typ:     priv.typ,
+		otsType: priv.otsType,
+		id:      append([]byte(nil), priv.id...),
+		root:    append([]byte(nil), priv.tree[n:2*n]...),
+	}
+}
+
+// SetStore - This method sets the store where the state is saved.
+func (priv *PrivateKey) SetStore(store state.Store) {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	priv.store = store
+}
+
+// Remaining - This method returns the number of signatures that the key can
+// still create.
+func (priv *PrivateKey) Remaining() uint64 {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	return uint64(1)<<uint(priv.typ.Height()) - uint64(priv.q)
+}
+
+// MarshalBinary - This method encodes the private key state. The encoding
+// contains the secret seed and must be protected.
+func (priv *PrivateKey) MarshalBinary() ([]byte, error) {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	return priv.marshal(priv.q), nil
+}
+
+// Sign - This method implements crypto.Signer. The message must not be hashed
+// and opts.HashFunc() must return zero. The new state is saved to the store
+// before the signature is created, if that fails no signature is returned.
+func (priv *PrivateKey) Sign(random io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("lms: cannot sign hashed message")
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+
+	if priv.store == nil {
+		return nil, ErrNoStore
+	}
+	h := priv.typ.Height()
+	q := priv.q
+	if uint64(q) >= uint64(1)<<uint(h) {
+		return nil, ErrKeyExhausted
+	}
+
+	c := make([]byte, n)
+	if _, err := io.ReadFull(random, c); err != nil {
+		return nil, err
+	}
+
+	// Reserve the one-time key before it is used
+	if err := priv.store.Save(priv.marshal(q + 1)); err != nil {
+		return nil, fmt.Errorf("lms: unable to save the private key state: %w", err)
+	}
+	priv.q = q + 1
+
+	sig := binary.BigEndian.AppendUint32(nil, q)
+	sig = append(sig, otsSign(priv.otsType, priv.id, q, priv.seed, c, message)...)
+	sig = binary.BigEndian.AppendUint32(sig, uint32(priv.typ))
+
+	node := uint32(1)<<uint(h) + q
+	for i := 0; i < h; i++ {
+		sibling := (node >> uint(i)) ^ 1
+		sig = append(sig, priv.tree[int(sibling)*n:int(sibling+1)*n]...)
+	}
+	return sig, nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// leafHash - This function computes the value of a leaf node from the
+// one-time public key.
+func leafHash(id []byte, r uint32, k []byte) []byte {
+	var buf [idSize + 4 + 2 + n]byte
+	copy(buf[:], id)
+	binary.BigEndian.PutUint32(buf[idSize:], r)
+	binary.BigEndian.PutUint16(buf[idSize+4:], dLEAF)
+	copy(buf[idSize+6:], k)
+	sum := sha256.Sum256(buf[:])
+	return sum[:]
+}
+
+// interiorHash - This function computes the value of an interior node from
+// its children.
+func interiorHash(id []byte, r uint32, left, right []byte) []byte {
+	var buf [idSize + 4 + 2 + 2*n]byte
+	copy(buf[:], id)
+	binary.BigEndian.PutUint32(buf[idSize:], r)
+	binary.BigEndian.PutUint16(buf[idSize+4:], dINTR)
+	copy(buf[idSize+6:], left)
+	copy(buf[idSize+6+n:], right)
+	sum := sha256.Sum256(buf[:])
+	return sum[:]
+}
+
+// ----------------------------------------------------------------------
+// Private PrivateKey Methods
+// ----------------------------------------------------------------------
+
+// marshal - This method encodes the private key state with the index q.
+func (priv *PrivateKey) marshal(q uint32) []byte {
+	out := make([]byte, 0, privateKeySize)
+	out = binary.BigEndian.AppendUint32(out, uint32(priv.typ))
+	out = binary.BigEndian.AppendUint32(out, uint32(priv.otsType))
+	out = append(out, priv.id...)
+	out = append(out, priv.seed...)
+	return binary.BigEndian.AppendUint32(out, q)
+}
+
+// buildTree - This method computes every node of the Merkle tree, from RFC
+// 8554 section 5.3. Node r is stored at tree[r*n:(r+1)*n]. The leaves are the
+// expensive part, so they are computed in parallel.
+func (priv *PrivateKey) buildTree() {
+	h := uint(priv.typ.Height())
+	leaves := uint32(1) << h
+	priv.tree = make([]byte, 2*int(leaves)*n)
+
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker uint32) {
+			defer wg.Done()
+			for q := worker; q < leaves; q += uint32(workers) {
+				k := otsPublicKey(priv.otsType, priv.id, q, priv.seed)
+				r := leaves + q
+				copy(priv.tree[int(r)*n:], leafHash(priv.id, r, k))
+			}
+		}(uint32(worker))
+	}
+	wg.Wait()
+
+	for r := leaves - 1; r >= 1; r-- {
+		left := priv.tree[int(2*r)*n : int(2*r+1)*n]
+		right := priv.tree[int(2*r+1)*n : int(2*r+2)*n]
+		copy(priv.tree[int(r)*n:], interiorHash(priv.id, r, left, right))
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package lms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/signature/state"
+)
+
+type failingStore struct{}
+
+func (failingStore) Save(state []byte) error {
+	return errors.New("disk full")
+}
+
+// TestSignVerify - This will test signing and verifying with each of the LM-OTS
+// parameter sets
+func TestSignVerify(t *testing.T) {
+	message := []byte("a playbook to sign")
+
+	for i, otsType := range []OTSType{OTSTypeSHA256N32W1, OTSTypeSHA256N32W2, OTSTypeSHA256N32W4, OTSTypeSHA256N32W8} {
+		priv, err := GenerateKey(nil, TypeSHA256M32H5, otsType, &state.MemoryStore{})
+		if err != nil {
+			t.Fatalf("1.%d unable to generate key: %s", i, err)
+		}
+		pub := priv.PublicKey()
+
+		for q := 0; q < 3; q++ {
+			sig, err := priv.Sign(nil, message, nil)
+			if err != nil {
+				t.Fatalf("1.%d.%d unable to sign: %s", i, q, err)
+			}
+			if !pub.Verify(message, sig) {
+				t.Errorf("1.%d.%d valid signature with %s was not verified", i, q, otsType)
+			}
+			if pub.Verify([]byte("a different playbook"), sig) {
+				t.Errorf("1.%d.%d signature of a different message was verified", i, q)
+			}
+			sig[len(sig)-1] ^= 1
+			if pub.Verify(message, sig) {
+				t.Errorf("1.%d.%d modified signature was verified", i, q)
+			}
+		}
+
+		parsed, err := ParsePublicKey(pub.Bytes())
+		if err != nil || !parsed.Equal(pub) {
+			t.Errorf("1.%d public key did not round trip", i)
+		}
+	}
+}
+
+// TestState - This will test that the state is saved before each signature and
+// that the one-time keys are never reused
+func TestState(t *testing.T) {
+	store := &state.MemoryStore{}
+	priv, err := GenerateKey(nil, TypeSHA256M32H5, OTSTypeSHA256N32W8, store)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	if _, err := priv.Sign(nil, []byte("one"), nil); err != nil {
+		t.Fatalf("2.0 unable to sign: %s", err)
+	}
+
+	// Reload the key from the saved state
+	saved, _ := store.Load()
+	reloaded, err := ParsePrivateKey(saved, store)
+	if err != nil {
+		t.Fatalf("2.1 unable to parse the saved state: %s", err)
+	}
+	if !reloaded.PublicKey().Equal(priv.PublicKey()) {
+		t.Errorf("2.2 reloaded key does not have the same public key")
+	}
+	if reloaded.Remaining() != 31 {
+		t.Errorf("2.3 reloaded key should have 31 signatures remaining, has %d", reloaded.Remaining())
+	}
+
+	// A store that fails must stop the signature and leave the index alone
+	reloaded.SetStore(failingStore{})
+	if sig, err := reloaded.Sign(nil, []byte("two"), nil); err == nil || sig != nil {
+		t.Errorf("2.4 a signature was returned when the state could not be saved")
+	}
+	if reloaded.Remaining() != 31 {
+		t.Errorf("2.5 the index was advanced when the state could not be saved")
+	}
+
+	reloaded.SetStore(nil)
+	if _, err := reloaded.Sign(nil, []byte("two"), nil); !errors.Is(err, ErrNoStore) {
+		t.Errorf("2.6 signing without a store should return ErrNoStore, got %v", err)
+	}
+
+	reloaded.SetStore(store)
+	for reloaded.Remaining() > 0 {
+		if _, err := reloaded.Sign(nil, []byte("more"), nil); err != nil {
+			t.Fatalf("2.7 unable to sign: %s", err)
+		}
+	}
+	if _, err := reloaded.Sign(nil, []byte("too many"), nil); !errors.Is(err, ErrKeyExhausted) {
+		t.Errorf("2.8 signing with an exhausted key should return ErrKeyExhausted, got %v", err)
+	}
+}
+
+// TestRFC8554Vectors - This will test that the private keys from RFC 8554
+// appendix F test case 2 give the public keys from the RFC
+func TestRFC8554Vectors(t *testing.T) {
+	tests := []struct {
+		typ              Type
+		otsType          OTSType
+		id, seed, public string
+	}{
+		{
+			typ:     TypeSHA256M32H10,
+			otsType: OTSTypeSHA256N32W4,
+			id:      "d08fabd4a2091ff0a8cb4ed834e74534",
+			seed:    "558b8966c48ae9cb898b423c83443aae014a72f1b1ab5cc85cf1d892903b5439",
+			public:  "0000000600000003d08fabd4a2091ff0a8cb4ed834e7453432a58885cd9ba0431235466bff9651c6c92124404d45fa53cf161c28f1ad5a8e",
+		},
+		{
+			typ:     TypeSHA256M32H5,
+			otsType: OTSTypeSHA256N32W8,
+			id:      "215f83b7ccb9acbcd08db97b0d04dc2b",
+			seed:    "a1c4696e2608035a886100d05cd99945eb3370731884a8235e2fb3d4d71f2547",
+			public:  "0000000500000004215f83b7ccb9acbcd08db97b0d04dc2ba1cd035833e0e90059603f26e07ad2aad152338e7a5e5984bcd5f7bb4eba40b7",
+		},
+	}
+
+	for i, test := range tests {
+		id, _ := hex.DecodeString(test.id)
+		seed, _ := hex.DecodeString(test.seed)
+		public, _ := hex.DecodeString(test.public)
+
+		var data []byte
+		data = append(data, 0, 0, 0, byte(test.typ), 0, 0, 0, byte(test.otsType))
+		data = append(data, id...)
+		data = append(data, seed...)
+		data = append(data, 0, 0, 0, 0)
+
+		store := &state.MemoryStore{}
+		priv, err := ParsePrivateKey(data, store)
+		if err != nil {
+			t.Fatalf("3.%d unable to parse the private key: %s", i, err)
+		}
+		if !bytes.Equal(priv.PublicKey().Bytes(), public) {
+			t.Errorf("3.%d the public key is not correct\nExpected: %x\nHave: %x", i, public, priv.PublicKey().Bytes())
+		}
+
+		message := []byte("a playbook to sign")
+		sig, err := priv.Sign(nil, message, nil)
+		if err != nil || !priv.PublicKey().Verify(message, sig) {
+			t.Errorf("3.%d the signature with the RFC key was not verified: %v", i, err)
+		}
+	}
+}
+
+// TestUnsupportedHeight - This will test that private keys for the large
+// parameter sets are rejected, while their public keys can still be parsed
+func TestUnsupportedHeight(t *testing.T) {
+	for i, typ := range []Type{TypeSHA256M32H20, TypeSHA256M32H25} {
+		if _, err := GenerateKey(nil, typ, OTSTypeSHA256N32W8, nil); !errors.Is(err, ErrUnsupportedHeight) {
+			t.Errorf("4.%d GenerateKey returned %v instead of ErrUnsupportedHeight", i, err)
+		}
+
+		data := make([]byte, privateKeySize)
+		data[3], data[7] = byte(typ), byte(OTSTypeSHA256N32W8)
+		if _, err := ParsePrivateKey(data, nil); !errors.Is(err, ErrUnsupportedHeight) {
+			t.Errorf("4.%d ParsePrivateKey returned %v instead of ErrUnsupportedHeight", i, err)
+		}
+		if _, err := ParsePublicKey(data[:PublicKeySize]); err != nil {
+			t.Errorf("4.%d ParsePublicKey returned an error: %s", i, err)
+		}
+	}
+}
+
+// TestRFC8554Signatures - This will test signatures made with the private keys
+// from RFC 8554 appendix F test case 2, laid out like the two levels of the
+// HSS signature in the RFC: the top level key signs the public key of the
+// second level key, which signs the message. The signatures are checked
+// against the public keys printed in the RFC. The randomizer C is fixed, so
+// the signatures are known answers and their hashes are compared as well.
+func TestRFC8554Signatures(t *testing.T) {
+	top := "0000000600000003d08fabd4a2091ff0a8cb4ed834e74534558b8966c48ae9cb898b423c83443aae014a72f1b1ab5cc85cf1d892903b5439"
+	topPublic := "0000000600000003d08fabd4a2091ff0a8cb4ed834e7453432a58885cd9ba0431235466bff9651c6c92124404d45fa53cf161c28f1ad5a8e"
+	second := "0000000500000004215f83b7ccb9acbcd08db97b0d04dc2ba1c4696e2608035a886100d05cd99945eb3370731884a8235e2fb3d4d71f2547"
+	secondPublic := "0000000500000004215f83b7ccb9acbcd08db97b0d04dc2ba1cd035833e0e90059603f26e07ad2aad152338e7a5e5984bcd5f7bb4eba40b7"
+	c := bytes.Repeat([]byte{0x5a}, n)
+	message := []byte("a playbook to sign")
+
+	tests := []struct {
+		private, public, message, hash string
+		size                           int
+	}{
+		{top, topPublic, secondPublic, "c96d119a6bb1ef4d73e3b8c95a6f6746a0bc15fd71bf6afdbe1b66478c2fde74", 2508},
+		{second, secondPublic, hex.EncodeToString(message), "d2352464fb5a8b454d3c71a0d75008531cdd95bfd432656deb21064a799f4d31", 1292},
+	}
+
+	for i, test := range tests {
+		// Sign with the one-time key at q = 5
+		data, _ := hex.DecodeString(test.private + "00000005")
+		priv, err := ParsePrivateKey(data, &state.MemoryStore{})
+		if err != nil {
+			t.Fatalf("5.%d unable to parse the private key: %s", i, err)
+		}
+		msg, _ := hex.DecodeString(test.message)
+		sig, err := priv.Sign(bytes.NewReader(c), msg, nil)
+		if err != nil {
+			t.Fatalf("5.%d unable to sign: %s", i, err)
+		}
+
+		public, _ := hex.DecodeString(test.public)
+		pub, err := ParsePublicKey(public)
+		if err != nil {
+			t.Fatalf("5.%d unable to parse the RFC public key: %s", i, err)
+		}
+		if len(sig) != test.size || binary.BigEndian.Uint32(sig) != 5 || !bytes.Equal(sig[8:8+n], c) {
+			t.Errorf("5.%d the signature is not laid out as in RFC 8554 section 5.4", i)
+		}
+		if !pub.Verify(msg, sig) {
+			t.Errorf("5.%d the signature was not verified with the RFC public key", i)
+		}
+		if hash := sha256.Sum256(sig); hex.EncodeToString(hash[:]) != test.hash {
+			t.Errorf("5.%d the signature is not the known answer, its hash is %x", i, hash)
+		}
+
+		sig[len(sig)-1] ^= 1
+		if pub.Verify(msg, sig) {
+			t.Errorf("5.%d a modified signature was verified", i)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package lms
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// This file implements the LM-OTS one-time signatures from RFC 8554 section 4.
+
+// The domain separation values from RFC 8554 section 3.
+const (
+	dPBLC = 0x8080
+	dMESG = 0x8181
+	dLEAF = 0x8282
+	dINTR = 0x8383
+)
+
+const (
+	n      = 32
+	idSize = 16
+)
+
+// OTSType - This type identifies an LM-OTS parameter set.
+type OTSType uint32
+
+// These are the LM-OTS parameter sets from RFC 8554 section 4.1.
+const (
+	OTSTypeSHA256N32W1 OTSType = 1
+	OTSTypeSHA256N32W2 OTSType = 2
+	OTSTypeSHA256N32W4 OTSType = 3
+	OTSTypeSHA256N32W8 OTSType = 4
+)
+
+// otsParams - This type holds the values that are derived from the LM-OTS
+// parameter set.
+type otsParams struct {
+	w  uint // the Winternitz parameter, in bits
+	p  int  // the number of n-byte string elements in a signature
+	ls uint // the left shift for the checksum
+}
+
+var otsParamSets = map[OTSType]otsParams{
+	OTSTypeSHA256N32W1: {w: 1, p: 265, ls: 7},
+	OTSTypeSHA256N32W2: {w: 2, p: 133, ls: 6},
+	OTSTypeSHA256N32W4: {w: 4, p: 67, ls: 4},
+	OTSTypeSHA256N32W8: {w: 8, p: 34, ls: 0},
+}
+
+// String - This method returns the name of the parameter set.
+func (t OTSType) String() string {
+	switch t {
+	case OTSTypeSHA256N32W1:
+		return "LMOTS_SHA256_N32_W1"
+	case OTSTypeSHA256N32W2:
+		return "LMOTS_SHA256_N32_W2"
+	case OTSTypeSHA256N32W4:
+		return "LMOTS_SHA256_N32_W4"
+	case OTSTypeSHA256N32W8:
+		return "LMOTS_SHA256_N32_W8"
+	}
+	return "LMOTS_UNKNOWN"
+}
+
+// signatureSize - This method returns the size of an LM-OTS signature.
+func (p otsParams) signatureSize() int {
+	return 4 + n + p.p*n
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// coef - This function returns the i'th w-bit value of s, from RFC 8554
+// section 3.1.3.
+func coef(s []byte, i int, w uint) uint {
+	mask := uint(1)<<w - 1
+	perByte := 8 / int(w)
+	b := s[i*int(w)/8]
+	shift := 8 - (w*uint(i%perByte) + w)
+	return mask & uint(b>>shift)
+}
+
+// checksum - This function computes the checksum of the message hash from
+// RFC 8554 section 4.4.
+func checksum(q []byte, params otsParams) uint16 {
+	var sum uint
+	max := uint(1)<<params.w - 1
+	for i := 0; i < n*8/int(params.w); i++ {
+		sum += max - coef(q, i, params.w)
+	}
+	return uint16(sum << params.ls)
+}
+
+// digits - This function returns the Winternitz digits of the message hash
+// with its checksum.
+func digits(q []byte, params otsParams) []uint {
+	buf := make([]byte, n+2)
+	copy(buf, q)
+	binary.BigEndian.PutUint16(buf[n:], checksum(q, params))
+
+	out := make([]uint, params.p)
+	for i := range out {
+		out[i] = coef(buf, i, params.w)
+	}
+	return out
+}
+
+// chain - This function applies the hash chain to x, from step start up to,
+// but not including, step end.
+func chain(id []byte, q uint32, i int, x []byte, start, end uint) []byte {
+	var buf [idSize + 4 + 2 + 1 + n]byte
+	copy(buf[:], id)
+	binary.BigEndian.PutUint32(buf[idSize:], q)
+	binary.BigEndian.PutUint16(buf[idSize+4:], uint16(i))
+	copy(buf[idSize+7:], x)
+
+	for j := start; j < end; j++ {
+		buf[idSize+6] = byte(j)
+		sum := sha256.Sum256(buf[:])
+		copy(buf[idSize+7:], sum[:])
+	}
+
+	out := make([]byte, n)
+	copy(out, buf[idSize+7:])
+	return out
+}
+
+// otsSecret - This function derives the i'th element of the private key for
+// the one-time key q from the seed, as in RFC 8554 appendix A.
+func otsSecret(id []byte, q uint32, i int, seed []byte) []byte {
+	var buf [idSize + 4 + 2 + 1 + n]byte
+	copy(buf[:], id)
+	binary.BigEndian.PutUint32(buf[idSize:], q)
+	binary.BigEndian.PutUint16(buf[idSize+4:], uint16(i))
+	buf[idSize+6] = 0xff
+	copy(buf[idSize+7:], seed)
+	sum := sha256.Sum256(buf[:])
+	return sum[:]
+}
+
+// otsMessageHash - This function computes Q, the hash of the randomizer and
+// the message.
+func otsMessageHash(id []byte, q uint32, c, message []byte) []byte {
+	h := sha256.New()
+	h.Write(id)
+	binary.Write(h, binary.BigEndian, q)
+	binary.Write(h, binary.BigEndian, uint16(dMESG))
+	h.Write(c)
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// otsPublicHash - This function hashes the ends of the chains into the
+// one-time public key K.
+func otsPublicHash(id []byte, q uint32, y [][]byte) []byte {
+	h := sha256.New()
+	h.Write(id)
+	binary.Write(h, binary.BigEndian, q)
+	binary.Write(h, binary.BigEndian, uint16(dPBLC))
+	for _, v := range y {
+		h.Write(v)
+	}
+	return h.Sum(nil)
+}
+
+// otsPublicKey - This function computes the one-time public key K for the
+// one-time key q, from RFC 8554 section 4.3.
+func otsPublicKey(otsType OTSType, id []byte, q uint32, seed []byte) []byte {
+	params := otsParamSets[otsType]
+	max := uint(1)<<params.w - 1
+
+	y := make([][]byte, params.p)
+	for i := range y {
+		y[i] = chain(id, q, i, otsSecret(id, q, i, seed), 0, max)
+	}
+	return otsPublicHash(id, q, y)
+}
+
+// otsSign - This function creates an LM-OTS signature with the one-time key q,
+// from RFC 8554 section 4.5.
+func otsSign(otsType OTSType, id []byte, q uint32, seed, c, message []byte) []byte {
+	params := otsParamSets[otsType]
+	a := digits(otsMessageHash(id, q, c, message), params)
+
+	sig := make([]byte, 0, params.signatureSize())
+	sig = binary.BigEndian.AppendUint32(sig, uint32(otsType))
+	sig = append(sig, c...)
+	for i := 0; i < params.p; i++ {
+		sig = append(sig, chain(id, q, i, otsSecret(id, q, i, seed), 0, a[i])...)
+	}
+	return sig
+}
+
+// otsCandidate - This function computes the candidate one-time public key from
+// an LM-OTS signature, from RFC 8554 section 4.6. The signature must already
+// have been checked to be the correct size for its type.
+func otsCandidate(id []byte, q uint32, sig, message []byte) []byte {
+	otsType := OTSType(binary.BigEndian.Uint32(sig))
+	params := otsParamSets[otsType]
+	max := uint(1)<<params.w - 1
+
+	c := sig[4 : 4+n]
+	a := digits(otsMessageHash(id, q, c, message), params)
+
+	z := make([][]byte, params.p)
+	for i := range z {
+		y := sig[4+n+i*n : 4+n+(i+1)*n]
+		z[i] = chain(id, q, i, y, a[i], max)
+	}
+	return otsPublicHash(id, q, z)
+}
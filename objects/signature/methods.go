@@ -0,0 +1,234 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/openplaybooks/libcacao/objects/signature/ed448"
+	"github.com/openplaybooks/libcacao/objects/signature/lms"
+	"github.com/openplaybooks/libcacao/objects/signature/xmss"
+)
+
+// SigningMethodEd448 - This is the signing method for Ed448 signatures. It
+// expects an ed448.PrivateKey for signing and an ed448.PublicKey for
+// verification.
+var SigningMethodEd448 = &signingMethodEd448{}
+
+// ErrEd448Verification - This error is returned when an Ed448 signature does
+// not verify.
+var ErrEd448Verification = errors.New("ed448: verification error")
+
+// ErrHashBasedVerification - This error is returned when an LMS or XMSS
+// signature does not verify.
+var ErrHashBasedVerification = errors.New("hash-based signature verification error")
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// GetSigningMethod - This function will return the signing method for the
+// algorithm name used in a CACAO signature object, like "RS256" or "Ed25519".
+func GetSigningMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case "RS256":
+		return jwt.SigningMethodRS256, nil
+	case "RS384":
+		return jwt.SigningMethodRS384, nil
+	case "RS512":
+		return jwt.SigningMethodRS512, nil
+	case "ES256":
+		return jwt.SigningMethodES256, nil
+	case "ES384":
+		return jwt.SigningMethodES384, nil
+	case "ES512":
+		return jwt.SigningMethodES512, nil
+	case "PS256":
+		return jwt.SigningMethodPS256, nil
+	case "PS384":
+		return jwt.SigningMethodPS384, nil
+	case "PS512":
+		return jwt.SigningMethodPS512, nil
+	case "Ed25519":
+		return jwt.SigningMethodEdDSA, nil
+	case "Ed448":
+		return SigningMethodEd448, nil
+	}
+
+	if strings.HasPrefix(algorithm, "LMS_") {
+		if t, err := lms.TypeFromName(algorithm); err == nil {
+			return &signingMethodLMS{typ: t}, nil
+		}
+	}
+	if strings.HasPrefix(algorithm, "XMSS-") {
+		if oid, err := xmss.OIDFromName(algorithm); err == nil {
+			return &signingMethodXMSS{oid: oid}, nil
+		}
+	}
+	return nil, fmt.Errorf("the signing method %s is not supported", algorithm)
+}
+
+// ----------------------------------------------------------------------
+// Define Ed448 Signing Method
+// ----------------------------------------------------------------------
+
+type signingMethodEd448 struct{}
+
+// Alg - This method returns the name of the algorithm.
+func (m *signingMethodEd448) Alg() string {
+	return "Ed448"
+}
+
+// Verify - This method verifies the base64url encoded signature of the signing
+// string. The key must be an ed448.PublicKey.
+func (m *signingMethodEd448) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed448.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	if len(publicKey) != ed448.PublicKeySize {
+		return jwt.ErrInvalidKey
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed448.Verify(publicKey, []byte(signingString), sig) {
+		return ErrEd448Verification
+	}
+	return nil
+}
+
+// Sign - This method signs the signing string and returns the base64url
+// encoded signature. The key must be an ed448.PrivateKey.
+func (m *signingMethodEd448) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed448.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if len(privateKey) != ed448.PrivateKeySize {
+		return "", jwt.ErrInvalidKey
+	}
+
+	return jwt.EncodeSegment(ed448.Sign(privateKey, []byte(signingString))), nil
+}
+
+// ----------------------------------------------------------------------
+// Define LMS Signing Method
+// ----------------------------------------------------------------------
+
+// signingMethodLMS - This signing method expects an *lms.PrivateKey for
+// signing and an *lms.PublicKey for verification, both with the parameter set
+// of the method. Signing advances the state of the private key.
+type signingMethodLMS struct {
+	typ lms.Type
+}
+
+// Alg - This method returns the name of the algorithm.
+func (m *signingMethodLMS) Alg() string {
+	return m.typ.String()
+}
+
+// Verify - This method verifies the base64url encoded signature of the signing
+// string.
+func (m *signingMethodLMS) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(*lms.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	if publicKey.Type() != m.typ {
+		return jwt.ErrInvalidKey
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !publicKey.Verify([]byte(signingString), sig) {
+		return ErrHashBasedVerification
+	}
+	return nil
+}
+
+// Sign - This method signs the signing string and returns the base64url
+// encoded signature.
+func (m *signingMethodLMS) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(*lms.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if privateKey.Type() != m.typ {
+		return "", jwt.ErrInvalidKey
+	}
+
+	sig, err := privateKey.Sign(nil, []byte(signingString), nil)
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(sig), nil
+}
+
+// ----------------------------------------------------------------------
+// Define XMSS Signing Method
+// ----------------------------------------------------------------------
+
+// signingMethodXMSS - This signing method expects an *xmss.PrivateKey for
+// signing and an *xmss.PublicKey for verification, both with the parameter set
+// of the method. Signing advances the state of the private key.
+type signingMethodXMSS struct {
+	oid xmss.OID
+}
+
+// Alg - This method returns the name of the algorithm.
+func (m *signingMethodXMSS) Alg() string {
+	return m.oid.String()
+}
+
+// Verify - This method verifies the base64url encoded signature of the signing
+// string.
+func (m *signingMethodXMSS) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(*xmss.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	if publicKey.OID() != m.oid {
+		return jwt.ErrInvalidKey
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !publicKey.Verify([]byte(signingString), sig) {
+		return ErrHashBasedVerification
+	}
+	return nil
+}
+
+// Sign - This method signs the signing string and returns the base64url
+// encoded signature.
+func (m *signingMethodXMSS) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(*xmss.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	if privateKey.OID() != m.oid {
+		return "", jwt.ErrInvalidKey
+	}
+
+	sig, err := privateKey.Sign(nil, []byte(signingString), nil)
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(sig), nil
+}
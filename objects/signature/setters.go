@@ -73,3 +73,74 @@ func (s *Signature) SetModified(t interface{}) error {
 	s.Modified = ts
 	return nil
 }
+
+// SetValidFrom - This method takes in a timestamp in either time.Time or string
+// format and updates the valid_from property with it. If a valid_until is
+// already present, the valid_from must be before it.
+func (s *Signature) SetValidFrom(t interface{}) error {
+	ts, err := objects.TimeToString(t, "milli")
+	if err != nil {
+		return err
+	}
+
+	if s.ValidUntil != "" {
+		from, _ := time.Parse(time.RFC3339, ts)
+		until, _ := time.Parse(time.RFC3339, s.ValidUntil)
+		if !from.Before(until) {
+			return errors.New("the valid_from timestamp is invalid, it is not before the valid_until timestamp")
+		}
+	}
+
+	s.ValidFrom = ts
+	return nil
+}
+
+// SetValidUntil - This method takes in a timestamp in either time.Time or
+// string format and updates the valid_until property with it. If a valid_from
+// is already present, the valid_until must be after it.
+func (s *Signature) SetValidUntil(t interface{}) error {
+	ts, err := objects.TimeToString(t, "milli")
+	if err != nil {
+		return err
+	}
+
+	if s.ValidFrom != "" {
+		from, _ := time.Parse(time.RFC3339, s.ValidFrom)
+		until, _ := time.Parse(time.RFC3339, ts)
+		if !until.After(from) {
+			return errors.New("the valid_until timestamp is invalid, it is not after the valid_from timestamp")
+		}
+	}
+
+	s.ValidUntil = ts
+	return nil
+}
+
+// SetRelatedTo - This method takes in the ID of the playbook that the
+// signature is for and updates the related_to property with it.
+func (s *Signature) SetRelatedTo(id string) error {
+	if !isIDValid(id, "playbook") {
+		return errors.New("the related_to value is not a valid playbook identifier")
+	}
+
+	s.RelatedTo = id
+	return nil
+}
+
+// SetRelatedVersion - This method takes in the modified timestamp of the
+// playbook that the signature is for, in either time.Time or string format,
+// and updates the related_version property with it. The related_to property
+// must be set first.
+func (s *Signature) SetRelatedVersion(t interface{}) error {
+	if s.RelatedTo == "" {
+		return errors.New("the related_to property must be set before the related_version property")
+	}
+
+	ts, err := objects.TimeToString(t, "milli")
+	if err != nil {
+		return err
+	}
+
+	s.RelatedVersion = ts
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/sha256"
+	_ "crypto/sha3"
+	_ "crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gowebpki/jcs"
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// These errors are returned, possibly wrapped, when a signature can not be
+// created or does not verify, so that callers can tell why.
+var (
+	ErrMissingKey           = errors.New("no public key was found for the signature")
+	ErrMissingValue         = errors.New("the signature does not contain a value")
+	ErrUnsupportedAlgorithm = errors.New("the signature algorithm is not supported")
+	ErrInvalidSignature     = errors.New("the signature does not match the signed content")
+)
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// SignHash - This function will sign the hex encoded hash of the content being
+// signed with the signing method, like "RS256", and return the base64url
+// encoded signature value. The key can be a private key or a Signer. An error
+// that wraps ErrKeyMismatch is returned if the key can not be used with the
+// signing method.
+func SignHash(method string, key interface{}, hash string) (string, error) {
+	signingMethod, err := GetSigningMethod(method)
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	if signer, ok := key.(Signer); ok {
+		value, err = signWithSigner(method, signer, hash)
+	} else {
+		value, err = signingMethod.Sign(hash, key)
+	}
+	if err == jwt.ErrInvalidKeyType || err == jwt.ErrInvalidKey || errors.Is(err, ErrKeyMismatch) {
+		return "", fmt.Errorf("%w: %s with a key of type %T", ErrKeyMismatch, method, key)
+	}
+	return value, err
+}
+
+// VerifyHash - This function will verify the base64url encoded signature value
+// of the hex encoded hash with the public key. It returns nil if the signature
+// is valid.
+func VerifyHash(algorithm, hash, value string, key crypto.PublicKey) error {
+	if key == nil {
+		return ErrMissingKey
+	}
+	if value == "" {
+		return ErrMissingValue
+	}
+
+	signingMethod, err := GetSigningMethod(algorithm)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+
+	err = signingMethod.Verify(hash, value, key)
+	if err == nil {
+		return nil
+	}
+	if err == jwt.ErrInvalidKeyType || err == jwt.ErrInvalidKey {
+		return fmt.Errorf("%w: %s with a key of type %T", ErrKeyMismatch, algorithm, key)
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+}
+
+// HashJSON - This function will return the hex encoded SHA-256 hash of the
+// JCS (RFC 8785) version of the JSON data.
+func HashJSON(data []byte) (string, error) {
+	jcsData, err := jcs.Transform(data)
+	if err != nil {
+		return "", err
+	}
+
+	// SHA256 encode JCS version. The Sum256 functions returns a [32]byte
+	hashhex := sha256.Sum256(jcsData)
+	return hex.EncodeToString(hashhex[:]), nil
+}
+
+// HashAlgorithm - This function will return the hash for a name from the hash
+// algorithms vocabulary, like "sha-256" or "sha3-256".
+func HashAlgorithm(name string) (crypto.Hash, error) {
+	switch name {
+	case "sha-256":
+		return crypto.SHA256, nil
+	case "sha-384":
+		return crypto.SHA384, nil
+	case "sha-512":
+		return crypto.SHA512, nil
+	case "sha3-256":
+		return crypto.SHA3_256, nil
+	case "sha3-384":
+		return crypto.SHA3_384, nil
+	case "sha3-512":
+		return crypto.SHA3_512, nil
+	}
+	return 0, fmt.Errorf("the hash algorithm %s is not supported", name)
+}
+
+// ----------------------------------------------------------------------
+// Public Signature Type Methods
+// ----------------------------------------------------------------------
+
+// PrepareSigning - This method will get the signature object ready to be
+// signed with the signing method and key. If the algorithm property is empty
+// it is set to the signing method, and if the key is a CertificateSigner with
+// a certificate chain the chain and its thumbprint are added, so they are
+// covered by the signature. The chain is not added when the public_key or
+// cert_url property is already set, since only one key source can be used.
+func (s *Signature) PrepareSigning(method string, key interface{}) error {
+	if !objects.IsVocabValueValid(method, GetSigningMethodsVocab()) {
+		return fmt.Errorf("the signing method %s is not valid", method)
+	}
+
+	if s.Algorithm == "" {
+		s.Algorithm = method
+	} else if s.Algorithm != method {
+		return fmt.Errorf("the signing method %s does not match the signature algorithm %s", method, s.Algorithm)
+	}
+
+	if s.PublicKey != "" || s.CertURL != "" {
+		return nil
+	}
+	if signer, ok := key.(CertificateSigner); ok && len(signer.CertificateChain()) > 0 {
+		if err := s.SetCertificateChain(signer.CertificateChain()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// signWithSigner - This function signs the hash with a signer and returns the
+// base64url encoded signature value.
+func signWithSigner(method string, signer Signer, hash string) (string, error) {
+	if signer.Algorithm() != method {
+		return "", fmt.Errorf("%w: the signer uses %s", ErrKeyMismatch, signer.Algorithm())
+	}
+	if err := CheckKey(method, signer.Public()); err != nil {
+		return "", err
+	}
+
+	digest, err := Digest(method, []byte(hash))
+	if err != nil {
+		return "", err
+	}
+
+	value, err := signer.SignDigest(digest)
+	if err != nil {
+		return "", err
+	}
+	return jwt.EncodeSegment(value), nil
+}
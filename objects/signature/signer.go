@@ -0,0 +1,306 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects/signature/ed448"
+	"github.com/openplaybooks/libcacao/objects/signature/lms"
+	"github.com/openplaybooks/libcacao/objects/signature/xmss"
+)
+
+// ErrKeyMismatch - This error is returned when a key can not be used with a
+// signing algorithm.
+var ErrKeyMismatch = errors.New("the key can not be used with the signing algorithm")
+
+// pkcs8 - This type is the ASN.1 structure of a PKCS #8 private key, it is
+// used for Ed448 keys which the x509 package does not know about.
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ----------------------------------------------------------------------
+// Define Interfaces
+// ----------------------------------------------------------------------
+
+// Signer - This interface defines an object that can create signatures with a
+// private key that it holds, like a key in memory, an HSM, a cloud KMS or a
+// signing agent. The private key never has to be given to this library.
+//
+// SignDigest is given the output of Digest() for the algorithm. For the RSA
+// and ECDSA algorithms this is the hash of the signing input, for the EdDSA,
+// LMS and XMSS algorithms it is the signing input itself. It must return the
+// signature in the form used by JWS (RFC 7518), so ECDSA signatures are the
+// fixed size R and S values and not ASN.1.
+type Signer interface {
+	Algorithm() string
+	Public() crypto.PublicKey
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// KeySigner - This type is a Signer that uses a crypto.Signer, like a private
+// key in memory or a key from a PKCS #11 or KMS library that implements the
+// crypto.Signer interface.
+type KeySigner struct {
+	algorithm string
+	key       crypto.Signer
+	chain     []*x509.Certificate
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// NewKeySigner - This function will create a new signer for the algorithm, like
+// "ES256", with the key. It returns an error if the key can not be used with
+// the algorithm.
+func NewKeySigner(algorithm string, key crypto.Signer) (*KeySigner, error) {
+	if key == nil {
+		return nil, errors.New("no key was given")
+	}
+	if err := CheckKey(algorithm, key.Public()); err != nil {
+		return nil, err
+	}
+	return &KeySigner{algorithm: algorithm, key: key}, nil
+}
+
+// LoadPEMSigner - This function will create a new signer for the algorithm with
+// the private key in a PEM file. If the file also contains certificates they
+// are used as the certificate chain, with the leaf certificate first.
+func LoadPEMSigner(path, algorithm string) (*KeySigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePEMPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	signer, err := NewKeySigner(algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) > 0 {
+		if err := signer.SetCertificateChain(chain); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return signer, nil
+}
+
+// ParsePEMPrivateKey - This function will decode the first private key in the
+// PEM data. PKCS #8 ("PRIVATE KEY"), PKCS #1 ("RSA PRIVATE KEY") and SEC 1
+// ("EC PRIVATE KEY") keys are supported, including Ed448 keys in PKCS #8.
+func ParsePEMPrivateKey(data []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no private key was found in the PEM data")
+		}
+
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			var p pkcs8
+			if _, err := asn1.Unmarshal(block.Bytes, &p); err == nil && p.Algorithm.Algorithm.Equal(oidEd448) {
+				var seed []byte
+				if _, err := asn1.Unmarshal(p.PrivateKey, &seed); err != nil || len(seed) != ed448.SeedSize {
+					return nil, errors.New("the ed448 private key is not valid")
+				}
+				return ed448.NewKeyFromSeed(seed), nil
+			}
+
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			signer, ok := key.(crypto.Signer)
+			if !ok {
+				return nil, fmt.Errorf("the private key of type %T can not be used to sign", key)
+			}
+			return signer, nil
+		}
+	}
+}
+
+// Digest - This function will return the value that is given to
+// Signer.SignDigest() to sign the message with the algorithm.
+func Digest(algorithm string, message []byte) ([]byte, error) {
+	hash, err := algorithmHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if hash == 0 {
+		return message, nil
+	}
+	h := hash.New()
+	h.Write(message)
+	return h.Sum(nil), nil
+}
+
+// CheckKey - This function will return an error that wraps ErrKeyMismatch if
+// the public key can not be used with the algorithm.
+func CheckKey(algorithm string, key crypto.PublicKey) error {
+	if _, err := algorithmHash(algorithm); err != nil {
+		return err
+	}
+
+	ok := false
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		ok = strings.HasPrefix(algorithm, "RS") || strings.HasPrefix(algorithm, "PS")
+	case *ecdsa.PublicKey:
+		switch algorithm {
+		case "ES256":
+			ok = k.Curve == elliptic.P256()
+		case "ES384":
+			ok = k.Curve == elliptic.P384()
+		case "ES512":
+			ok = k.Curve == elliptic.P521()
+		}
+	case ed25519.PublicKey:
+		ok = algorithm == "Ed25519"
+	case ed448.PublicKey:
+		ok = algorithm == "Ed448"
+	case *lms.PublicKey:
+		ok = algorithm == k.Type().String()
+	case *xmss.PublicKey:
+		ok = algorithm == k.OID().String()
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s with a key of type %T", ErrKeyMismatch, algorithm, key)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Public KeySigner Methods
+// ----------------------------------------------------------------------
+
+// Algorithm - This method returns the signing algorithm.
+func (k *KeySigner) Algorithm() string {
+	return k.algorithm
+}
+
+// Public - This method returns the public key.
+func (k *KeySigner) Public() crypto.PublicKey {
+	return k.key.Public()
+}
+
+// CertificateChain - This method returns the certificate chain for the key, or
+// nil if it does not have one.
+func (k *KeySigner) CertificateChain() []*x509.Certificate {
+	return k.chain
+}
+
+// SetCertificateChain - This method sets the certificate chain for the key,
+// with the leaf certificate first. The leaf certificate must be for the key.
+func (k *KeySigner) SetCertificateChain(chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("the certificate chain is empty")
+	}
+	public, ok := k.key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(chain[0].PublicKey) {
+		return errors.New("the leaf certificate is not for the signing key")
+	}
+	k.chain = chain
+	return nil
+}
+
+// SignDigest - This method signs the digest with the key.
+func (k *KeySigner) SignDigest(digest []byte) ([]byte, error) {
+	hash, err := algorithmHash(k.algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(k.algorithm, "PS"):
+		return k.key.Sign(rand.Reader, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash})
+
+	case strings.HasPrefix(k.algorithm, "ES"):
+		der, err := k.key.Sign(rand.Reader, digest, hash)
+		if err != nil {
+			return nil, err
+		}
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(der, &sig); err != nil {
+			return nil, err
+		}
+
+		size := (k.key.Public().(*ecdsa.PublicKey).Curve.Params().BitSize + 7) / 8
+		out := make([]byte, 2*size)
+		sig.R.FillBytes(out[:size])
+		sig.S.FillBytes(out[size:])
+		return out, nil
+	}
+	return k.key.Sign(rand.Reader, digest, hash)
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// algorithmHash - This function returns the hash that is used to create the
+// digest for the algorithm, or zero if the message is signed as is.
+func algorithmHash(algorithm string) (crypto.Hash, error) {
+	if _, err := GetSigningMethod(algorithm); err != nil {
+		return 0, err
+	}
+
+	switch algorithm {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/signature/ed448"
+)
+
+// TestParsePEMPrivateKey - This will test decoding each of the supported PEM
+// private key formats
+func TestParsePEMPrivateKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, ed448Key, _ := ed448.GenerateKey(rand.Reader)
+
+	ecDer, _ := x509.MarshalECPrivateKey(ecKey)
+	edDer, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	seed, _ := asn1.Marshal(ed448Key.Seed())
+	ed448Der, _ := asn1.Marshal(pkcs8{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidEd448}, PrivateKey: seed})
+
+	tests := []struct {
+		block *pem.Block
+		want  crypto.PublicKey
+	}{
+		{&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}, rsaKey.Public()},
+		{&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}, ecKey.Public()},
+		{&pem.Block{Type: "PRIVATE KEY", Bytes: edDer}, edKey.Public()},
+		{&pem.Block{Type: "PRIVATE KEY", Bytes: ed448Der}, ed448Key.Public()},
+	}
+
+	for i, test := range tests {
+		// Put a certificate before the key to make sure it is skipped
+		data := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0}}), pem.EncodeToMemory(test.block)...)
+		key, err := ParsePEMPrivateKey(data)
+		if err != nil {
+			t.Errorf("1.%d unable to parse %s: %s", i, test.block.Type, err)
+			continue
+		}
+		if !key.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(test.want) {
+			t.Errorf("1.%d the %s did not decode to the right key", i, test.block.Type)
+		}
+	}
+
+	if _, err := ParsePEMPrivateKey([]byte("not pem")); err == nil {
+		t.Errorf("1.4 data without a private key did not return an error")
+	}
+}
+
+// TestKeySigner - This will test the digests and that keys are checked against
+// the algorithm
+func TestKeySigner(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	if _, err := NewKeySigner("ES256", ecKey); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("2.0 a P-384 key was accepted for ES256")
+	}
+	if _, err := NewKeySigner("RS256", edKey); !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("2.1 an Ed25519 key was accepted for RS256")
+	}
+	if _, err := NewKeySigner("HS256", edKey); err == nil {
+		t.Errorf("2.2 an unsupported algorithm was accepted")
+	}
+
+	message := []byte("message")
+	sum := sha512.Sum384(message)
+	if d, _ := Digest("ES384", message); !bytes.Equal(d, sum[:]) {
+		t.Errorf("2.3 the ES384 digest is not the SHA-384 hash of the message")
+	}
+	if d, _ := Digest("Ed25519", message); !bytes.Equal(d, message) {
+		t.Errorf("2.4 the Ed25519 digest is not the message")
+	}
+
+	signer, err := NewKeySigner("ES384", ecKey)
+	if err != nil {
+		t.Fatalf("2.5 unable to create signer: %s", err)
+	}
+	digest, _ := Digest("ES384", message)
+	sig, err := signer.SignDigest(digest)
+	if err != nil || len(sig) != 96 {
+		t.Errorf("2.6 the ES384 signature is not 96 bytes of R and S")
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package signertest provides a test double for a remote signer, like a
+// signing agent, HSM or KMS service, that holds the private key in another
+// process. The Server listens on a local socket and signs digests with a
+// signature.Signer, and the RemoteSigner is a signature.Signer that sends
+// each digest to the server. Only the digest and the signature cross the
+// socket, the same way they would with a real remote signer.
+package signertest
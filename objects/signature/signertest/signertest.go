@@ -0,0 +1,223 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signertest
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/openplaybooks/libcacao/objects/signature"
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// Server - This type is a signing server that listens on a unix socket. Each
+// connection carries one JSON request and one JSON response.
+type Server struct {
+	Network string
+	Addr    string
+
+	signer   signature.Signer
+	listener net.Listener
+	dir      string
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	requests int
+}
+
+// RemoteSigner - This type is a signature.Signer that signs by sending the
+// digest to a Server.
+type RemoteSigner struct {
+	network   string
+	addr      string
+	algorithm string
+	public    crypto.PublicKey
+}
+
+// request - This type is a request sent to the server. The op is either
+// "info" or "sign".
+type request struct {
+	Op     string `json:"op"`
+	Digest []byte `json:"digest,omitempty"`
+}
+
+// response - This type is the response from the server. The public key is
+// encoded the same way as the public_key property of a signature.
+type response struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewServer - This function will start a server for the signer on a unix
+// socket in a new temporary directory. Close must be called to stop it.
+func NewServer(signer signature.Signer) (*Server, error) {
+	dir, err := os.MkdirTemp("", "signertest")
+	if err != nil {
+		return nil, err
+	}
+
+	addr := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", addr)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s := &Server{Network: "unix", Addr: addr, signer: signer, listener: l, dir: dir}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Dial - This function will connect to a signing server and return a signer
+// that uses it. The algorithm and public key are fetched once.
+func Dial(network, addr string) (*RemoteSigner, error) {
+	r := &RemoteSigner{network: network, addr: addr}
+
+	resp, err := r.call(request{Op: "info"})
+	if err != nil {
+		return nil, err
+	}
+
+	s := signature.Signature{Algorithm: resp.Algorithm, PublicKey: resp.PublicKey}
+	public, err := s.ParsePublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	r.algorithm = resp.Algorithm
+	r.public = public
+	return r, nil
+}
+
+// ----------------------------------------------------------------------
+// Public Server Methods
+// ----------------------------------------------------------------------
+
+// Close - This method stops the server and removes the socket.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	os.RemoveAll(s.dir)
+	return err
+}
+
+// Requests - This method returns the number of sign requests the server has
+// handled.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// ----------------------------------------------------------------------
+// Public RemoteSigner Methods
+// ----------------------------------------------------------------------
+
+// Algorithm - This method returns the signing algorithm of the remote key.
+func (r *RemoteSigner) Algorithm() string {
+	return r.algorithm
+}
+
+// Public - This method returns the public key of the remote key.
+func (r *RemoteSigner) Public() crypto.PublicKey {
+	return r.public
+}
+
+// SignDigest - This method sends the digest to the server to be signed.
+func (r *RemoteSigner) SignDigest(digest []byte) ([]byte, error) {
+	resp, err := r.call(request{Op: "sign", Digest: digest})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// call - This method sends one request to the server and reads the response.
+func (r *RemoteSigner) call(req request) (*response, error) {
+	conn, err := net.Dial(r.network, r.addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// serve - This method accepts connections until the listener is closed.
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			json.NewEncoder(conn).Encode(s.handle(conn))
+		}()
+	}
+}
+
+// handle - This method reads and answers one request.
+func (s *Server) handle(conn net.Conn) response {
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return response{Error: err.Error()}
+	}
+
+	switch req.Op {
+	case "info":
+		sig := signature.Signature{Algorithm: s.signer.Algorithm()}
+		if err := sig.SetPublicKey(s.signer.Public()); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Algorithm: sig.Algorithm, PublicKey: sig.PublicKey}
+
+	case "sign":
+		s.mu.Lock()
+		s.requests++
+		s.mu.Unlock()
+
+		value, err := s.signer.SignDigest(req.Digest)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Signature: value}
+	}
+	return response{Error: "unknown operation " + req.Op}
+}
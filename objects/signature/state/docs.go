@@ -0,0 +1,11 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package state implements the storage for the private key state of the
+// stateful hash-based signature schemes, LMS and XMSS. Each private key can
+// only sign with each one-time key once, so the index of the next one-time
+// key must be saved before a signature is released. If the state is lost or
+// rolled back, one-time keys will be reused and the private key can be forged.
+package state
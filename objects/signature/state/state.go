@@ -0,0 +1,120 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ----------------------------------------------------------------------
+// Define Interfaces
+// ----------------------------------------------------------------------
+
+// Store - This interface defines where the state of a stateful private key is
+// saved. Save must not return until the state is durable, since the signature
+// is only released after Save returns without an error.
+type Store interface {
+	Save(state []byte) error
+}
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// FileStore - This type saves the state to a file. The file is replaced
+// atomically, by writing and syncing a temporary file in the same directory
+// and then renaming it, so a crash will leave either the old or the new state
+// and never a partial one.
+type FileStore struct {
+	Path string
+	Mode os.FileMode
+}
+
+// MemoryStore - This type keeps the state in memory. It is only safe to use
+// for keys that do not need to survive the process, like in tests.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state []byte
+}
+
+// ----------------------------------------------------------------------
+// Initialization Functions
+// ----------------------------------------------------------------------
+
+// NewFileStore - This function will create a new file store for the path with
+// a file mode of 0600.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path, Mode: 0600}
+}
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Save - This method will atomically replace the state file.
+func (f *FileStore) Save(state []byte) error {
+	mode := f.Mode
+	if mode == 0 {
+		mode = 0600
+	}
+
+	dir := filepath.Dir(f.Path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(state); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, f.Path); err != nil {
+		return err
+	}
+
+	// Sync the directory so the rename itself is durable
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Load - This method will read the state file.
+func (f *FileStore) Load() ([]byte, error) {
+	return os.ReadFile(f.Path)
+}
+
+// Save - This method will keep a copy of the state.
+func (m *MemoryStore) Save(state []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = append([]byte(nil), state...)
+	return nil
+}
+
+// Load - This method will return a copy of the last saved state.
+func (m *MemoryStore) Load() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]byte(nil), m.state...), nil
+}
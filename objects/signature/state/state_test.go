@@ -0,0 +1,45 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package state
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFileStore - This will test that the file store replaces the state and
+// does not leave temporary files behind
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFileStore(filepath.Join(dir, "key.state"))
+
+	for i, state := range [][]byte{[]byte("first"), []byte("second")} {
+		if err := f.Save(state); err != nil {
+			t.Fatalf("1.%d unable to save the state: %s", i, err)
+		}
+		got, err := f.Load()
+		if err != nil || !bytes.Equal(got, state) {
+			t.Errorf("1.%d state was not saved correctly\nExpected: %s\nHave: %s", i, state, got)
+		}
+	}
+
+	info, err := os.Stat(f.Path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("1.2 state file does not have a mode of 0600")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("1.3 temporary files were left in the directory, found %d files", len(entries))
+	}
+
+	f = NewFileStore(filepath.Join(dir, "missing", "key.state"))
+	if err := f.Save([]byte("state")); err == nil {
+		t.Errorf("1.4 saving to a missing directory did not return an error")
+	}
+}
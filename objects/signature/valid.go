@@ -0,0 +1,404 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Valid - This method will verify that the object is correct. It will return a
+// boolean, an integer that tracks the number of problems found, and a slice of
+// strings that contain the detailed results, whether good or bad. If debug is
+// enabled, then resultDetails will contain entries for successful checks not
+// just failures. Nested countersignatures are checked as well. This does not
+// verify the signature value, use Playbook.Verify() for that.
+func (s *Signature) Valid(debug bool) (bool, int, []string) {
+	r := s.validate(debug)
+
+	// Return real values not pointers
+	if r.ProblemsFound > 0 {
+		return false, r.ProblemsFound, r.ResultDetails
+	}
+	return true, r.ProblemsFound, r.ResultDetails
+}
+
+// Check - This method will run the same checks as Valid() and return the
+// results as a list of findings. The JSON Pointer of each finding is relative
+// to the signature object.
+func (s *Signature) Check(debug bool) []objects.Finding {
+	r := s.validate(debug)
+	return r.Findings
+}
+
+// ----------------------------------------------------------------------
+// Private Common Functions
+// ----------------------------------------------------------------------
+
+// validate - This method will run each of the checks and return the results.
+func (s *Signature) validate(debug bool) *objects.Results {
+	r := new(objects.Results)
+	r.Debug = debug
+
+	// Check each property in the model
+	s.checkObjectType(r)
+	s.checkID(r)
+	s.checkCreatedBy(r)
+	s.checkCreated(r)
+	s.checkModified(r)
+	// Revoked - No requirements
+	s.checkSignee(r)
+	s.checkValidFrom(r)
+	s.checkValidUntil(r)
+	s.checkRelatedTo(r)
+	s.checkRelatedVersion(r)
+	s.checkHashAlgorithm(r)
+	s.checkAlgorithm(r)
+	s.checkKeySource(r)
+	s.checkValue(r)
+	s.checkSignature(r)
+
+	return r
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// isIDValid - This function will take in an CACAO ID and an object type and
+// check to see if it is a valid identifier for that object type.
+func isIDValid(id, objType string) bool {
+	idparts := strings.Split(id, "--")
+
+	if len(idparts) != 2 {
+		return false
+	}
+
+	// First check to see if the object type is valid, if not return false.
+	if idparts[0] != objType {
+		// Short circuit if the object type part is wrong
+		return false
+	}
+
+	// If the type is valid, then check to see if the ID is a UUID, if not return
+	// false.
+	return objects.IsUUIDValid(idparts[1])
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// Each of these methods will check a specific property. It is done this way
+// to reduce the complexity of the main valid() function. This way all of the
+// checks for each property are self contained in their own function.
+
+func (s *Signature) checkObjectType(r *objects.Results) {
+	r.At("signature.type", "type")
+
+	if s.ObjectType == "" {
+		r.RequiredButMissing("type")
+		return
+	}
+
+	r.RequiredAndFound("type")
+	if s.ObjectType != "jss" {
+		r.LogProblem("-- the type property does not contain a value of jss")
+	} else {
+		r.LogValid("++ the type property contains a valid type value of \"jss\"")
+	}
+}
+
+func (s *Signature) checkID(r *objects.Results) {
+	r.At("signature.id", "id")
+
+	if s.ID == "" {
+		r.RequiredButMissing("id")
+		return
+	}
+
+	r.RequiredAndFound("id")
+	if valid := isIDValid(s.ID, "jss"); valid == false {
+		r.LogProblem("-- the id property does not contain a valid identifier")
+	} else {
+		str := fmt.Sprintf("++ the id property contains a valid identifier value of \"%s\"", s.ID)
+		r.LogValid(str)
+	}
+}
+
+func (s *Signature) checkCreatedBy(r *objects.Results) {
+	r.At("signature.created_by", "created_by")
+
+	if s.CreatedBy != "" {
+		if valid := isIDValid(s.CreatedBy, "identity"); valid == false {
+			r.LogProblem("-- the created_by property does not contain a valid identifier")
+		} else {
+			str := fmt.Sprintf("++ the created_by property contains a valid identifier value of \"%s\"", s.CreatedBy)
+			r.LogValid(str)
+		}
+	}
+}
+
+func (s *Signature) checkCreated(r *objects.Results) {
+	r.At("signature.created", "created")
+
+	if s.Created == "" {
+		r.RequiredButMissing("created")
+		return
+	}
+
+	r.RequiredAndFound("created")
+	if valid := objects.IsTimestampValid(s.Created); valid == false {
+		r.LogProblem("-- the created property does not contain a valid timestamp")
+	} else {
+		str := fmt.Sprintf("++ the created property contains a valid timestamp value of \"%s\"", s.Created)
+		r.LogValid(str)
+	}
+}
+
+func (s *Signature) checkModified(r *objects.Results) {
+	r.At("signature.modified", "modified")
+
+	if s.Modified == "" {
+		r.RequiredButMissing("modified")
+		return
+	}
+
+	r.RequiredAndFound("modified")
+	if valid := objects.IsTimestampValid(s.Modified); valid == false {
+		r.LogProblem("-- the modified property does not contain a valid timestamp")
+	} else {
+		str := fmt.Sprintf("++ the modified property contains a valid timestamp value of \"%s\"", s.Modified)
+		r.LogValid(str)
+	}
+
+	// Make sure the modified timestamp is equal to or greater than created
+	if s.Created != "" {
+		created, _ := time.Parse(time.RFC3339, s.Created)
+		modified, _ := time.Parse(time.RFC3339, s.Modified)
+		if modified.Before(created) {
+			r.LogProblem("-- the modified timestamp is not later than or equal to the created timestamp")
+		} else {
+			r.LogValid("++ the modified timestamp is later than or equal to the created timestamp")
+		}
+	}
+}
+
+func (s *Signature) checkSignee(r *objects.Results) {
+	r.At("signature.signee", "signee")
+
+	if s.Signee == "" {
+		r.RequiredButMissing("signee")
+		return
+	}
+	r.RequiredAndFound("signee")
+}
+
+func (s *Signature) checkValidFrom(r *objects.Results) {
+	r.At("signature.valid_from", "valid_from")
+
+	if s.ValidFrom != "" {
+		if valid := objects.IsTimestampValid(s.ValidFrom); valid == false {
+			r.LogProblem("-- the valid_from property does not contain a valid timestamp")
+		} else {
+			r.LogValid("++ the valid_from property contains a valid timestamp")
+		}
+	}
+}
+
+func (s *Signature) checkValidUntil(r *objects.Results) {
+	r.At("signature.valid_until", "valid_until")
+
+	if s.ValidUntil != "" {
+		if valid := objects.IsTimestampValid(s.ValidUntil); valid == false {
+			r.LogProblem("-- the valid_until property does not contain a valid timestamp")
+		} else {
+			r.LogValid("++ the valid_until property contains a valid timestamp")
+		}
+
+		// If there is a valid_until timestamp, then lets check to see if there is
+		// also a valid_from and if so is the valid_until later than the valid_from
+		if s.ValidFrom != "" {
+			validFrom, _ := time.Parse(time.RFC3339, s.ValidFrom)
+			validUntil, _ := time.Parse(time.RFC3339, s.ValidUntil)
+			if validUntil.After(validFrom) {
+				r.LogValid("++ the valid_until timestamp is later than the valid_from timestamp")
+			} else {
+				r.LogProblem("-- the valid_until timestamp is not later than the valid_from timestamp")
+			}
+		}
+	}
+}
+
+func (s *Signature) checkRelatedTo(r *objects.Results) {
+	r.At("signature.related_to", "related_to")
+
+	if s.RelatedTo != "" {
+		if valid := isIDValid(s.RelatedTo, "playbook"); valid == false {
+			r.LogProblem("-- the related_to property does not contain a valid playbook identifier")
+		} else {
+			str := fmt.Sprintf("++ the related_to property contains a valid playbook identifier value of \"%s\"", s.RelatedTo)
+			r.LogValid(str)
+		}
+	}
+}
+
+func (s *Signature) checkRelatedVersion(r *objects.Results) {
+	r.At("signature.related_version", "related_version")
+
+	if s.RelatedVersion != "" {
+		if valid := objects.IsTimestampValid(s.RelatedVersion); valid == false {
+			r.LogProblem("-- the related_version property does not contain a valid timestamp")
+		} else {
+			r.LogValid("++ the related_version property contains a valid timestamp")
+		}
+
+		if s.RelatedTo == "" {
+			r.LogProblem("-- the related_version property is used without the related_to property")
+		}
+	}
+}
+
+func (s *Signature) checkHashAlgorithm(r *objects.Results) {
+	r.At("signature.hash_algorithm", "hash_algorithm")
+
+	if s.HashAlgorithm != "" {
+		if objects.IsVocabValueValid(s.HashAlgorithm, GetHashAlgorithmsVocab()) {
+			str := fmt.Sprintf("++ the hash_algorithm property contains a valid value of \"%s\"", s.HashAlgorithm)
+			r.LogValid(str)
+		} else {
+			str := fmt.Sprintf("-- the hash_algorithm property contains a value of \"%s\" that is not in the vocabulary", s.HashAlgorithm)
+			r.LogProblem(str)
+		}
+	}
+}
+
+func (s *Signature) checkAlgorithm(r *objects.Results) {
+	r.At("signature.algorithm", "algorithm")
+
+	if s.Algorithm == "" {
+		r.RequiredButMissing("algorithm")
+		return
+	}
+
+	r.RequiredAndFound("algorithm")
+	if objects.IsVocabValueValid(s.Algorithm, GetSigningMethodsVocab()) {
+		str := fmt.Sprintf("++ the algorithm property contains a valid value of \"%s\"", s.Algorithm)
+		r.LogValid(str)
+	} else {
+		str := fmt.Sprintf("-- the algorithm property contains a value of \"%s\" that is not in the vocabulary", s.Algorithm)
+		r.LogProblem(str)
+	}
+}
+
+// checkKeySource - Exactly one of public_key, public_cert_chain, cert_url and
+// thumbprint must be used. The thumbprint of the leaf certificate may be used
+// along with public_cert_chain, since Sign() adds both.
+func (s *Signature) checkKeySource(r *objects.Results) {
+	var used []string
+	if s.PublicKey != "" {
+		used = append(used, "public_key")
+	}
+	if len(s.PublicCertChain) > 0 {
+		used = append(used, "public_cert_chain")
+	}
+	if s.CertURL != "" {
+		used = append(used, "cert_url")
+	}
+	if s.Thumbprint != "" {
+		used = append(used, "thumbprint")
+	}
+
+	var chain []string
+	if len(s.PublicCertChain) > 0 {
+		r.At("signature.public_cert_chain", "public_cert_chain")
+		certs, err := s.ParseCertificateChain()
+		if err != nil {
+			r.LogProblem("-- the public_cert_chain property is not valid: " + err.Error())
+		} else {
+			r.LogValid("++ the public_cert_chain property contains valid certificates")
+			if s.Thumbprint != "" {
+				r.At("signature.thumbprint", "thumbprint")
+				if strings.ToLower(s.Thumbprint) == Thumbprint(certs[0]) {
+					r.LogValid("++ the thumbprint property matches the leaf certificate in the public_cert_chain property")
+					chain = []string{"thumbprint"}
+				} else {
+					r.LogProblem("-- the thumbprint property does not match the leaf certificate in the public_cert_chain property")
+				}
+			}
+		}
+	}
+
+	r.At("signature.key_source")
+	switch n := len(used) - len(chain); {
+	case n == 0:
+		r.LogProblem("-- one of the public_key, public_cert_chain, cert_url, or thumbprint properties is required but missing")
+	case n > 1:
+		str := fmt.Sprintf("-- only one of the public_key, public_cert_chain, cert_url, or thumbprint properties can be used, found %s", strings.Join(used, ", "))
+		r.LogProblem(str)
+	default:
+		r.LogValid("++ exactly one of the public_key, public_cert_chain, cert_url, or thumbprint properties is used")
+	}
+
+	if s.PublicKey != "" {
+		r.At("signature.public_key", "public_key")
+		if _, err := s.ParsePublicKey(); err != nil {
+			r.LogProblem("-- the public_key property does not contain a valid public key: " + err.Error())
+		} else {
+			r.LogValid("++ the public_key property contains a valid public key")
+		}
+	}
+
+	if s.CertURL != "" {
+		r.At("signature.cert_url", "cert_url")
+		if u, err := url.Parse(s.CertURL); err != nil || u.Scheme == "" || u.Host == "" {
+			r.LogProblem("-- the cert_url property does not contain a valid url")
+		} else {
+			r.LogValid("++ the cert_url property contains a valid url")
+		}
+	}
+
+	if s.Thumbprint != "" && len(s.PublicCertChain) == 0 {
+		r.At("signature.thumbprint", "thumbprint")
+		if len(s.Thumbprint) != 64 || strings.Trim(strings.ToLower(s.Thumbprint), "0123456789abcdef") != "" {
+			r.LogProblem("-- the thumbprint property does not contain a hex encoded SHA-256 value")
+		} else {
+			r.LogValid("++ the thumbprint property contains a hex encoded SHA-256 value")
+		}
+	}
+}
+
+func (s *Signature) checkValue(r *objects.Results) {
+	r.At("signature.value", "value")
+
+	if s.Value == "" {
+		r.RequiredButMissing("value")
+		return
+	}
+	r.RequiredAndFound("value")
+}
+
+func (s *Signature) checkSignature(r *objects.Results) {
+	if s.Signature == nil {
+		return
+	}
+
+	// The findings of the countersignature keep their own rule codes and
+	// their pointers are moved under the signature property
+	if r.LogNested(s.Signature.Check(r.Debug), "countersignature "+s.Signature.ID, "signature") {
+		r.At("signature.signature", "signature")
+		str := fmt.Sprintf("++ the countersignature %s is valid", s.Signature.ID)
+		r.LogValid(str)
+	}
+}
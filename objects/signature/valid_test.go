@@ -0,0 +1,146 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+)
+
+// newTestSignature - This will create a signature object that is valid
+func newTestSignature(t *testing.T) *Signature {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	s := New()
+	s.CreatedBy = "identity--5abe695c-7bd5-4c31-8824-2528696cdbf1"
+	s.Signee = "ACME Cyber Company"
+	s.HashAlgorithm = "sha-256"
+	s.Algorithm = "ES256"
+	s.Value = "c2lnbmF0dXJl"
+	if err := s.SetPublicKey(key.Public()); err != nil {
+		t.Fatalf("unable to set public key: %s", err)
+	}
+	return s
+}
+
+// TestValid - This will test the Valid() method
+func TestValid(t *testing.T) {
+	s := newTestSignature(t)
+	if valid, count, details := s.Valid(false); !valid || count != 0 {
+		t.Errorf("1.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check the required properties
+	empty := new(Signature)
+	if valid, count, details := empty.Valid(false); valid || count != 8 {
+		t.Errorf("1.2 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check invalid values
+	s.ObjectType = "signature"
+	s.ID = "signature--af892292-c4b4-47eb-9be6-4897ff4b9388"
+	s.Modified = "2000-01-01T00:00:00Z"
+	s.HashAlgorithm = "md5"
+	s.Algorithm = "RS1"
+	if valid, count, details := s.Valid(false); valid || count != 5 {
+		t.Errorf("1.3 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check the lifecycle properties
+	s = newTestSignature(t)
+	s.ValidFrom = "2023-06-10T17:39:31.319Z"
+	s.ValidUntil = "2023-01-10T17:39:31.319Z"
+	s.RelatedVersion = "2022-05-18T11:31:31.319Z"
+	if valid, count, details := s.Valid(false); valid || count != 2 {
+		t.Errorf("1.4 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check that exactly one key source is used
+	s = newTestSignature(t)
+	s.CertURL = "https://example.com/cert.pem"
+	if valid, count, details := s.Valid(false); valid || count != 1 {
+		t.Errorf("1.5 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	s.PublicKey = ""
+	s.CertURL = "cert.pem"
+	if valid, count, details := s.Valid(false); valid || count != 1 {
+		t.Errorf("1.6 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// The thumbprint of the leaf certificate can be used with the chain
+	cert, _ := newTestCert(t, "Signer", nil, nil, x509.Certificate{})
+	s = newTestSignature(t)
+	s.PublicKey = ""
+	if err := s.SetCertificateChain([]*x509.Certificate{cert}); err != nil {
+		t.Fatalf("1.7 unable to set the certificate chain: %s", err)
+	}
+	if valid, count, details := s.Valid(false); !valid || count != 0 {
+		t.Errorf("1.8 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	s.Thumbprint = "0000000000000000000000000000000000000000000000000000000000000000"
+	if valid, count, details := s.Valid(false); valid || count != 2 {
+		t.Errorf("1.9 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check that countersignatures are validated
+	s = newTestSignature(t)
+	s.Signature = newTestSignature(t)
+	s.Signature.Signee = ""
+	if valid, count, details := s.Valid(false); valid || count != 1 {
+		t.Errorf("1.10 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+}
+
+// TestSetters - This will test the lifecycle setters
+func TestSetters(t *testing.T) {
+	s := New()
+
+	if err := s.SetValidFrom("2023-06-10T17:39:31.319Z"); err != nil {
+		t.Errorf("2.1 SetValidFrom returned an error: %s", err)
+	}
+	if err := s.SetValidUntil("2023-01-10T17:39:31.319Z"); err == nil {
+		t.Errorf("2.2 SetValidUntil accepted a timestamp before valid_from")
+	}
+	if err := s.SetValidUntil("2023-12-10T17:39:31.319Z"); err != nil {
+		t.Errorf("2.3 SetValidUntil returned an error: %s", err)
+	}
+	if err := s.SetValidFrom("2024-01-10T17:39:31.319Z"); err == nil {
+		t.Errorf("2.4 SetValidFrom accepted a timestamp after valid_until")
+	}
+
+	if err := s.SetRelatedVersion("2022-05-18T11:31:31.319Z"); err == nil {
+		t.Errorf("2.5 SetRelatedVersion accepted a value without related_to")
+	}
+	if err := s.SetRelatedTo("identity--a0777575-5c4c-4710-9f01-15776103837f"); err == nil {
+		t.Errorf("2.6 SetRelatedTo accepted an identifier that is not a playbook")
+	}
+	if err := s.SetRelatedTo("playbook--a0777575-5c4c-4710-9f01-15776103837f"); err != nil {
+		t.Errorf("2.7 SetRelatedTo returned an error: %s", err)
+	}
+	if err := s.SetRelatedVersion("2022-05-18T11:31:31.319Z"); err != nil {
+		t.Errorf("2.8 SetRelatedVersion returned an error: %s", err)
+	}
+}
+
+// TestDecode - This will test decoding a signature object
+func TestDecode(t *testing.T) {
+	s := newTestSignature(t)
+	data, _ := s.Encode()
+
+	s2, err := Decode(data)
+	if err != nil {
+		t.Fatalf("3.1 Decode returned an error: %s", err)
+	}
+	if s2.ID != s.ID || s2.PublicKey != s.PublicKey {
+		t.Errorf("3.2 Decode did not round trip the signature object")
+	}
+	if _, err := Decode([]byte(`{"type": 1}`)); err == nil {
+		t.Errorf("3.3 Decode accepted invalid JSON")
+	}
+}
@@ -6,7 +6,8 @@
 package signature
 
 // GetSigningMethodsVocab - This will return a slice of officially supported
-// signing methods
+// signing methods. The LMS heights 20 and 25 and XMSS-SHA2_20_256 are left
+// out, since private keys that large can not be loaded.
 func GetSigningMethodsVocab() []string {
 	return []string{
 		"RS256",
@@ -22,11 +23,22 @@ func GetSigningMethodsVocab() []string {
 		"Ed448",
 		"XMSS-SHA2_10_256",
 		"XMSS-SHA2_16_256",
-		"XMSS-SHA2_20_256",
 		"LMS_SHA256_M32_H5",
 		"LMS_SHA256_M32_H10",
 		"LMS_SHA256_M32_H15",
-		"LMS_SHA256_M32_H20",
-		"LMS_SHA256_M32_H25",
+	}
+}
+
+// GetHashAlgorithmsVocab - This will return a slice of officially supported
+// hash algorithms, using the names from the IANA Named Information Hash
+// Algorithm Registry
+func GetHashAlgorithmsVocab() []string {
+	return []string{
+		"sha-256",
+		"sha-384",
+		"sha-512",
+		"sha3-256",
+		"sha3-384",
+		"sha3-512",
 	}
 }
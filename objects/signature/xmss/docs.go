@@ -0,0 +1,21 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+// Package xmss implements the eXtended Merkle Signature Scheme from RFC 8391
+// for the single tree SHA-256 parameter sets XMSS-SHA2_10_256,
+// XMSS-SHA2_16_256 and XMSS-SHA2_20_256. XMSS is a stateful scheme, each
+// private key contains 2^h one-time WOTS+ keys and every signature uses the
+// next one. The private key saves its state to a state.Store before each
+// signature is returned, so the same one-time key is never used twice, even
+// if the process stops while signing.
+//
+// The WOTS+ private keys are derived from a secret seed with a keyed hash, as
+// done by the reference implementation, so the private key state is small.
+// The whole Merkle tree is kept in memory and it is built when a key is
+// generated or parsed, which takes a long time for a height of 16 and is not
+// practical for a height of 20, so XMSS-SHA2_20_256 private keys are not
+// supported and it is not in the signing methods vocabulary. Signatures from
+// keys of any height can be verified.
+package xmss
@@ -0,0 +1,244 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package xmss
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// This file implements the addresses, the keyed hash functions and WOTS+ from
+// RFC 8391 sections 2 and 3 for SHA-256 with n = 32 and w = 16.
+
+const (
+	n    = 32
+	w    = 16
+	logW = 4
+	len1 = 64
+	len2 = 3
+	wLen = len1 + len2
+)
+
+// The padding values that separate the keyed hash functions.
+const (
+	padF      = 0
+	padH      = 1
+	padHMsg   = 2
+	padPRF    = 3
+	padKeygen = 4
+)
+
+// The address types from RFC 8391 section 2.5.
+const (
+	addrOTS   = 0
+	addrLTree = 1
+	addrTree  = 2
+)
+
+// address - This type is the 32 byte hash address from RFC 8391 section 2.5.
+type address [8]uint32
+
+// ----------------------------------------------------------------------
+// Private Address Methods
+// ----------------------------------------------------------------------
+
+// setType - This method sets the type and clears the fields that follow it.
+func (a *address) setType(t uint32) {
+	a[3] = t
+	a[4], a[5], a[6], a[7] = 0, 0, 0, 0
+}
+
+func (a *address) setOTS(i uint32)        { a[4] = i }
+func (a *address) setChain(i uint32)      { a[5] = i }
+func (a *address) setHash(i uint32)       { a[6] = i }
+func (a *address) setLTree(i uint32)      { a[4] = i }
+func (a *address) setTreeHeight(i uint32) { a[5] = i }
+func (a *address) setTreeIndex(i uint32)  { a[6] = i }
+func (a *address) setKeyAndMask(i uint32) { a[7] = i }
+func (a *address) treeHeight() uint32     { return a[5] }
+func (a *address) treeIndex() uint32      { return a[6] }
+
+// bytes - This method encodes the address.
+func (a *address) bytes() []byte {
+	out := make([]byte, 32)
+	for i, v := range a {
+		binary.BigEndian.PutUint32(out[i*4:], v)
+	}
+	return out
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// keyedHash - This function computes SHA-256(toByte(pad, 32) || key || m).
+func keyedHash(pad byte, key []byte, m ...[]byte) []byte {
+	var prefix [n]byte
+	prefix[n-1] = pad
+
+	h := sha256.New()
+	h.Write(prefix[:])
+	h.Write(key)
+	for _, v := range m {
+		h.Write(v)
+	}
+	return h.Sum(nil)
+}
+
+// prf - This function is the PRF from RFC 8391 section 5.1.
+func prf(key []byte, m []byte) []byte {
+	return keyedHash(padPRF, key, m)
+}
+
+// toByte - This function encodes x as a big-endian string of y bytes.
+func toByte(x uint64, y int) []byte {
+	out := make([]byte, y)
+	for i := y - 1; i >= 0 && x > 0; i-- {
+		out[i] = byte(x)
+		x >>= 8
+	}
+	return out
+}
+
+// xor - This function returns a XOR b.
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// chain - This function is the WOTS+ chaining function from RFC 8391 section
+// 3.1.2. It applies s steps of the chain to x, starting at step i.
+func chain(x []byte, i, s uint32, seed []byte, adrs *address) []byte {
+	tmp := append([]byte(nil), x...)
+	for j := i; j < i+s; j++ {
+		adrs.setHash(j)
+		adrs.setKeyAndMask(0)
+		key := prf(seed, adrs.bytes())
+		adrs.setKeyAndMask(1)
+		mask := prf(seed, adrs.bytes())
+		tmp = keyedHash(padF, key, xor(tmp, mask))
+	}
+	return tmp
+}
+
+// baseW - This function splits x into outLen base w digits, from RFC 8391
+// section 2.6.
+func baseW(x []byte, outLen int) []uint32 {
+	out := make([]uint32, outLen)
+	in, bits := 0, 0
+	var total byte
+	for i := range out {
+		if bits == 0 {
+			total = x[in]
+			in++
+			bits = 8
+		}
+		bits -= logW
+		out[i] = uint32(total>>uint(bits)) & (w - 1)
+	}
+	return out
+}
+
+// wotsDigits - This function returns the base w digits of the message with
+// the checksum, as used by WOTS_sign and WOTS_pkFromSig.
+func wotsDigits(m []byte) []uint32 {
+	msg := baseW(m, len1)
+
+	var csum uint32
+	for _, v := range msg {
+		csum += w - 1 - v
+	}
+	csum <<= 8 - ((len2 * logW) % 8)
+	return append(msg, baseW(toByte(uint64(csum), (len2*logW+7)/8), len2)...)
+}
+
+// wotsSecret - This function derives the i'th WOTS+ private key element for the
+// address from the secret seed.
+func wotsSecret(skSeed, seed []byte, adrs *address, i uint32) []byte {
+	a := *adrs
+	a.setChain(i)
+	a.setHash(0)
+	a.setKeyAndMask(0)
+	return keyedHash(padKeygen, skSeed, seed, a.bytes())
+}
+
+// wotsPublicKey - This function is WOTS_genPK from RFC 8391 section 3.1.4.
+func wotsPublicKey(skSeed, seed []byte, adrs *address) [][]byte {
+	pk := make([][]byte, wLen)
+	for i := range pk {
+		adrs.setChain(uint32(i))
+		pk[i] = chain(wotsSecret(skSeed, seed, adrs, uint32(i)), 0, w-1, seed, adrs)
+	}
+	return pk
+}
+
+// wotsSign - This function is WOTS_sign from RFC 8391 section 3.1.5.
+func wotsSign(m, skSeed, seed []byte, adrs *address) []byte {
+	digits := wotsDigits(m)
+	sig := make([]byte, 0, wLen*n)
+	for i, d := range digits {
+		adrs.setChain(uint32(i))
+		sig = append(sig, chain(wotsSecret(skSeed, seed, adrs, uint32(i)), 0, d, seed, adrs)...)
+	}
+	return sig
+}
+
+// wotsPublicKeyFromSig - This function is WOTS_pkFromSig from RFC 8391
+// section 3.1.6.
+func wotsPublicKeyFromSig(m, sig, seed []byte, adrs *address) [][]byte {
+	digits := wotsDigits(m)
+	pk := make([][]byte, wLen)
+	for i, d := range digits {
+		adrs.setChain(uint32(i))
+		pk[i] = chain(sig[i*n:(i+1)*n], d, w-1-d, seed, adrs)
+	}
+	return pk
+}
+
+// randHash - This function is RAND_HASH from RFC 8391 section 4.1.4.
+func randHash(left, right, seed []byte, adrs *address) []byte {
+	adrs.setKeyAndMask(0)
+	key := prf(seed, adrs.bytes())
+	adrs.setKeyAndMask(1)
+	mask0 := prf(seed, adrs.bytes())
+	adrs.setKeyAndMask(2)
+	mask1 := prf(seed, adrs.bytes())
+	return keyedHash(padH, key, xor(left, mask0), xor(right, mask1))
+}
+
+// lTree - This function compresses a WOTS+ public key into a leaf, from RFC
+// 8391 section 4.1.5.
+func lTree(pk [][]byte, seed []byte, adrs *address) []byte {
+	l := len(pk)
+	adrs.setTreeHeight(0)
+	for l > 1 {
+		for i := 0; i < l/2; i++ {
+			adrs.setTreeIndex(uint32(i))
+			pk[i] = randHash(pk[2*i], pk[2*i+1], seed, adrs)
+		}
+		if l%2 == 1 {
+			pk[l/2] = pk[l-1]
+		}
+		l = (l + 1) / 2
+		adrs.setTreeHeight(adrs.treeHeight() + 1)
+	}
+	return pk[0]
+}
+
+// leaf - This function computes the leaf of the tree for the one-time key i.
+func leaf(skSeed, seed []byte, i uint32) []byte {
+	var adrs address
+	adrs.setType(addrOTS)
+	adrs.setOTS(i)
+	pk := wotsPublicKey(skSeed, seed, &adrs)
+
+	adrs.setType(addrLTree)
+	adrs.setLTree(i)
+	return lTree(pk, seed, &adrs)
+}
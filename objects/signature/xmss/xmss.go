@@ -0,0 +1,429 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package xmss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/openplaybooks/libcacao/objects/signature/state"
+)
+
+// OID - This type identifies an XMSS parameter set.
+type OID uint32
+
+// These are the XMSS parameter sets from RFC 8391 section 5.3.
+const (
+	SHA2_10_256 OID = 1
+	SHA2_16_256 OID = 2
+	SHA2_20_256 OID = 3
+)
+
+// These errors are returned when a private key can not be created or used to
+// sign.
+var (
+	ErrKeyExhausted      = errors.New("xmss: all of the one-time keys have been used")
+	ErrNoStore           = errors.New("xmss: the private key does not have a state store")
+	ErrUnsupportedHeight = errors.New("xmss: private keys with a height above 16 are not supported")
+)
+
+// maxPrivateHeight is the largest tree height that a private key can have.
+// The whole tree is built when a private key is generated or parsed, which is
+// too slow and uses too much memory for a height of 20. Public keys of any
+// height can still be used to verify.
+const maxPrivateHeight = 16
+
+const (
+	// PublicKeySize is the size, in bytes, of public keys.
+	PublicKeySize = 4 + 2*n
+
+	// privateKeySize is the size, in bytes, of the private key state.
+	privateKeySize = 4 + 4 + 4*n
+)
+
+// ----------------------------------------------------------------------
+// Define Object Model
+// ----------------------------------------------------------------------
+
+// PublicKey - This type is an XMSS public key.
+type PublicKey struct {
+	oid  OID
+	root []byte
+	seed []byte
+}
+
+// PrivateKey - This type is an XMSS private key. It holds the index of the next
+// one-time key and the store where that index is saved. A PrivateKey is safe
+// for concurrent use.
+type PrivateKey struct {
+	mu     sync.Mutex
+	oid    OID
+	idx    uint32
+	skSeed []byte
+	skPRF  []byte
+	seed   []byte
+	tree   []byte
+	store  state.Store
+}
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// OIDFromName - This function returns the parameter set for a name like
+// "XMSS-SHA2_10_256".
+func OIDFromName(name string) (OID, error) {
+	for o := SHA2_10_256; o <= SHA2_20_256; o++ {
+		if o.String() == name {
+			return o, nil
+		}
+	}
+	return 0, fmt.Errorf("xmss: unknown parameter set %s", name)
+}
+
+// GenerateKey - This function generates a new private key with entropy from
+// random. If random is nil, crypto/rand.Reader will be used. If the store is
+// not nil the initial state is saved to it. A height of 20 returns
+// ErrUnsupportedHeight.
+func GenerateKey(random io.Reader, oid OID, store state.Store) (*PrivateKey, error) {
+	if oid.Height() == 0 {
+		return nil, fmt.Errorf("xmss: unknown parameter set %d", oid)
+	}
+	if oid.Height() > maxPrivateHeight {
+		return nil, ErrUnsupportedHeight
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	priv := &PrivateKey{
+		oid:    oid,
+		skSeed: make([]byte, n),
+		skPRF:  make([]byte, n),
+		seed:   make([]byte, n),
+		store:  store,
+	}
+	for _, b := range [][]byte{priv.skSeed, priv.skPRF, priv.seed} {
+		if _, err := io.ReadFull(random, b); err != nil {
+			return nil, err
+		}
+	}
+
+	priv.buildTree()
+	if store != nil {
+		if err := store.Save(priv.marshal(0)); err != nil {
+			return nil, err
+		}
+	}
+	return priv, nil
+}
+
+// ParsePrivateKey - This function decodes the private key state created by
+// MarshalBinary and rebuilds the tree. Future signatures will save the state
+// to the store. A height of 20 returns ErrUnsupportedHeight.
+func ParsePrivateKey(data []byte, store state.Store) (*PrivateKey, error) {
+	if len(data) != privateKeySize {
+		return nil, errors.New("xmss: the private key is not the correct size")
+	}
+
+	priv := &PrivateKey{
+		oid:    OID(binary.BigEndian.Uint32(data[0:])),
+		idx:    binary.BigEndian.Uint32(data[4:]),
+		skSeed: append([]byte(nil), data[8:8+n]...),
+		skPRF:  append([]byte(nil), data[8+n:8+2*n]...),
+		seed:   append([]byte(nil), data[8+2*n:8+3*n]...),
+		store:  store,
+	}
+	if priv.oid.Height() == 0 {
+		return nil, fmt.Errorf("xmss: unknown parameter set %d", priv.oid)
+	}
+	if priv.oid.Height() > maxPrivateHeight {
+		return nil, ErrUnsupportedHeight
+	}
+
+	priv.buildTree()
+	if !bytes.Equal(priv.root(), data[8+3*n:]) {
+		return nil, errors.New("xmss: the private key does not match its root")
+	}
+	return priv, nil
+}
+
+// ParsePublicKey - This function decodes a public key in the format from RFC
+// 8391 appendix B.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	if len(data) != PublicKeySize {
+		return nil, errors.New("xmss: the public key is not the correct size")
+	}
+
+	pub := &PublicKey{
+		oid:  OID(binary.BigEndian.Uint32(data)),
+		root: append([]byte(nil), data[4:4+n]...),
+		seed: append([]byte(nil), data[4+n:]...),
+	}
+	if pub.oid.Height() == 0 {
+		return nil, fmt.Errorf("xmss: unknown parameter set %d", pub.oid)
+	}
+	return pub, nil
+}
+
+// ----------------------------------------------------------------------
+// Public OID Methods
+// ----------------------------------------------------------------------
+
+// String - This method returns the name of the parameter set.
+func (o OID) String() string {
+	if h := o.Height(); h != 0 {
+		return fmt.Sprintf("XMSS-SHA2_%d_256", h)
+	}
+	return "XMSS-UNKNOWN"
+}
+
+// Height - This method returns the height of the tree, or zero if the
+// parameter set is not known.
+func (o OID) Height() int {
+	switch o {
+	case SHA2_10_256:
+		return 10
+	case SHA2_16_256:
+		return 16
+	case SHA2_20_256:
+		return 20
+	}
+	return 0
+}
+
+// SignatureSize - This method returns the size, in bytes, of signatures.
+func (o OID) SignatureSize() int {
+	return 4 + n + wLen*n + o.Height()*n
+}
+
+// ----------------------------------------------------------------------
+// Public PublicKey Methods
+// ----------------------------------------------------------------------
+
+// OID - This method returns the XMSS parameter set of the key.
+func (pub *PublicKey) OID() OID {
+	return pub.oid
+}
+
+// Bytes - This method encodes the public key in the format from RFC 8391
+// appendix B.
+func (pub *PublicKey) Bytes() []byte {
+	out := binary.BigEndian.AppendUint32(make([]byte, 0, PublicKeySize), uint32(pub.oid))
+	out = append(out, pub.root...)
+	return append(out, pub.seed...)
+}
+
+// Equal - This method reports whether the public key has the same value as x.
+func (pub *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(pub.Bytes(), xx.Bytes())
+}
+
+// Verify - This method reports whether sig is a valid XMSS signature of the
+// message, following RFC 8391 section 4.1.10.
+func (pub *PublicKey) Verify(message, sig []byte) bool {
+	h := pub.oid.Height()
+	if h == 0 || len(sig) != pub.oid.SignatureSize() {
+		return false
+	}
+
+	idx := binary.BigEndian.Uint32(sig)
+	if uint64(idx) >= uint64(1)<<uint(h) {
+		return false
+	}
+	r := sig[4 : 4+n]
+	sigOTS := sig[4+n : 4+n+wLen*n]
+	auth := sig[4+n+wLen*n:]
+
+	m := keyedHash(padHMsg, append(append(append([]byte(nil), r...), pub.root...), toByte(uint64(idx), n)...), message)
+
+	var adrs address
+	adrs.setType(addrOTS)
+	adrs.setOTS(idx)
+	pk := wotsPublicKeyFromSig(m, sigOTS, pub.seed, &adrs)
+
+	adrs.setType(addrLTree)
+	adrs.setLTree(idx)
+	node := lTree(pk, pub.seed, &adrs)
+
+	adrs.setType(addrTree)
+	adrs.setTreeIndex(idx)
+	for k := 0; k < h; k++ {
+		adrs.setTreeHeight(uint32(k))
+		sibling := auth[k*n : (k+1)*n]
+		if (idx>>uint(k))%2 == 0 {
+			adrs.setTreeIndex(adrs.treeIndex() / 2)
+			node = randHash(node, sibling, pub.seed, &adrs)
+		} else {
+			adrs.setTreeIndex((adrs.treeIndex() - 1) / 2)
+			node = randHash(sibling, node, pub.seed, &adrs)
+		}
+	}
+	return subtle.ConstantTimeCompare(node, pub.root) == 1
+}
+
+// ----------------------------------------------------------------------
+// Public PrivateKey Methods
+// ----------------------------------------------------------------------
+
+// OID - This method returns the XMSS parameter set of the key.
+func (priv *PrivateKey) OID() OID {
+	return priv.oid
+}
+
+// Public - This method returns the public key for the private key.
+func (priv *PrivateKey) Public() crypto.PublicKey {
+	return priv.PublicKey()
+}
+
+// PublicKey - This method returns the public key for the private key.
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{
+		oid:  priv.oid,
+		root: append([]byte(nil), priv.root()...),
+		seed: append([]byte(nil), priv.seed...),
+	}
+}
+
+// SetStore - This method sets the store where the state is saved.
+func (priv *PrivateKey) SetStore(store state.Store) {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	priv.store = store
+}
+
+// Remaining - This method returns the number of signatures that the key can
+// still create.
+func (priv *PrivateKey) Remaining() uint64 {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	return uint64(1)<<uint(priv.oid.Height()) - uint64(priv.idx)
+}
+
+// MarshalBinary - This method encodes the private key state. The encoding
+// contains the secret seeds and must be protected.
+func (priv *PrivateKey) MarshalBinary() ([]byte, error) {
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+	return priv.marshal(priv.idx), nil
+}
+
+// Sign - This method implements crypto.Signer, following RFC 8391 section
+// 4.1.9. The message must not be hashed and opts.HashFunc() must return zero.
+// The new state is saved to the store before the signature is created, if
+// that fails no signature is returned. XMSS signatures are deterministic so
+// random is not used.
+func (priv *PrivateKey) Sign(random io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("xmss: cannot sign hashed message")
+	}
+
+	priv.mu.Lock()
+	defer priv.mu.Unlock()
+
+	if priv.store == nil {
+		return nil, ErrNoStore
+	}
+	h := priv.oid.Height()
+	idx := priv.idx
+	if uint64(idx) >= uint64(1)<<uint(h) {
+		return nil, ErrKeyExhausted
+	}
+
+	// Reserve the one-time key before it is used
+	if err := priv.store.Save(priv.marshal(idx + 1)); err != nil {
+		return nil, fmt.Errorf("xmss: unable to save the private key state: %w", err)
+	}
+	priv.idx = idx + 1
+
+	r := prf(priv.skPRF, toByte(uint64(idx), 32))
+	m := keyedHash(padHMsg, append(append(append([]byte(nil), r...), priv.root()...), toByte(uint64(idx), n)...), message)
+
+	var adrs address
+	adrs.setType(addrOTS)
+	adrs.setOTS(idx)
+
+	sig := make([]byte, 0, priv.oid.SignatureSize())
+	sig = binary.BigEndian.AppendUint32(sig, idx)
+	sig = append(sig, r...)
+	sig = append(sig, wotsSign(m, priv.skSeed, priv.seed, &adrs)...)
+
+	node := uint32(1)<<uint(h) + idx
+	for k := 0; k < h; k++ {
+		sibling := (node >> uint(k)) ^ 1
+		sig = append(sig, priv.tree[int(sibling)*n:int(sibling+1)*n]...)
+	}
+	return sig, nil
+}
+
+// ----------------------------------------------------------------------
+// Private PrivateKey Methods
+// ----------------------------------------------------------------------
+
+// root - This method returns the root of the tree.
+func (priv *PrivateKey) root() []byte {
+	return priv.tree[n : 2*n]
+}
+
+// marshal - This method encodes the private key state with the index idx.
+func (priv *PrivateKey) marshal(idx uint32) []byte {
+	out := make([]byte, 0, privateKeySize)
+	out = binary.BigEndian.AppendUint32(out, uint32(priv.oid))
+	out = binary.BigEndian.AppendUint32(out, idx)
+	out = append(out, priv.skSeed...)
+	out = append(out, priv.skPRF...)
+	out = append(out, priv.seed...)
+	return append(out, priv.root()...)
+}
+
+// buildTree - This method computes every node of the tree, the same values as
+// treeHash from RFC 8391 section 4.1.6. The node at height k with index i is
+// stored at position 2^(h-k) + i, so the root is at position 1. The leaves are
+// the expensive part, so they are computed in parallel.
+func (priv *PrivateKey) buildTree() {
+	h := uint(priv.oid.Height())
+	leaves := uint32(1) << h
+	priv.tree = make([]byte, 2*int(leaves)*n)
+
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker uint32) {
+			defer wg.Done()
+			for i := worker; i < leaves; i += uint32(workers) {
+				copy(priv.tree[int(leaves+i)*n:], leaf(priv.skSeed, priv.seed, i))
+			}
+		}(uint32(worker))
+	}
+	wg.Wait()
+
+	var adrs address
+	adrs.setType(addrTree)
+	for k := uint(0); k < h; k++ {
+		first := leaves >> (k + 1)
+		for i := uint32(0); i < first; i++ {
+			adrs.setTreeHeight(uint32(k))
+			adrs.setTreeIndex(i)
+			p := first + i
+			left := priv.tree[int(2*p)*n : int(2*p+1)*n]
+			right := priv.tree[int(2*p+1)*n : int(2*p+2)*n]
+			copy(priv.tree[int(p)*n:], randHash(left, right, priv.seed, &adrs))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package xmss
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/openplaybooks/libcacao/objects/signature/state"
+)
+
+type failingStore struct{}
+
+func (failingStore) Save(state []byte) error {
+	return errors.New("disk full")
+}
+
+// TestSignVerify - This will test signing, verifying and reloading a key from
+// its saved state
+func TestSignVerify(t *testing.T) {
+	message := []byte("a playbook to sign")
+	store := &state.MemoryStore{}
+
+	priv, err := GenerateKey(nil, SHA2_10_256, store)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	pub := priv.PublicKey()
+
+	for i := 0; i < 3; i++ {
+		sig, err := priv.Sign(nil, message, nil)
+		if err != nil {
+			t.Fatalf("1.%d unable to sign: %s", i, err)
+		}
+		if len(sig) != SHA2_10_256.SignatureSize() {
+			t.Errorf("1.%d signature is %d bytes, expected %d", i, len(sig), SHA2_10_256.SignatureSize())
+		}
+		if !pub.Verify(message, sig) {
+			t.Errorf("1.%d valid signature was not verified", i)
+		}
+		if pub.Verify([]byte("a different playbook"), sig) {
+			t.Errorf("1.%d signature of a different message was verified", i)
+		}
+		sig[len(sig)-1] ^= 1
+		if pub.Verify(message, sig) {
+			t.Errorf("1.%d modified signature was verified", i)
+		}
+	}
+
+	parsed, err := ParsePublicKey(pub.Bytes())
+	if err != nil || !parsed.Equal(pub) {
+		t.Errorf("1.3 public key did not round trip")
+	}
+
+	saved, _ := store.Load()
+	reloaded, err := ParsePrivateKey(saved, store)
+	if err != nil {
+		t.Fatalf("1.4 unable to parse the saved state: %s", err)
+	}
+	if reloaded.Remaining() != 1021 {
+		t.Errorf("1.5 reloaded key should have 1021 signatures remaining, has %d", reloaded.Remaining())
+	}
+
+	reloaded.SetStore(failingStore{})
+	if sig, err := reloaded.Sign(nil, message, nil); err == nil || sig != nil {
+		t.Errorf("1.6 a signature was returned when the state could not be saved")
+	}
+
+	reloaded.SetStore(store)
+	sig, err := reloaded.Sign(nil, message, nil)
+	if err != nil || !pub.Verify(message, sig) {
+		t.Errorf("1.7 reloaded key did not create a valid signature")
+	}
+
+	reloaded.idx = 1 << 10
+	if _, err := reloaded.Sign(nil, message, nil); !errors.Is(err, ErrKeyExhausted) {
+		t.Errorf("1.8 signing with an exhausted key should return ErrKeyExhausted, got %v", err)
+	}
+}
+
+// TestRFC8391Functions - This will test the functions against their
+// definitions in RFC 8391. The RFC does not have test vectors for whole
+// signatures, only the base_w example in section 2.6.
+func TestRFC8391Functions(t *testing.T) {
+	// base_w(0x1234, 16, 4) from RFC 8391 section 2.6
+	if got := baseW([]byte{0x12, 0x34}, 4); len(got) != 4 || got[0] != 1 || got[1] != 2 || got[2] != 3 || got[3] != 4 {
+		t.Errorf("2.1 base_w of 0x1234 returned %v instead of [1 2 3 4]", got)
+	}
+
+	// The keyed hash functions from RFC 8391 section 5.1 are SHA-256 over
+	// toByte(x, 32), the key and the message, where x is 0 for F, 1 for H, 2
+	// for H_msg and 3 for PRF
+	key := bytes.Repeat([]byte{0xaa}, n)
+	m := []byte("message")
+	for i, pad := range []byte{padF, padH, padHMsg, padPRF} {
+		want := sha256.Sum256(append(append(toByte(uint64(i), 32), key...), m...))
+		if !bytes.Equal(keyedHash(pad, key, m), want[:]) {
+			t.Errorf("2.2.%d the keyed hash with padding %d is not correct", i, pad)
+		}
+	}
+	if want := sha256.Sum256(append(append(toByte(3, 32), key...), m...)); !bytes.Equal(prf(key, m), want[:]) {
+		t.Errorf("2.3 PRF is not correct")
+	}
+
+	// The address from RFC 8391 section 2.5 is eight big-endian words, and
+	// setting the type clears the words that follow it
+	var adrs address
+	adrs[0], adrs[1], adrs[2] = 1, 2, 3
+	adrs.setType(addrLTree)
+	adrs.setLTree(5)
+	adrs.setTreeHeight(6)
+	adrs.setTreeIndex(7)
+	adrs.setKeyAndMask(2)
+	want := []byte{0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 1, 0, 0, 0, 5, 0, 0, 0, 6, 0, 0, 0, 7, 0, 0, 0, 2}
+	if !bytes.Equal(adrs.bytes(), want) {
+		t.Errorf("2.4 the address is not correct %x", adrs.bytes())
+	}
+	adrs.setType(addrTree)
+	if adrs[4] != 0 || adrs[5] != 0 || adrs[6] != 0 || adrs[7] != 0 {
+		t.Errorf("2.5 setting the type did not clear the address %v", adrs)
+	}
+
+	// The WOTS+ checksum from RFC 8391 section 3.1.5 for a message of zeros is
+	// len_1 * (w - 1) = 960, shifted left by 4 bits
+	digits := wotsDigits(make([]byte, n))
+	if len(digits) != wLen || digits[len1] != 3 || digits[len1+1] != 12 || digits[len1+2] != 0 {
+		t.Errorf("2.6 the checksum digits are not correct %v", digits[len1:])
+	}
+}
+
+// TestUnsupportedHeight - This will test that private keys for a height of 20
+// are rejected, while their public keys can still be parsed
+func TestUnsupportedHeight(t *testing.T) {
+	if _, err := GenerateKey(nil, SHA2_20_256, nil); !errors.Is(err, ErrUnsupportedHeight) {
+		t.Errorf("3.1 GenerateKey returned %v instead of ErrUnsupportedHeight", err)
+	}
+
+	data := make([]byte, privateKeySize)
+	data[3] = byte(SHA2_20_256)
+	if _, err := ParsePrivateKey(data, nil); !errors.Is(err, ErrUnsupportedHeight) {
+		t.Errorf("3.2 ParsePrivateKey returned %v instead of ErrUnsupportedHeight", err)
+	}
+	if _, err := ParsePublicKey(data[:PublicKeySize]); err != nil {
+		t.Errorf("3.3 ParsePublicKey returned an error: %s", err)
+	}
+}
+
+// TestSignatureVector - This will test a signature from a key made with fixed
+// seeds. XMSS signatures are deterministic, so the public key and the hash of
+// the signature are known answers. The signature is checked against the
+// public key parsed from its RFC 8391 appendix B encoding.
+func TestSignatureVector(t *testing.T) {
+	seeds := make([]byte, 3*n)
+	for i := range seeds {
+		seeds[i] = byte(i)
+	}
+	priv, err := GenerateKey(bytes.NewReader(seeds), SHA2_10_256, &state.MemoryStore{})
+	if err != nil {
+		t.Fatalf("4.1 unable to generate key: %s", err)
+	}
+
+	public, _ := hex.DecodeString("000000019d898033e37af48e6a116f8b15651cc26773467007ad19375d38c23c690c3483404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f")
+	if !bytes.Equal(priv.PublicKey().Bytes(), public) {
+		t.Errorf("4.2 the public key is not the known answer %x", priv.PublicKey().Bytes())
+	}
+	pub, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatalf("4.3 unable to parse the public key: %s", err)
+	}
+
+	message := []byte("a playbook to sign")
+	sig, err := priv.Sign(nil, message, nil)
+	if err != nil {
+		t.Fatalf("4.4 unable to sign: %s", err)
+	}
+	start, _ := hex.DecodeString("0000000011c3e8f92a6565812dad1b5e748d117a17f1f9f07336cf6c1eaa3a2b77071cb2")
+	if !bytes.Equal(sig[:4+n], start) {
+		t.Errorf("4.5 the index and randomness of the signature are not the known answer %x", sig[:4+n])
+	}
+	if hash := sha256.Sum256(sig); hex.EncodeToString(hash[:]) != "042584fe70488ba059fbf5ba30934a84ec3e26132ec8186a6a1841c033636adb" {
+		t.Errorf("4.6 the signature is not the known answer, its hash is %x", hash)
+	}
+	if !pub.Verify(message, sig) {
+		t.Errorf("4.7 the signature was not verified with the parsed public key")
+	}
+	sig[4+n] ^= 1
+	if pub.Verify(message, sig) {
+		t.Errorf("4.8 a modified signature was verified")
+	}
+}
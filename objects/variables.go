@@ -0,0 +1,339 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ----------------------------------------------------------------------
+// Variables Type Methods
+// ----------------------------------------------------------------------
+
+// UnmarshalJSON - This method will over write the default UnmarshalJSON method
+// so that numbers in the value property are decoded as json.Number instead of
+// float64. This keeps integer and long values from losing precision.
+func (v *Variables) UnmarshalJSON(b []byte) error {
+	type alias Variables
+	temp := &struct {
+		Value json.RawMessage `json:"value,omitempty"`
+		*alias
+	}{
+		alias: (*alias)(v),
+	}
+	if err := json.Unmarshal(b, &temp); err != nil {
+		return err
+	}
+
+	v.Value = nil
+	if len(temp.Value) > 0 {
+		d := json.NewDecoder(bytes.NewReader(temp.Value))
+		d.UseNumber()
+		var value interface{}
+		if err := d.Decode(&value); err != nil {
+			return err
+		}
+		v.Value = value
+	}
+	return nil
+}
+
+// Validate - This method will check that the variable type is in the
+// vocabulary and that the value, if present, matches the declared type. A
+// variable without a value, or with an empty string value, is valid since the
+// value may be set when the playbook is executed.
+func (v *Variables) Validate() error {
+	if !IsVocabValueValid(v.ObjectType, GetVariableTypesVocab()) {
+		return fmt.Errorf("the variable type %s is not valid", v.ObjectType)
+	}
+
+	if v.Value == nil {
+		return nil
+	}
+	if s, ok := v.Value.(string); ok && s == "" {
+		return nil
+	}
+
+	var err error
+	switch v.ObjectType {
+	case "bool":
+		_, err = v.AsBool()
+	case "dictionary":
+		_, err = v.AsMap()
+	case "float":
+		_, err = v.AsFloat()
+	case "integer":
+		var i int64
+		i, err = v.AsInt()
+		if err == nil && (i < math.MinInt32 || i > math.MaxInt32) {
+			err = errors.New("the value is out of range for an integer")
+		}
+	case "long":
+		_, err = v.AsInt()
+	case "hexstring":
+		err = checkStringFormat(v.Value, isHexString)
+	case "ipv4-addr":
+		err = checkStringFormat(v.Value, func(s string) bool { return isIPAddress(s, 4) })
+	case "ipv6-addr":
+		err = checkStringFormat(v.Value, func(s string) bool { return isIPAddress(s, 6) })
+	case "mac-addr":
+		_, err = v.AsMAC()
+	case "hash":
+		err = checkStringFormat(v.Value, func(s string) bool {
+			switch len(s) {
+			case 32, 40, 56, 64, 96, 128:
+				return isHexString(s)
+			}
+			return false
+		})
+	case "md5-hash":
+		err = checkStringFormat(v.Value, func(s string) bool { return len(s) == 32 && isHexString(s) })
+	case "sha256-hash":
+		err = checkStringFormat(v.Value, func(s string) bool { return len(s) == 64 && isHexString(s) })
+	case "string":
+		_, err = v.AsString()
+	case "uri":
+		_, err = v.AsURI()
+	case "uuid":
+		err = checkStringFormat(v.Value, func(s string) bool {
+			_, e := uuid.Parse(s)
+			return e == nil
+		})
+	}
+
+	if err != nil {
+		return fmt.Errorf("the variable value is not a valid %s: %s", v.ObjectType, err)
+	}
+	return nil
+}
+
+// AsString - This method returns the value as a string. Only string values
+// can be returned.
+func (v *Variables) AsString() (string, error) {
+	s, ok := v.Value.(string)
+	if !ok {
+		return "", fmt.Errorf("the value of type %T is not a string", v.Value)
+	}
+	return s, nil
+}
+
+// AsBool - This method returns the value as a bool. Both JSON booleans and
+// strings like "true" and "false" are accepted.
+func (v *Variables) AsBool() (bool, error) {
+	switch value := v.Value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		return strconv.ParseBool(value)
+	}
+	return false, fmt.Errorf("the value of type %T is not a bool", v.Value)
+}
+
+// AsInt - This method returns the value as an int64. Go integer types,
+// json.Number, floats without a fractional part, and numeric strings are
+// accepted.
+func (v *Variables) AsInt() (int64, error) {
+	switch value := v.Value.(type) {
+	case json.Number:
+		return strconv.ParseInt(value.String(), 10, 64)
+	case string:
+		return strconv.ParseInt(value, 10, 64)
+	case float32:
+		return floatToInt(float64(value))
+	case float64:
+		return floatToInt(value)
+	}
+
+	rv := reflect.ValueOf(v.Value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, errors.New("the value is out of range for a long")
+		}
+		return int64(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("the value of type %T is not an integer", v.Value)
+}
+
+// AsFloat - This method returns the value as a float64. Go numeric types,
+// json.Number, and numeric strings are accepted.
+func (v *Variables) AsFloat() (float64, error) {
+	switch value := v.Value.(type) {
+	case json.Number:
+		return value.Float64()
+	case string:
+		return strconv.ParseFloat(value, 64)
+	}
+
+	rv := reflect.ValueOf(v.Value)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("the value of type %T is not a number", v.Value)
+}
+
+// AsIP - This method returns the value as a net.IP. The value can be a single
+// address or an address in CIDR notation, in which case the address part is
+// returned.
+func (v *Variables) AsIP() (net.IP, error) {
+	s, err := v.AsString()
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(s); ip != nil {
+		return ip, nil
+	}
+	ip, _, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("the value %s is not an IP address", s)
+	}
+	return ip, nil
+}
+
+// AsIPNet - This method returns the value as a net.IPNet. A single address is
+// returned as a host network, for example a /32 for IPv4.
+func (v *Variables) AsIPNet() (*net.IPNet, error) {
+	s, err := v.AsString()
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 128
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+			bits = 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("the value %s is not an IP address or network", s)
+	}
+	return ipnet, nil
+}
+
+// AsMAC - This method returns the value as a net.HardwareAddr. The colon,
+// hyphen, and dot separated formats are accepted for EUI-48 and EUI-64
+// addresses.
+func (v *Variables) AsMAC() (net.HardwareAddr, error) {
+	s, err := v.AsString()
+	if err != nil {
+		return nil, err
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(mac) != 6 && len(mac) != 8 {
+		return nil, fmt.Errorf("the value %s is not an EUI-48 or EUI-64 address", s)
+	}
+	return mac, nil
+}
+
+// AsMap - This method returns the value as a map[string]interface{}. Any Go
+// map with string keys is accepted.
+func (v *Variables) AsMap() (map[string]interface{}, error) {
+	if m, ok := v.Value.(map[string]interface{}); ok {
+		return m, nil
+	}
+
+	rv := reflect.ValueOf(v.Value)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("the value of type %T is not a dictionary", v.Value)
+	}
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m, nil
+}
+
+// AsURI - This method returns the value as a *url.URL. The value must be an
+// absolute URI with a scheme.
+func (v *Variables) AsURI() (*url.URL, error) {
+	s, err := v.AsString()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("the value %s is not an absolute uri", s)
+	}
+	return u, nil
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// checkStringFormat - This function will check that a value is a string and
+// that it satisfies the format function.
+func checkStringFormat(value interface{}, format func(string) bool) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("the value of type %T is not a string", value)
+	}
+	if !format(s) {
+		return fmt.Errorf("the value %s is not correctly formatted", s)
+	}
+	return nil
+}
+
+// isHexString - This function will return true if the string is made up of an
+// even number of hexadecimal characters.
+func isHexString(s string) bool {
+	r := regexp.MustCompile(`^([a-fA-F0-9]{2})+$`)
+	return r.MatchString(s)
+}
+
+// isIPAddress - This function will return true if the string is an IP address
+// or an IP network in CIDR notation of the given IP version.
+func isIPAddress(s string, version int) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		var err error
+		ip, _, err = net.ParseCIDR(s)
+		if err != nil {
+			return false
+		}
+	}
+
+	if version == 4 {
+		return ip.To4() != nil
+	}
+	return ip.To4() == nil
+}
+
+// floatToInt - This function will convert a float to an int64 if it does not
+// have a fractional part.
+func floatToInt(f float64) (int64, error) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+		return 0, fmt.Errorf("the value %v is not an integer", f)
+	}
+	return int64(f), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestVariablesValidate - This will test the Validate() method against each of
+// the variable types
+func TestVariablesValidate(t *testing.T) {
+	tests := []struct {
+		objType string
+		value   interface{}
+		valid   bool
+	}{
+		{"string", "foo", true},
+		{"string", 10, false},
+		{"bool", true, true},
+		{"bool", "false", true},
+		{"bool", "nope", false},
+		{"integer", 42, true},
+		{"integer", json.Number("42"), true},
+		{"integer", 4.5, false},
+		{"integer", int64(1) << 40, false},
+		{"long", int64(1) << 40, true},
+		{"float", 4.5, true},
+		{"float", "4.5", true},
+		{"float", "four", false},
+		{"dictionary", map[string]interface{}{"a": 1}, true},
+		{"dictionary", map[string]string{"a": "b"}, true},
+		{"dictionary", []string{"a"}, false},
+		{"hexstring", "deadBEEF", true},
+		{"hexstring", "abc", false},
+		{"ipv4-addr", "10.0.0.1", true},
+		{"ipv4-addr", "10.0.0.0/8", true},
+		{"ipv4-addr", "10.0.0.256", false},
+		{"ipv4-addr", "2001:db8::1", false},
+		{"ipv6-addr", "2001:db8::1", true},
+		{"ipv6-addr", "2001:db8::/32", true},
+		{"ipv6-addr", "10.0.0.1", false},
+		{"mac-addr", "00:1a:2b:3c:4d:5e", true},
+		{"mac-addr", "00-1A-2B-3C-4D-5E", true},
+		{"mac-addr", "001a.2b3c.4d5e", true},
+		{"mac-addr", "00:1a:2b", false},
+		{"md5-hash", "d41d8cd98f00b204e9800998ecf8427e", true},
+		{"md5-hash", "d41d8cd98f00b204e9800998ecf8427", false},
+		{"sha256-hash", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", true},
+		{"sha256-hash", "d41d8cd98f00b204e9800998ecf8427e", false},
+		{"hash", "da39a3ee5e6b4b0d3255bfef95601890afd80709", true},
+		{"hash", "da39a3ee5e6b4b0d3255bfef95601890afd8070", false},
+		{"uri", "https://example.com/path", true},
+		{"uri", "/relative/path", false},
+		{"uuid", "61a6c41e-6efc-4516-a242-dfbc5c89d562", true},
+		{"uuid", "61a6c41e-6efc-4516-a242", false},
+		{"foo", "bar", false},
+		{"ipv4-addr", nil, true},
+	}
+
+	for i, test := range tests {
+		v := Variables{ObjectType: test.objType, Value: test.value}
+		err := v.Validate()
+		if test.valid && err != nil {
+			t.Errorf("1.%d %s value %v was reported as invalid: %s", i, test.objType, test.value, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("1.%d %s value %v was reported as valid", i, test.objType, test.value)
+		}
+	}
+}
+
+// TestVariablesRoundTrip - This will test that any JSON value round trips and
+// that large numbers keep their precision
+func TestVariablesRoundTrip(t *testing.T) {
+	data := []byte(`{"a":{"type":"long","value":9007199254740993},"b":{"type":"dictionary","value":{"x":[1,"y"]}},"c":{"type":"ipv4-addr","value":"1.2.3.4/32"}}`)
+
+	var m map[string]Variables
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("2.1 unable to decode variables: %s", err)
+	}
+
+	a := m["a"]
+	if i, err := a.AsInt(); err != nil || i != 9007199254740993 {
+		t.Errorf("2.2 AsInt returned %d and %v", i, err)
+	}
+
+	b := m["b"]
+	if d, err := b.AsMap(); err != nil || len(d) != 1 {
+		t.Errorf("2.3 AsMap returned %v and %v", d, err)
+	}
+
+	c := m["c"]
+	if ip, err := c.AsIP(); err != nil || ip.String() != "1.2.3.4" {
+		t.Errorf("2.4 AsIP returned %v and %v", ip, err)
+	}
+
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("2.5 unable to encode variables: %s", err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("2.6 round trip was not lossless\nExpected: %s\nHave: %s", data, out)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode - This function will decode a single workflow step into the concrete
+// step type identified by its type property and return it as a StepObject.
+// The id value is the dictionary key that the step was stored under in the
+// playbook workflow and it is restored into the ID property of the step.
+func Decode(id string, data []byte) (StepObject, error) {
+	var common CommonProperties
+	if err := json.Unmarshal(data, &common); err != nil {
+		return nil, err
+	}
+
+	var w StepObject
+	switch common.ObjectType {
+	case "start":
+		w = new(StartStep)
+	case "end":
+		w = new(EndStep)
+	case "action":
+		w = new(ActionStep)
+	case "playbook-action":
+		w = new(PlaybookActionStep)
+	case "parallel":
+		w = new(ParallelStep)
+	case "if-condition":
+		w = new(IfStep)
+	case "while-condition":
+		w = new(WhileStep)
+	case "switch-condition":
+		w = new(SwitchStep)
+	default:
+		return nil, fmt.Errorf("the workflow step %s has a type of \"%s\" that is not supported", id, common.ObjectType)
+	}
+
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, err
+	}
+	w.SetID(id)
+
+	return w, nil
+}
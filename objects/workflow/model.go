@@ -5,38 +5,45 @@
 
 package workflow
 
-import "github.com/openplaybooks/libcacao/objects"
+import (
+	"github.com/openplaybooks/libcacao/objects"
+	"github.com/openplaybooks/libcacao/objects/extensions"
+)
 
 // ----------------------------------------------------------------------
 // Define Object Model
 // ----------------------------------------------------------------------
 
 // StepObject - This interface defines a workflow step object. I needed to add
-// the ClearID() function to the interface to make sure I could call it on
-// an object that is defined as fullfilling this interface.
+// the SetID() and ClearID() functions to the interface to make sure I could
+// call them on an object that is defined as fullfilling this interface.
 type StepObject interface {
 	GetCommon() CommonProperties
+	SetID(id string)
 	ClearID()
+	Valid(debug bool) (bool, int, []string)
+	Check(debug bool) []objects.Finding
 }
 
 // CommonProperties - Each workflow step contains some base properties that are
 // common across all steps. These common properties are defined in the following
-// table. The ID property here is just to help make processing easier, it will
-// be removed when it is added to the playbook.
+// table. The ID property here is just to help make processing easier, it is
+// never serialized since the specification only has the ID at the dictionary
+// level.
 type CommonProperties struct {
 	ObjectType         string                       `json:"type,omitempty"`
-	ID                 string                       `json:"id,omitempty"`
+	ID                 string                       `json:"-"`
 	Name               string                       `json:"name,omitempty"`
 	Description        string                       `json:"description,omitempty"`
 	ExternalReferences []objects.ExternalReference  `json:"external_references,omitempty"`
 	Delay              int                          `json:"delay,omitempty"`
 	Timeout            int                          `json:"timeout,omitempty"`
-	StepVariables      map[string]objects.Variables `json:"playbook_variables,omitempty"`
+	StepVariables      map[string]objects.Variables `json:"step_variables,omitempty"`
 	Owner              string                       `json:"owner,omitempty"`
 	OnCompletion       string                       `json:"on_completion,omitempty"`
 	OnSuccess          string                       `json:"on_success,omitempty"`
 	OnFailure          string                       `json:"on_failure,omitempty"`
-	//StepExtensions
+	StepExtensions     extensions.Extensions        `json:"step_extensions,omitempty"`
 }
 
 // StartStep - This type implmenets the CACAO 2.0 workflow start step and
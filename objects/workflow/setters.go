@@ -105,7 +105,7 @@ func (w *IfStep) AddOnTrue(values interface{}) error {
 // property. Each entry represents one or more identifiers to be processed if
 // the condition returns "false".
 func (w *IfStep) AddOnFalse(values interface{}) error {
-	return objects.AddValuesToList(&w.OnTrue, values)
+	return objects.AddValuesToList(&w.OnFalse, values)
 }
 
 // ----------------------------------------------------------------------
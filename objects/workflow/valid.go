@@ -0,0 +1,445 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package workflow
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openplaybooks/libcacao/objects"
+)
+
+// ----------------------------------------------------------------------
+// Public Methods
+// ----------------------------------------------------------------------
+
+// Valid - This method checks a start step, without on_success or on_failure.
+func (w *StartStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the start step checks as findings.
+func (w *StartStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks an end step, which must not name a next step.
+func (w *EndStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the end step checks as findings.
+func (w *EndStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks an action step and each of its commands.
+func (w *ActionStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the action step checks as findings.
+func (w *ActionStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks a playbook action step and its playbook_id.
+func (w *PlaybookActionStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the playbook action step checks as findings.
+func (w *PlaybookActionStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks a parallel step and its next_steps.
+func (w *ParallelStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the parallel step checks as findings.
+func (w *ParallelStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks an if condition step, condition and on_true.
+func (w *IfStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the if condition step checks as findings.
+func (w *IfStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks a while condition step, condition and on_true.
+func (w *WhileStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the while condition step checks as findings.
+func (w *WhileStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks a switch condition step, switch and cases.
+func (w *SwitchStep) Valid(debug bool) (bool, int, []string) {
+	return w.validate(debug).Valid()
+}
+
+// Check - This method returns the switch condition step checks as findings.
+func (w *SwitchStep) Check(debug bool) []objects.Finding {
+	return w.validate(debug).Findings
+}
+
+// Valid - This method checks a command data object on its own.
+func (c *CommandData) Valid(debug bool) (bool, int, []string) {
+	return c.validate(debug).Valid()
+}
+
+// Check - This method returns the command checks as findings.
+func (c *CommandData) Check(debug bool) []objects.Finding {
+	return c.validate(debug).Findings
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// checkRequired - This function will check that a required string property is
+// populated.
+func checkRequired(r *objects.Results, propertyName, value string) {
+	r.At("workflow.step."+propertyName, propertyName)
+	if value == "" {
+		r.RequiredButMissing(propertyName)
+		return
+	}
+	r.RequiredAndFound(propertyName)
+}
+
+// checkRequiredList - This function will check that a required list property
+// has at least one entry.
+func checkRequiredList(r *objects.Results, propertyName string, values []string) {
+	r.At("workflow.step."+propertyName, propertyName)
+	if len(values) == 0 {
+		r.RequiredButMissing(propertyName)
+		return
+	}
+	r.RequiredAndFound(propertyName)
+}
+
+// checkNotUsed - This function will check that a property that this step type
+// MUST NOT use is empty.
+func checkNotUsed(r *objects.Results, propertyName, value string) {
+	if value != "" {
+		r.At("workflow.step."+propertyName, propertyName)
+		str := fmt.Sprintf("-- the %s property MUST NOT be used on this type of step", propertyName)
+		r.LogProblem(str)
+	}
+}
+
+// isIDValid - This function will take in an CACAO ID and an object type and
+// check to see if it is a valid identifier for that object type.
+func isIDValid(id, objType string) bool {
+	idparts := strings.Split(id, "--")
+
+	if len(idparts) != 2 {
+		return false
+	}
+
+	// First check to see if the object type is valid, if not return false.
+	if idparts[0] != objType {
+		// Short circuit if the object type part is wrong
+		return false
+	}
+
+	// If the type is valid, then check to see if the ID is a UUID, if not return
+	// false.
+	return objects.IsUUIDValid(idparts[1])
+}
+
+// ----------------------------------------------------------------------
+// Private Methods
+// ----------------------------------------------------------------------
+
+// These methods will run the checks for each type of step and return the
+// results.
+
+func (w *StartStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "start")
+	checkNotUsed(r, "on_success", w.OnSuccess)
+	checkNotUsed(r, "on_failure", w.OnFailure)
+	return r
+}
+
+func (w *EndStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "end")
+	checkNotUsed(r, "on_completion", w.OnCompletion)
+	checkNotUsed(r, "on_success", w.OnSuccess)
+	checkNotUsed(r, "on_failure", w.OnFailure)
+	return r
+}
+
+func (w *ActionStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "action")
+	w.checkCommands(r)
+	return r
+}
+
+func (w *PlaybookActionStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "playbook-action")
+	w.checkPlaybookID(r)
+	return r
+}
+
+func (w *ParallelStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "parallel")
+	w.checkNextSteps(r)
+	return r
+}
+
+func (w *IfStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "if-condition")
+	checkRequired(r, "condition", w.Condition)
+	checkRequiredList(r, "on_true", w.OnTrue)
+	return r
+}
+
+func (w *WhileStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "while-condition")
+	checkRequired(r, "condition", w.Condition)
+	checkRequiredList(r, "on_true", w.OnTrue)
+	return r
+}
+
+func (w *SwitchStep) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	w.checkCommon(r, "switch-condition")
+	checkRequired(r, "switch", w.Switch)
+	w.checkCases(r)
+	return r
+}
+
+func (c *CommandData) validate(debug bool) *objects.Results {
+	r := &objects.Results{Debug: debug}
+	c.check(r)
+	return r
+}
+
+// Each of these methods will check a specific property. It is done this way
+// to reduce the complexity of the main valid() function. This way all of the
+// checks for each property are self contained in their own function.
+
+// checkCommon - This method will check the properties that are common to all
+// workflow steps.
+func (w *CommonProperties) checkCommon(r *objects.Results, objType string) {
+	w.checkObjectType(r, objType)
+	w.checkID(r, objType)
+	w.checkExternalReferences(r)
+	w.checkDelayAndTimeout(r)
+	w.checkStepVariables(r)
+	w.checkOnCompletion(r)
+}
+
+func (w *CommonProperties) checkObjectType(r *objects.Results, objType string) {
+	r.At("workflow.step.type", "type")
+
+	if w.ObjectType == "" {
+		r.RequiredButMissing("type")
+		return
+	}
+
+	r.RequiredAndFound("type")
+	if w.ObjectType != objType {
+		str := fmt.Sprintf("-- the type property does not contain a value of %s", objType)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the type property contains a valid type value of \"%s\"", w.ObjectType)
+		r.LogValid(str)
+	}
+}
+
+// checkID - The ID is not serialized, since it is the key in the workflow
+// dictionary, so it is only checked when it has been set on the object.
+func (w *CommonProperties) checkID(r *objects.Results, objType string) {
+	if w.ID == "" {
+		return
+	}
+
+	r.At("workflow.step.id")
+	if valid := isIDValid(w.ID, objType); valid == false {
+		str := fmt.Sprintf("-- the id \"%s\" does not start with the step type %s or is not a valid identifier", w.ID, objType)
+		r.LogProblem(str)
+	} else {
+		str := fmt.Sprintf("++ the id property contains a valid identifier value of \"%s\"", w.ID)
+		r.LogValid(str)
+	}
+}
+
+func (w *CommonProperties) checkExternalReferences(r *objects.Results) {
+	for i := range w.ExternalReferences {
+		r.At("workflow.step.external_references", "external_references", strconv.Itoa(i), "name")
+		if w.ExternalReferences[i].Name == "" {
+			r.LogProblem("-- the name property in an external reference is required but missing")
+		} else {
+			r.LogValid("++ the name property in an external reference is required and is present")
+		}
+	}
+}
+
+func (w *CommonProperties) checkDelayAndTimeout(r *objects.Results) {
+	if w.Delay < 0 {
+		r.At("workflow.step.delay", "delay")
+		r.LogProblem("-- the delay property must not be a negative integer")
+	}
+	if w.Timeout < 0 {
+		r.At("workflow.step.timeout", "timeout")
+		r.LogProblem("-- the timeout property must not be a negative integer")
+	}
+}
+
+func (w *CommonProperties) checkStepVariables(r *objects.Results) {
+	names := make([]string, 0, len(w.StepVariables))
+	for k := range w.StepVariables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		v := w.StepVariables[k]
+		r.At("workflow.step.step_variables", "step_variables", k, "value")
+		if err := v.Validate(); err != nil {
+			str := fmt.Sprintf("-- the step variable %s is not valid: %s", k, err)
+			r.LogProblem(str)
+		} else {
+			str := fmt.Sprintf("++ the step variable %s contains a valid %s value", k, v.ObjectType)
+			r.LogValid(str)
+		}
+	}
+}
+
+// checkOnCompletion - The on_completion property MUST NOT be used if either
+// the on_success or on_failure property is used.
+func (w *CommonProperties) checkOnCompletion(r *objects.Results) {
+	if w.OnCompletion != "" && (w.OnSuccess != "" || w.OnFailure != "") {
+		r.At("workflow.step.on_completion", "on_completion")
+		r.LogProblem("-- the on_completion property MUST NOT be used with the on_success or on_failure properties")
+	}
+}
+
+func (w *ActionStep) checkCommands(r *objects.Results) {
+	r.At("workflow.step.commands", "commands")
+
+	if len(w.Commands) == 0 {
+		r.RequiredButMissing("commands")
+		return
+	}
+
+	r.RequiredAndFound("commands")
+	for i := range w.Commands {
+		w.Commands[i].check(r, "commands", strconv.Itoa(i))
+	}
+}
+
+// check - This method will check a command. The tokens are the JSON Pointer
+// to the command, so that the findings point to its properties.
+func (c *CommandData) check(r *objects.Results, tokens ...string) {
+	r.At("workflow.command.type", append(tokens, "type")...)
+	if c.ObjectType == "" {
+		r.LogProblem("-- the type property in a command is required but missing")
+	} else if objects.IsVocabValueValid(c.ObjectType, GetCommandDataTypesVocab()) {
+		str := fmt.Sprintf("++ the type property in a command contains a valid type value of \"%s\"", c.ObjectType)
+		r.LogValid(str)
+	} else {
+		str := fmt.Sprintf("-- the type property in a command contains a value of \"%s\" that is not in the vocabulary", c.ObjectType)
+		r.LogProblem(str)
+	}
+
+	r.At("workflow.command.command", append(tokens, "command")...)
+	switch {
+	case c.Command == "" && c.CommandB64 == "":
+		r.LogProblem("-- either the command property or the command_b64 property in a command is required but missing")
+	case c.Command != "" && c.CommandB64 != "":
+		r.LogProblem("-- the command property and the command_b64 property in a command MUST NOT both be used")
+	case c.CommandB64 != "":
+		r.At("workflow.command.command_b64", append(tokens, "command_b64")...)
+		if _, err := base64.StdEncoding.DecodeString(c.CommandB64); err != nil {
+			r.LogProblem("-- the command_b64 property in a command is not base64 encoded")
+		} else {
+			r.LogValid("++ the command_b64 property in a command is base64 encoded")
+		}
+	default:
+		r.LogValid("++ the command property in a command is required and is found")
+	}
+}
+
+func (w *PlaybookActionStep) checkPlaybookID(r *objects.Results) {
+	r.At("workflow.step.playbook_id", "playbook_id")
+
+	if w.PlaybookID == "" {
+		r.RequiredButMissing("playbook_id")
+		return
+	}
+
+	r.RequiredAndFound("playbook_id")
+	if valid := isIDValid(w.PlaybookID, "playbook"); valid == false {
+		r.LogProblem("-- the playbook_id property does not contain a valid playbook identifier")
+	} else {
+		str := fmt.Sprintf("++ the playbook_id property contains a valid identifier value of \"%s\"", w.PlaybookID)
+		r.LogValid(str)
+	}
+}
+
+func (w *ParallelStep) checkNextSteps(r *objects.Results) {
+	r.At("workflow.step.next_steps", "next_steps")
+
+	if len(w.NextSteps) == 0 {
+		r.RequiredButMissing("next_steps")
+		return
+	}
+
+	r.RequiredAndFound("next_steps")
+	if len(w.NextSteps) < 2 {
+		r.LogProblem("-- the next_steps property must contain two or more steps")
+	} else {
+		r.LogValid("++ the next_steps property contains two or more steps")
+	}
+}
+
+func (w *SwitchStep) checkCases(r *objects.Results) {
+	r.At("workflow.step.cases", "cases")
+
+	if len(w.Cases) == 0 {
+		r.RequiredButMissing("cases")
+		return
+	}
+
+	r.RequiredAndFound("cases")
+	cases := make([]string, 0, len(w.Cases))
+	for k := range w.Cases {
+		cases = append(cases, k)
+	}
+	sort.Strings(cases)
+
+	for _, k := range cases {
+		if len(w.Cases[k]) == 0 {
+			r.At("workflow.step.cases", "cases", k)
+			str := fmt.Sprintf("-- the case %s in the cases property does not reference any steps", k)
+			r.LogProblem(str)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package workflow
+
+import (
+	"testing"
+)
+
+// TestStartAndEndValid - This will test the MUST NOT rules on the start and
+// end steps
+func TestStartAndEndValid(t *testing.T) {
+	s, _ := NewStartStep()
+	s.OnCompletion = "action--7f40f9d7-de39-4027-ab97-15035beff2ff"
+	if valid, count, details := s.Valid(false); !valid || count != 0 {
+		t.Errorf("1.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	s.OnCompletion = ""
+	s.OnSuccess = "action--7f40f9d7-de39-4027-ab97-15035beff2ff"
+	s.OnFailure = "end--6b23c237-ade8-4d00-9aa1-75999738d557"
+	if valid, count, details := s.Valid(false); valid || count != 2 {
+		t.Errorf("1.2 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	e, _ := NewEndStep()
+	if valid, count, details := e.Valid(false); !valid || count != 0 {
+		t.Errorf("1.3 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	e.OnCompletion = "start--07bea005-4a36-4a77-bd1f-79a6e4682a13"
+	if valid, count, details := e.Valid(false); valid || count != 1 {
+		t.Errorf("1.4 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	// Check an ID that does not match the type
+	e.OnCompletion = ""
+	e.ID = "start--07bea005-4a36-4a77-bd1f-79a6e4682a13"
+	if valid, count, details := e.Valid(false); valid || count != 1 {
+		t.Errorf("1.5 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+}
+
+// TestActionValid - This will test the action step and command data rules
+func TestActionValid(t *testing.T) {
+	a, _ := NewActionStep()
+	if valid, count, details := a.Valid(false); valid || count != 1 {
+		t.Errorf("2.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	c, _ := a.NewCommand()
+	if valid, count, details := a.Valid(false); valid || count != 2 {
+		t.Errorf("2.2 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	c.ObjectType = "foo"
+	c.Command = "ls -la"
+	if valid, count, details := a.Valid(false); valid || count != 1 {
+		t.Errorf("2.3 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	c.ObjectType = "bash"
+	if valid, count, details := a.Valid(false); !valid || count != 0 {
+		t.Errorf("2.4 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	c.CommandB64 = "bHMgLWxh"
+	if valid, count, details := c.Valid(false); valid || count != 1 {
+		t.Errorf("2.5 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	c.Command = ""
+	if valid, count, details := c.Valid(false); !valid || count != 0 {
+		t.Errorf("2.6 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	a.OnCompletion = "end--6b23c237-ade8-4d00-9aa1-75999738d557"
+	a.OnFailure = "end--6b23c237-ade8-4d00-9aa1-75999738d557"
+	if valid, count, details := a.Valid(false); valid || count != 1 {
+		t.Errorf("2.7 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+}
+
+// TestLogicStepsValid - This will test the parallel, if, while, switch, and
+// playbook action steps
+func TestLogicStepsValid(t *testing.T) {
+	p, _ := NewParallelStep()
+	p.AddNextSteps("action--7f40f9d7-de39-4027-ab97-15035beff2ff")
+	if valid, count, details := p.Valid(false); valid || count != 1 {
+		t.Errorf("3.1 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	p.AddNextSteps("action--d1b2e4a6-5c3f-4f0e-9a7d-8b6c5d4e3f2a")
+	if valid, count, details := p.Valid(false); !valid || count != 0 {
+		t.Errorf("3.2 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	i, _ := NewIfStep()
+	if valid, count, details := i.Valid(false); valid || count != 2 {
+		t.Errorf("3.3 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	i.Condition = "__x__:value = 1"
+	i.AddOnTrue("end--6b23c237-ade8-4d00-9aa1-75999738d557")
+	if valid, count, details := i.Valid(false); !valid || count != 0 {
+		t.Errorf("3.4 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	w, _ := NewWhileStep()
+	w.Condition = "__x__:value = 1"
+	if valid, count, details := w.Valid(false); valid || count != 1 {
+		t.Errorf("3.5 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	s, _ := NewSwitchStep()
+	s.Switch = "__x__:value"
+	if valid, count, details := s.Valid(false); valid || count != 1 {
+		t.Errorf("3.6 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	s.AddCase("1", "end--6b23c237-ade8-4d00-9aa1-75999738d557")
+	if valid, count, details := s.Valid(false); !valid || count != 0 {
+		t.Errorf("3.7 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+
+	pa, _ := NewPlaybookActionStep()
+	pa.PlaybookID = "foo"
+	if valid, count, details := pa.Valid(false); valid || count != 1 {
+		t.Errorf("3.8 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+	pa.PlaybookID = "playbook--61a6c41e-6efc-4516-a242-dfbc5c89d562"
+	if valid, count, details := pa.Valid(false); !valid || count != 0 {
+		t.Errorf("3.9 Valid returned %t with errors %d and results %s which is invalid", valid, count, details)
+	}
+}
@@ -33,16 +33,24 @@ func (w *CommonProperties) GetID() string {
 	return w.ID
 }
 
+// SetID - This method will set the ID of the object to the value passed in.
+// This is used when a step is decoded from the workflow dictionary and the key
+// needs to be restored.
+func (w *CommonProperties) SetID(id string) {
+	w.ID = id
+}
+
 // ClearID - This method will clear the ID from the object
 func (w *CommonProperties) ClearID() {
 	w.ID = ""
 }
 
 // AddVariable - This method takes in a Variable object and adds it to the
-// workflow step object as a local step variable.
+// workflow step object as a local step variable. The variable is refused if its
+// value does not match its type.
 func (w *CommonProperties) AddVariable(v objects.Variables) error {
-	if !objects.IsVocabValueValid(v.ObjectType, objects.GetVariableTypesVocab()) {
-		return fmt.Errorf("the variable type %s is not valid", v.ObjectType)
+	if err := v.Validate(); err != nil {
+		return err
 	}
 
 	if w.StepVariables == nil {
@@ -54,3 +62,10 @@ func (w *CommonProperties) AddVariable(v objects.Variables) error {
 	w.StepVariables[name] = v
 	return nil
 }
+
+// AddExtension - This method takes in the ID of an extension definition and a
+// value that represents the extension and adds it to the step_extensions
+// property.
+func (w *CommonProperties) AddExtension(id string, v interface{}) error {
+	return w.StepExtensions.Add(id, v)
+}
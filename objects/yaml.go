@@ -0,0 +1,425 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes limits how many nodes are written when aliases are expanded,
+// and how many pairs are read from merge keys, so a small document with nested
+// aliases or merge keys can not expand without bound.
+const maxYAMLNodes = 1000000
+
+// ----------------------------------------------------------------------
+// Public Functions
+// ----------------------------------------------------------------------
+
+// YAMLToJSON - This function will convert a YAML document into JSON. Anchors,
+// aliases and merge keys (<<) are expanded, and the keys of each mapping are
+// written in the order they appear in the YAML. Timestamps are written as
+// strings. Errors in the YAML are returned as a *DecodeError with the line
+// and column, except for syntax errors which say the line themselves.
+func YAMLToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errors.New("the YAML document is empty")
+	}
+
+	w := &yamlWriter{}
+	if err := w.write(doc.Content[0]); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// JSONToYAML - This function will convert JSON into a YAML document with an
+// indent of two spaces. The keys of each object are written in the order they
+// appear in the JSON, so converting the YAML back gives the same JSON. Strings
+// with more than one line are written as literal blocks.
+func JSONToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := jsonToNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, errors.New("the JSON contains more than one value")
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// YAMLPointerPosition - This function will return the line and column in the
+// YAML document of the property that the JSON Pointer (RFC 6901) refers to.
+// For a property of a mapping this is where its key starts, and for an entry
+// in a sequence where the entry starts. Aliases and merge keys are followed.
+// If the pointer is not found, ok is false.
+func YAMLPointerPosition(data []byte, pointer string) (line, column int, ok bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0, 0, false
+	}
+	return yamlPointerPosition(doc.Content[0], pointer)
+}
+
+// ----------------------------------------------------------------------
+// Private Functions
+// ----------------------------------------------------------------------
+
+// yamlPointerPosition - This function will return the line and column of the
+// node that the JSON Pointer refers to, starting at the root node.
+func yamlPointerPosition(root *yaml.Node, pointer string) (line, column int, ok bool) {
+	tokens, ok := splitPointer(pointer)
+	if !ok || root == nil {
+		return 0, 0, false
+	}
+
+	n, at := root, root
+	for _, t := range tokens {
+		n, at = yamlChild(resolveAlias(n), t, 0)
+		if n == nil {
+			return 0, 0, false
+		}
+	}
+	return at.Line, at.Column, true
+}
+
+// yamlChild - This function will return the value of the mapping key or
+// sequence index, and the node where it is written. Keys from merge keys are
+// used if the mapping does not set them itself.
+func yamlChild(n *yaml.Node, token string, depth int) (value, at *yaml.Node) {
+	if depth > 100 {
+		return nil, nil
+	}
+
+	switch n.Kind {
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(n.Content) {
+			return nil, nil
+		}
+		return n.Content[i], n.Content[i]
+
+	case yaml.MappingNode:
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Tag == "!!merge" {
+				merges = append(merges, v)
+			} else if k.Value == token {
+				return v, k
+			}
+		}
+		for _, m := range merges {
+			sources := []*yaml.Node{m}
+			if resolveAlias(m).Kind == yaml.SequenceNode {
+				sources = resolveAlias(m).Content
+			}
+			for _, src := range sources {
+				if value, at := yamlChild(resolveAlias(src), token, depth+1); value != nil {
+					return value, at
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// jsonToNode - This function will read the next JSON value from the decoder
+// and return it as a YAML node.
+func jsonToNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if v == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := jsonToNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		// Read the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if strings.Contains(v, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// ----------------------------------------------------------------------
+// Private Types and Methods
+// ----------------------------------------------------------------------
+
+// yamlWriter - This type writes YAML nodes as JSON and counts the nodes that
+// are written so alias expansion is bounded. The pairs of each merged mapping
+// are only worked out once, since the same anchor is often merged many times.
+type yamlWriter struct {
+	buf       bytes.Buffer
+	nodes     int
+	expanding map[*yaml.Node]bool
+	merged    map[*yaml.Node][]yamlPair
+}
+
+// yamlPair - This type is a key and value from a YAML mapping
+type yamlPair struct {
+	key   string
+	value *yaml.Node
+}
+
+func (w *yamlWriter) write(n *yaml.Node) error {
+	w.nodes++
+	if w.nodes > maxYAMLNodes {
+		return yamlError(n, "the YAML expands to more than %d nodes", maxYAMLNodes)
+	}
+
+	switch n.Kind {
+	case yaml.AliasNode:
+		// An anchor that contains an alias to itself would never end
+		if w.expanding == nil {
+			w.expanding = make(map[*yaml.Node]bool)
+		}
+		if w.expanding[n.Alias] {
+			return yamlError(n, "the anchor %s contains itself", n.Value)
+		}
+		w.expanding[n.Alias] = true
+		defer delete(w.expanding, n.Alias)
+		return w.write(n.Alias)
+
+	case yaml.MappingNode:
+		pairs, err := w.pairs(n)
+		if err != nil {
+			return err
+		}
+		w.buf.WriteByte('{')
+		for i, p := range pairs {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.writeString(p.key)
+			w.buf.WriteByte(':')
+			if err := w.write(p.value); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteByte('}')
+		return nil
+
+	case yaml.SequenceNode:
+		w.buf.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			if err := w.write(c); err != nil {
+				return err
+			}
+		}
+		w.buf.WriteByte(']')
+		return nil
+
+	case yaml.ScalarNode:
+		return w.writeScalar(n)
+	}
+	return yamlError(n, "unsupported YAML node")
+}
+
+// pairs - This method will return the keys and values of a mapping in order.
+// The keys from merge keys (<<) come where the merge key is, unless the
+// mapping sets them itself, and earlier merged mappings win over later ones.
+func (w *yamlWriter) pairs(n *yaml.Node) ([]yamlPair, error) {
+	explicit := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if k.Kind != yaml.ScalarNode {
+			return nil, yamlError(k, "mapping keys must be scalars")
+		}
+		if k.Tag == "!!merge" {
+			continue
+		}
+		if explicit[k.Value] {
+			return nil, yamlError(k, "the key %q is repeated", k.Value)
+		}
+		explicit[k.Value] = true
+	}
+
+	var pairs []yamlPair
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Tag != "!!merge" {
+			pairs = append(pairs, yamlPair{k.Value, v})
+			seen[k.Value] = true
+			continue
+		}
+
+		sources := []*yaml.Node{v}
+		if resolveAlias(v).Kind == yaml.SequenceNode {
+			sources = resolveAlias(v).Content
+		}
+		for _, src := range sources {
+			src = resolveAlias(src)
+			if src.Kind != yaml.MappingNode {
+				return nil, yamlError(src, "merge keys must refer to mappings")
+			}
+			if src == n || w.expanding[src] {
+				return nil, yamlError(k, "the mapping merges itself")
+			}
+			merged, err := w.mergedPairs(src)
+			if err != nil {
+				return nil, err
+			}
+			w.nodes += len(merged)
+			if w.nodes > maxYAMLNodes {
+				return nil, yamlError(k, "the YAML expands to more than %d nodes", maxYAMLNodes)
+			}
+			for _, p := range merged {
+				if !explicit[p.key] && !seen[p.key] {
+					pairs = append(pairs, p)
+					seen[p.key] = true
+				}
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// mergedPairs - This method will return the pairs of a mapping that is merged
+// into another, and keep track of it so a mapping can not merge itself. The
+// pairs are saved so the next merge of the same mapping does not repeat the
+// work.
+func (w *yamlWriter) mergedPairs(n *yaml.Node) ([]yamlPair, error) {
+	if pairs, found := w.merged[n]; found {
+		return pairs, nil
+	}
+	if w.expanding == nil {
+		w.expanding = make(map[*yaml.Node]bool)
+	}
+	w.expanding[n] = true
+	defer delete(w.expanding, n)
+
+	pairs, err := w.pairs(n)
+	if err != nil {
+		return nil, err
+	}
+	if w.merged == nil {
+		w.merged = make(map[*yaml.Node][]yamlPair)
+	}
+	w.merged[n] = pairs
+	return pairs, nil
+}
+
+// writeScalar - This method will write a scalar using the type that YAML
+// resolves it to. Timestamps and binary values are written as strings.
+func (w *yamlWriter) writeScalar(n *yaml.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		w.buf.WriteString("null")
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return err
+		}
+		w.buf.WriteString(strconv.FormatBool(b))
+	case "!!int":
+		var i int64
+		if err := n.Decode(&i); err == nil {
+			w.buf.WriteString(strconv.FormatInt(i, 10))
+			return nil
+		}
+		var u uint64
+		if err := n.Decode(&u); err != nil {
+			return err
+		}
+		w.buf.WriteString(strconv.FormatUint(u, 10))
+	case "!!float":
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return yamlError(n, "%s can not be written as JSON", n.Value)
+		}
+		w.buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	default:
+		w.writeString(n.Value)
+	}
+	return nil
+}
+
+// writeString - This method will write a JSON string
+func (w *yamlWriter) writeString(s string) {
+	enc := json.NewEncoder(&w.buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	// Encode adds a newline after the value
+	w.buf.Truncate(w.buf.Len() - 1)
+}
+
+// yamlError - This function will return a decode error at the position of the
+// node
+func yamlError(n *yaml.Node, format string, a ...interface{}) error {
+	return &DecodeError{Line: n.Line, Column: n.Column, Err: fmt.Errorf(format, a...)}
+}
+
+// resolveAlias - This function will follow an alias to the node it refers to
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Bret Jordan, All rights reserved.
+//
+// Use of this source code is governed by an Apache 2.0 license that can be
+// found in the LICENSE file in the root of the source tree.
+
+package objects
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestYAMLToJSON - This will test converting YAML with anchors, aliases, merge
+// keys and the different scalar types into JSON
+func TestYAMLToJSON(t *testing.T) {
+	data := []byte(`
+name: Playbook 1
+created: 2022-05-18T11:31:31.319Z
+count: 0x10
+ratio: 1.5
+enabled: yes
+strict: true
+nothing: ~
+quoted: "true"
+defaults: &defaults
+  type: manual
+  command: |
+    isolate host
+    notify <owner>
+steps:
+  - <<: *defaults
+    command: scan host
+  - *defaults
+`)
+	want := `{"name":"Playbook 1","created":"2022-05-18T11:31:31.319Z","count":16,"ratio":1.5,"enabled":"yes",` +
+		`"strict":true,"nothing":null,"quoted":"true",` +
+		`"defaults":{"type":"manual","command":"isolate host\nnotify <owner>\n"},` +
+		`"steps":[{"type":"manual","command":"scan host"},{"type":"manual","command":"isolate host\nnotify <owner>\n"}]}`
+
+	got, err := YAMLToJSON(data)
+	if err != nil {
+		t.Fatalf("1.1 YAMLToJSON returned an error: %s", err)
+	}
+	if string(got) != want {
+		t.Errorf("1.2 YAMLToJSON returned\n%s\ninstead of\n%s", got, want)
+	}
+
+	tests := []struct {
+		yaml string
+		err  string
+	}{
+		{"a: 1\na: 2\n", "line 2"},
+		{"a: &a\n  b: *a\n", "contains itself"},
+		{"a: &a\n  <<: *a\n", "merges itself"},
+		{"a: .inf\n", "can not be written as JSON"},
+		{"? [a]\n: 1\n", "keys must be scalars"},
+		{"", "empty"},
+	}
+	for i, test := range tests {
+		if _, err := YAMLToJSON([]byte(test.yaml)); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("1.%d YAMLToJSON returned %v instead of an error with %q", 3+i, err, test.err)
+		}
+	}
+}
+
+// TestJSONToYAML - This will test that JSON converted to YAML keeps the key
+// order and types and converts back to the same JSON
+func TestJSONToYAML(t *testing.T) {
+	data := `{"type":"playbook","id":"x","created":"2022-05-18T11:31:31.319Z","priority":1,"ratio":0.5,` +
+		`"yes":"yes","flag":false,"empty":"","none":null,"list":["a","10"],"command":"line 1\nline 2"}`
+
+	y, err := JSONToYAML([]byte(data))
+	if err != nil {
+		t.Fatalf("2.1 JSONToYAML returned an error: %s", err)
+	}
+	if !strings.HasPrefix(string(y), "type: playbook\nid: x\n") || !strings.Contains(string(y), "command: |-\n  line 1\n  line 2") {
+		t.Errorf("2.2 JSONToYAML returned\n%s", y)
+	}
+
+	back, err := YAMLToJSON(y)
+	if err != nil {
+		t.Fatalf("2.3 YAMLToJSON returned an error: %s", err)
+	}
+	if string(back) != data {
+		t.Errorf("2.4 the round trip returned\n%s\ninstead of\n%s", back, data)
+	}
+
+	if _, err := JSONToYAML([]byte(`{"a": 1} {}`)); err == nil {
+		t.Errorf("2.5 JSONToYAML accepted more than one value")
+	}
+}
+
+// TestYAMLMergeBomb - This will test that merge keys that refer to the same
+// anchors many times over do not take exponential time or memory
+func TestYAMLMergeBomb(t *testing.T) {
+	// Each level merges the level before it ten times, so without a limit
+	// working out the last mapping would take 10^40 merges
+	var b strings.Builder
+	b.WriteString("l0: &l0\n  k0: v\n")
+	for i := 1; i <= 40; i++ {
+		fmt.Fprintf(&b, "l%d: &l%d\n  k%d: v\n  <<: [%s]\n", i, i, i, strings.TrimSuffix(strings.Repeat(fmt.Sprintf("*l%d, ", i-1), 10), ", "))
+	}
+
+	got, err := YAMLToJSON([]byte(b.String()))
+	if err != nil {
+		t.Fatalf("3.1 YAMLToJSON returned an error: %s", err)
+	}
+	if !strings.Contains(string(got), `"l40":{"k40":"v","k39":"v"`) || !strings.HasSuffix(string(got), `"k0":"v"}}`) {
+		t.Errorf("3.2 YAMLToJSON did not merge the keys correctly %s", got)
+	}
+
+	// Every merged pair counts toward the limit
+	b.Reset()
+	b.WriteString("a: &a\n")
+	for i := 0; i < 1100; i++ {
+		fmt.Fprintf(&b, "  k%d: v\n", i)
+	}
+	b.WriteString("b:\n  <<: [*a" + strings.Repeat(", *a", 999) + "]\n")
+	if _, err := YAMLToJSON([]byte(b.String())); err == nil || !strings.Contains(err.Error(), "expands to more than") {
+		t.Errorf("3.3 YAMLToJSON returned %v instead of an error about the size", err)
+	}
+}